@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+func runCheckIn(args []string) error {
+	fs, cf := newFlagSet("checkin")
+	ticket := fs.Uint64("ticket", 0, "ticket number")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *ticket == 0 {
+		return usagef("checkin: --ticket required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.CheckIn(ctx, &pb.ReservationRequest{TicketNo: ticket})
+	if err != nil {
+		return err
+	}
+	return printTickets(cf.output, resp)
+}
+
+func runBoard(args []string) error {
+	fs, cf := newFlagSet("board")
+	ticket := fs.Uint64("ticket", 0, "ticket number")
+	passenger := fs.Uint("passenger", 1, "passenger to board, 1 for the lead passenger")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *ticket == 0 {
+		return usagef("board: --ticket required")
+	}
+	if *passenger == 0 {
+		return usagef("board: --passenger starts at 1")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.Board(ctx, &pb.BoardRequest{TicketNo: *ticket, Position: uint32(*passenger - 1)})
+	if err != nil {
+		return err
+	}
+	return printTickets(cf.output, resp)
+}
+
+func runManifest(args []string) error {
+	fs, cf := newFlagSet("manifest")
+	departure := fs.Uint64("departure", 0, "departure ID")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *departure == 0 {
+		return usagef("manifest: --departure required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.GetBoardingManifest(ctx, &pb.ManifestRequest{DepartureId: *departure})
+	if err != nil {
+		return err
+	}
+	return printManifest(cf.output, resp)
+}
+
+func printManifest(format string, m *pb.BoardingManifest) error {
+	if done, err := printStructured(os.Stdout, format, m); done {
+		return err
+	}
+	d := m.Departure
+	fmt.Printf("%s %s → %s, departs %s\n", d.Train, d.FromCode, d.ToCode, fmtTime(d.DepartsAt))
+	fmt.Printf("Check-in %s to %s, boarding from %s\n\n",
+		fmtTime(m.CheckInOpensAt), fmtTime(m.CheckInClosesAt), fmtTime(m.BoardingOpensAt))
+	if len(m.Passengers) == 0 {
+		fmt.Println("No passengers.")
+		return nil
+	}
+	counts := map[pb.BoardingManifest_State]int{}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COACH\tSEAT\tTICKET\tPASSENGER\tFROM\tTO\tSTATE")
+	for _, p := range m.Passengers {
+		counts[p.State]++
+		fmt.Fprintf(tw, "%s\t%d\t%d/%d\t%s\t%s\t%s\t%s\n", p.Section, p.Seat, p.TicketNo, p.Position+1, p.Name, p.FromCode, p.ToCode, p.State)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d passengers: %d booked, %d checked in, %d boarded, %d no-show\n", len(m.Passengers),
+		counts[pb.BoardingManifest_BOOKED], counts[pb.BoardingManifest_CHECKED_IN],
+		counts[pb.BoardingManifest_BOARDED], counts[pb.BoardingManifest_NO_SHOW])
+	return nil
+}
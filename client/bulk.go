@@ -0,0 +1,401 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// idempotencyMDKey must match the key the server reads from metadata.
+const idempotencyMDKey = "idempotency-key"
+
+// csvColumns is the column order for import and export files. Import also
+// accepts the columns in any order as long as the header names match.
+var csvColumns = []string{
+	"booking_ref", "ticket_no", "status", "departure_id", "from", "to", "price",
+	"first_name", "last_name", "email", "address", "section", "seat",
+}
+
+// booking is one ReserveTicket call assembled from the input file.
+type booking struct {
+	Ref  string // booking_ref from the file, or the line/index it came from
+	Line int
+	Req  *pb.ReservationRequest
+	Key  string
+	Errs []string
+}
+
+// importResult is one line of the import report.
+type importResult struct {
+	BookingRef     string `json:"booking_ref"`
+	Line           int    `json:"line"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Result         string `json:"result"`
+	TicketNo       uint64 `json:"ticket_no,omitempty"`
+	Seats          string `json:"seats,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+func runImport(args []string) error {
+	fs, cf := newFlagSet("import")
+	file := fs.String("file", "", "CSV or JSON file of bookings (required)")
+	format := fs.String("format", "", "input format: csv or json (default: from the file extension)")
+	concurrency := fs.Int("concurrency", 4, "number of bookings submitted in parallel")
+	keyPrefix := fs.String("key-prefix", "import", "prefix for generated idempotency keys; change it to book the same file twice")
+	report := fs.String("report", "", "write a per-booking report to this .csv or .json file (default: stdout)")
+	dryRun := fs.Bool("dry-run", false, "validate the file without booking anything")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usagef("import: --file required")
+	}
+	if *concurrency < 1 {
+		return usagef("import: --concurrency must be at least 1")
+	}
+
+	bookings, err := readBookings(*file, *format)
+	if err != nil {
+		return usagef("import: %v", err)
+	}
+	invalid := 0
+	for _, b := range bookings {
+		validateBooking(b)
+		b.Key = bookingKey(*keyPrefix, b.Req)
+		if len(b.Errs) > 0 {
+			invalid++
+			fmt.Fprintf(os.Stderr, "%s (line %d): %s\n", b.Ref, b.Line, strings.Join(b.Errs, "; "))
+		}
+	}
+	if invalid > 0 {
+		return usagef("import: %d of %d bookings are invalid, nothing was submitted", invalid, len(bookings))
+	}
+	if *dryRun {
+		fmt.Fprintf(os.Stderr, "%d bookings are valid\n", len(bookings))
+		return nil
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	results := make([]importResult, len(bookings))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				b := bookings[i]
+				res := importResult{BookingRef: b.Ref, Line: b.Line, IdempotencyKey: b.Key}
+
+				ctx, cancel := cf.context()
+				ctx = metadata.AppendToOutgoingContext(ctx, idempotencyMDKey, b.Key)
+				resp, err := client.ReserveTicket(ctx, b.Req)
+				cancel()
+
+				if err != nil {
+					res.Result, res.Error = "failed", describe(err)
+				} else {
+					res.Result, res.TicketNo = "booked", resp.TicketNo
+					var seats []string
+					for _, p := range resp.Passengers {
+						seats = append(seats, fmt.Sprintf("%s-%d", p.Section, p.Seat))
+					}
+					res.Seats = strings.Join(seats, " ")
+				}
+				results[i] = res
+			}
+		}()
+	}
+	for i := range bookings {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Result == "failed" {
+			failed++
+		}
+	}
+	if err := writeReport(*report, results); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d booked, %d failed\n", len(results)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("import: %d of %d bookings failed", failed, len(results))
+	}
+	return nil
+}
+
+func runExport(args []string) error {
+	fs, cf := newFlagSet("export")
+	file := fs.String("file", "", "output file (default: stdout)")
+	format := fs.String("format", "", "output format: csv or json (default: from the file extension, else csv)")
+	var req pb.SearchRequest
+	fs.StringVar(&req.Name, "name", "", "only passengers whose name contains this")
+	fs.StringVar(&req.Email, "email", "", "only this email")
+	fs.StringVar(&req.Section, "section", "", "only this section")
+	fs.StringVar(&req.Status, "status", "", "only this status")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	f := fileFormat(*file, *format)
+	if f != "csv" && f != "json" {
+		return usagef("export: unknown format %q", f)
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.SearchTickets(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if *file != "" {
+		out, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+
+	if f == "json" {
+		b, err := jsonOut.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(csvColumns)
+	for _, t := range resp.Tickets {
+		for _, p := range t.Passengers {
+			cw.Write([]string{
+				"", strconv.FormatUint(t.TicketNo, 10), t.Status, strconv.FormatUint(t.DepartureId, 10), t.FromCode, t.ToCode, strconv.FormatUint(t.PricePaid, 10),
+				p.FirstName, p.LastName, p.Email, p.Address, p.Section, strconv.FormatUint(uint64(p.Seat), 10),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func fileFormat(file, format string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return "json"
+	}
+	return "csv"
+}
+
+func readBookings(file, format string) ([]*booking, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch fileFormat(file, format) {
+	case "csv":
+		return readCSVBookings(f)
+	case "json":
+		return readJSONBookings(f)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// readCSVBookings reads one passenger per row. Consecutive rows sharing a
+// booking_ref are booked together; rows without one are booked alone.
+func readCSVBookings(r io.Reader) ([]*booking, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %v", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{"first_name", "email"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var bookings []*booking
+	byRef := map[string]*booking{}
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+
+		ref := get("booking_ref")
+		b := byRef[ref]
+		if b == nil || ref == "" {
+			b = &booking{Ref: ref, Line: line, Req: &pb.ReservationRequest{FromCode: get("from"), ToCode: get("to")}}
+			if b.Ref == "" {
+				b.Ref = fmt.Sprintf("line-%d", line)
+			}
+			if v := get("departure_id"); v != "" {
+				n, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					b.Errs = append(b.Errs, fmt.Sprintf("line %d: invalid departure_id %q", line, v))
+				}
+				b.Req.DepartureId = n
+			}
+			if v := get("price"); v != "" {
+				n, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					b.Errs = append(b.Errs, fmt.Sprintf("line %d: invalid price %q", line, v))
+				}
+				b.Req.PricePaid = n
+			}
+			bookings = append(bookings, b)
+			if ref != "" {
+				byRef[ref] = b
+			}
+		}
+
+		p := &pb.UserDetails{
+			FirstName: get("first_name"),
+			LastName:  get("last_name"),
+			Email:     get("email"),
+			Address:   get("address"),
+			Section:   get("section"),
+		}
+		if v := get("seat"); v != "" {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				b.Errs = append(b.Errs, fmt.Sprintf("line %d: invalid seat %q", line, v))
+			}
+			p.Seat = uint32(n)
+		}
+		b.Req.Passengers = append(b.Req.Passengers, p)
+	}
+	return bookings, nil
+}
+
+// readJSONBookings reads an array of ReservationRequest objects in their
+// protobuf JSON form, optionally with a "booking_ref" field each.
+func readJSONBookings(r io.Reader) ([]*booking, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of bookings: %v", err)
+	}
+	var bookings []*booking
+	for i, m := range raw {
+		var meta struct {
+			BookingRef string `json:"booking_ref"`
+		}
+		json.Unmarshal(m, &meta)
+		b := &booking{Ref: meta.BookingRef, Line: i + 1, Req: &pb.ReservationRequest{}}
+		if b.Ref == "" {
+			b.Ref = fmt.Sprintf("item-%d", i+1)
+		}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(m, b.Req); err != nil {
+			b.Errs = append(b.Errs, err.Error())
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, nil
+}
+
+func validateBooking(b *booking) {
+	if len(b.Req.Passengers) == 0 {
+		b.Errs = append(b.Errs, "no passengers")
+	}
+	for i, p := range b.Req.Passengers {
+		if p.FirstName == "" {
+			b.Errs = append(b.Errs, fmt.Sprintf("passenger %d: first_name required", i+1))
+		}
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			b.Errs = append(b.Errs, fmt.Sprintf("passenger %d: invalid email %q", i+1, p.Email))
+		}
+		if (p.Section == "") != (p.Seat == 0) {
+			b.Errs = append(b.Errs, fmt.Sprintf("passenger %d: section and seat must be given together", i+1))
+		}
+	}
+	b.Req.TicketNo = nil
+	b.Req.PassengerCount = uint64(len(b.Req.Passengers))
+}
+
+// bookingKey derives a stable idempotency key from the booking contents so
+// re-running an import after a partial failure does not double-book.
+func bookingKey(prefix string, req *pb.ReservationRequest) string {
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	sum := sha256.Sum256(append([]byte(prefix+"\x00"), b...))
+	return prefix + "-" + hex.EncodeToString(sum[:16])
+}
+
+func writeReport(path string, results []importResult) error {
+	w := io.Writer(os.Stdout)
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if fileFormat(path, "") == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"booking_ref", "line", "idempotency_key", "result", "ticket_no", "seats", "error"})
+	for _, r := range results {
+		ticket := ""
+		if r.TicketNo != 0 {
+			ticket = strconv.FormatUint(r.TicketNo, 10)
+		}
+		cw.Write([]string{r.BookingRef, strconv.Itoa(r.Line), r.IdempotencyKey, r.Result, ticket, r.Seats, r.Error})
+	}
+	cw.Flush()
+	return cw.Error()
+}
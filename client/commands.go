@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+// passengerFlag collects repeated --passenger key=value,... flags.
+type passengerFlag []*pb.UserDetails
+
+func (p *passengerFlag) String() string { return "" }
+
+func (p *passengerFlag) Set(v string) error {
+	u, err := parsePassenger(v)
+	if err != nil {
+		return err
+	}
+	*p = append(*p, u)
+	return nil
+}
+
+// parsePassenger parses "first=Ada,last=Lovelace,email=ada@example.com,
+// address=...,section=A,seat=3,prefer=window+quiet,party=lovelace".
+// Unknown keys are rejected.
+func parsePassenger(v string) (*pb.UserDetails, error) {
+	u := &pb.UserDetails{}
+	for _, kv := range strings.Split(v, ",") {
+		key, val, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=value, got %q", kv)
+		}
+		val = strings.TrimSpace(val)
+		switch strings.TrimSpace(key) {
+		case "first":
+			u.FirstName = val
+		case "last":
+			u.LastName = val
+		case "email":
+			u.Email = val
+		case "address":
+			u.Address = val
+		case "section":
+			u.Section = val
+		case "seat":
+			n, err := strconv.ParseUint(val, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid seat %q", val)
+			}
+			u.Seat = uint32(n)
+		case "prefer":
+			pref, err := parsePreferences(val)
+			if err != nil {
+				return nil, err
+			}
+			u.Preferences = pref
+		case "party":
+			u.Party = val
+		default:
+			return nil, fmt.Errorf("unknown passenger field %q", key)
+		}
+	}
+	return u, nil
+}
+
+// parsePreferences parses seat preferences joined by "+", e.g.
+// "window+forward+quiet". An empty string means no preferences.
+func parsePreferences(v string) (*pb.SeatPreferences, error) {
+	if v == "" {
+		return nil, nil
+	}
+	pref := &pb.SeatPreferences{}
+	for _, p := range strings.Split(v, "+") {
+		switch strings.TrimSpace(p) {
+		case "window":
+			pref.Position = pb.SeatPreferences_WINDOW
+		case "aisle":
+			pref.Position = pb.SeatPreferences_AISLE
+		case "forward":
+			pref.Facing = pb.SeatPreferences_FORWARD
+		case "backward":
+			pref.Facing = pb.SeatPreferences_BACKWARD
+		case "quiet":
+			pref.QuietCoach = true
+		case "exit":
+			pref.NearExit = true
+		case "accessible":
+			pref.Accessible = true
+		default:
+			return nil, fmt.Errorf("unknown seat preference %q (want window, aisle, forward, backward, quiet, exit or accessible)", p)
+		}
+	}
+	return pref, nil
+}
+
+func runReserve(args []string) error {
+	fs, cf := newFlagSet("reserve")
+	from := fs.String("from", "", "departure station code")
+	to := fs.String("to", "", "arrival station code")
+	price := fs.Uint64("price", 0, "expected fare in minor units; booking fails if the fare differs (default: accept the current fare)")
+	hold := fs.String("hold", "", "book the seat held under this hold ID")
+	departure := fs.Uint64("departure", 0, "departure ID (default: next departure from --from to --to)")
+	fareType := fs.String("fare-type", "", "fare type, e.g. flexible or saver (default standard)")
+	token := fs.String("payment-token", "", "payment token from the payment provider, e.g. tok_decline with the fake provider")
+	var first pb.UserDetails
+	fs.StringVar(&first.FirstName, "first-name", "", "first passenger's first name")
+	fs.StringVar(&first.LastName, "last-name", "", "first passenger's last name")
+	fs.StringVar(&first.Email, "email", "", "first passenger's email")
+	fs.StringVar(&first.Address, "address", "", "first passenger's address")
+	fs.StringVar(&first.Section, "section", "", "requested section, or preferred section without --seat (default: any)")
+	seat := fs.Uint("seat", 0, "requested seat number")
+	prefer := fs.String("prefer", "", "first passenger's seat preferences joined by +, e.g. window+forward+quiet (also aisle, backward, exit, accessible)")
+	fs.StringVar(&first.Party, "party", "", "first passenger's party; when the booking cannot sit together, each party is kept together")
+	var more passengerFlag
+	fs.Var(&more, "passenger", "additional passenger as first=..,last=..,email=..,address=..,section=..,seat=..,prefer=..,party=.. (repeatable)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	first.Seat = uint32(*seat)
+	pref, err := parsePreferences(*prefer)
+	if err != nil {
+		return usagef("reserve: %v", err)
+	}
+	first.Preferences = pref
+
+	var passengers []*pb.UserDetails
+	if first.FirstName != "" || first.Email != "" {
+		passengers = append(passengers, &first)
+	}
+	passengers = append(passengers, more...)
+	if len(passengers) == 0 {
+		return usagef("reserve: --first-name/--email or --passenger required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.ReserveTicket(ctx, &pb.ReservationRequest{
+		FromCode:       *from,
+		ToCode:         *to,
+		PricePaid:      *price,
+		PassengerCount: uint64(len(passengers)),
+		Passengers:     passengers,
+		HoldId:         *hold,
+		DepartureId:    *departure,
+		FareType:       *fareType,
+		PaymentToken:   *token,
+	})
+	if err != nil {
+		return err
+	}
+	return printBooking(cf.output, resp)
+}
+
+func runModify(args []string) error {
+	fs, cf := newFlagSet("modify")
+	ticket := fs.Uint64("ticket", 0, "ticket number")
+	section := fs.String("section", "", "new section")
+	seat := fs.Uint("seat", 0, "new seat number")
+	hold := fs.String("hold", "", "move to the seat held under this hold ID")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *ticket == 0 {
+		return usagef("modify: --ticket required")
+	}
+	if *hold == "" && (*section == "" || *seat == 0) {
+		return usagef("modify: --section and --seat, or --hold, required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.ModifyTicket(ctx, &pb.ReservationRequest{
+		TicketNo:       ticket,
+		PassengerCount: 1,
+		Passengers:     []*pb.UserDetails{{Section: *section, Seat: uint32(*seat)}},
+		HoldId:         *hold,
+	})
+	if err != nil {
+		return err
+	}
+	return printTickets(cf.output, resp)
+}
+
+func runCancel(args []string) error {
+	fs, cf := newFlagSet("cancel")
+	ticket := fs.Uint64("ticket", 0, "ticket number")
+	quote := fs.Bool("quote", false, "only show what cancelling would refund")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *ticket == 0 {
+		return usagef("cancel: --ticket required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	if *quote {
+		r, err := client.QuoteCancellation(ctx, &pb.ReservationRequest{TicketNo: ticket})
+		if err != nil {
+			return err
+		}
+		return printRefund(cf.output, r)
+	}
+	resp, err := client.CancelTicket(ctx, &pb.ReservationRequest{TicketNo: ticket})
+	if err != nil {
+		return err
+	}
+	if err := printTickets(cf.output, resp); err != nil || resp.Refund == nil || cf.output != "table" {
+		return err
+	}
+	fmt.Println()
+	return printRefund(cf.output, resp.Refund)
+}
+
+func runGet(args []string) error {
+	fs, cf := newFlagSet("get")
+	ticket := fs.Uint64("ticket", 0, "ticket number (or pass it as the only argument)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *ticket == 0 && fs.NArg() == 1 {
+		n, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+		if err != nil {
+			return usagef("get: invalid ticket number %q", fs.Arg(0))
+		}
+		*ticket = n
+	}
+	if *ticket == 0 {
+		return usagef("get: --ticket required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.GetTicket(ctx, &pb.ReservationRequest{TicketNo: ticket})
+	if err != nil {
+		return err
+	}
+	return printTickets(cf.output, resp)
+}
+
+func runDocument(args []string) error {
+	fs, cf := newFlagSet("document")
+	ticket := fs.Uint64("ticket", 0, "ticket number")
+	out := fs.String("out", "", "file to write (default: the name suggested by the server)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *ticket == 0 {
+		return usagef("document: --ticket required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	doc, err := client.GetTicketDocument(ctx, &pb.ReservationRequest{TicketNo: ticket})
+	if err != nil {
+		return err
+	}
+	path := *out
+	if path == "" {
+		path = filepath.Base(doc.Filename)
+	}
+	if err := os.WriteFile(path, doc.Content, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s (%d bytes)\n", path, len(doc.Content))
+	return nil
+}
+
+func runList(args []string) error {
+	fs, cf := newFlagSet("list")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.GetAllTickets(ctx, &pb.EmptyRequest{})
+	if err != nil {
+		return err
+	}
+	return printTicketList(cf.output, resp)
+}
+
+func runSearch(args []string) error {
+	fs, cf := newFlagSet("search")
+	var req pb.SearchRequest
+	fs.StringVar(&req.Name, "name", "", "passenger name (substring, case-insensitive)")
+	fs.StringVar(&req.Email, "email", "", "passenger email")
+	fs.StringVar(&req.Section, "section", "", "section")
+	fs.StringVar(&req.Status, "status", "", "ticket status, e.g. Confirmed")
+	fs.Uint64Var(&req.DepartureId, "departure", 0, "departure ID")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.SearchTickets(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return printTicketList(cf.output, resp)
+}
+
+func runSeatMap(args []string) error {
+	fs, cf := newFlagSet("seatmap")
+	section := fs.String("section", "", "only show this section")
+	departure := fs.Uint64("departure", 0, "departure ID (default: next departure)")
+	from := fs.String("from", "", "show seats free from this stop (default: the first)")
+	to := fs.String("to", "", "show seats free up to this stop (default: the last)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.GetSeatMap(ctx, &pb.SeatMapRequest{Section: *section, DepartureId: *departure, FromCode: *from, ToCode: *to})
+	if err != nil {
+		return err
+	}
+	return printSeatMap(cf.output, resp)
+}
+
+func runDepartures(args []string) error {
+	fs, cf := newFlagSet("departures")
+	var req pb.DeparturesRequest
+	fs.StringVar(&req.FromCode, "from", "", "departure station")
+	fs.StringVar(&req.ToCode, "to", "", "arrival station")
+	fs.StringVar(&req.Date, "date", "", "only departures on this day, YYYY-MM-DD (UTC)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.ListDepartures(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return printDepartures(cf.output, resp)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/tickettoken"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// invalidTicket is returned by inspect when the ticket does not pass, so
+// scripts can tell it apart from a failed call by the exit code.
+type invalidTicket struct{ result pb.TicketValidation_Result }
+
+func (e invalidTicket) Error() string { return "ticket is not valid: " + e.result.String() }
+
+func runInspect(args []string) error {
+	fs, cf := newFlagSet("inspect")
+	token := fs.String("token", "", "ticket token from the QR code (or pass it as the only argument, or - to read stdin)")
+	offline := fs.Bool("offline", false, "check signature and validity window locally, without the server")
+	publicKey := fs.String("public-key", os.Getenv("TICKET_PUBLIC_KEY"), "base64 Ed25519 public key for --offline (default $TICKET_PUBLIC_KEY)")
+	inspector := fs.String("inspector", "", "who is inspecting, recorded with the inspection")
+	consume := fs.Bool("consume", false, "mark the ticket as used if it is valid")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *token == "" && fs.NArg() == 1 {
+		*token = fs.Arg(0)
+	}
+	if *token == "-" {
+		var line string
+		fmt.Fscanln(os.Stdin, &line)
+		*token = line
+	}
+	if *token == "" {
+		return usagef("inspect: --token required")
+	}
+
+	var v *pb.TicketValidation
+	if *offline {
+		if *publicKey == "" {
+			return usagef("inspect: --offline needs --public-key or TICKET_PUBLIC_KEY")
+		}
+		if *consume {
+			return usagef("inspect: --consume needs the server")
+		}
+		pub, err := tickettoken.ParsePublicKey(*publicKey)
+		if err != nil {
+			return usagef("inspect: %v", err)
+		}
+		v = inspectOffline(pub, *token, time.Now())
+	} else {
+		conn, client, err := cf.dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ctx, cancel := cf.context()
+		defer cancel()
+		v, err = client.ValidateTicket(ctx, &pb.ValidateTicketRequest{Token: *token, Inspector: *inspector, Consume: *consume})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := printValidation(cf.output, v); err != nil {
+		return err
+	}
+	if v.Result != pb.TicketValidation_VALID {
+		return invalidTicket{v.Result}
+	}
+	return nil
+}
+
+// inspectOffline checks what can be checked without the server: the
+// signature and the validity window.
+func inspectOffline(pub []byte, token string, now time.Time) *pb.TicketValidation {
+	v := &pb.TicketValidation{}
+	c, err := tickettoken.Verify(pub, token)
+	switch {
+	case errors.Is(err, tickettoken.ErrMalformed):
+		v.Result, v.Message = pb.TicketValidation_MALFORMED, err.Error()
+		return v
+	case err != nil:
+		v.Result, v.Message = pb.TicketValidation_BAD_SIGNATURE, err.Error()
+		return v
+	}
+	v.TicketNo, v.DepartureId, v.Position = c.TicketNo, c.DepartureID, c.Position
+	v.Section, v.Seat = c.Section, c.Seat
+	v.ValidFrom, v.ValidUntil = timestamppb.New(c.ValidFrom()), timestamppb.New(c.ValidUntil())
+	switch err := c.CheckTime(now); {
+	case errors.Is(err, tickettoken.ErrNotYetValid):
+		v.Result, v.Message = pb.TicketValidation_NOT_YET_VALID, err.Error()
+	case err != nil:
+		v.Result, v.Message = pb.TicketValidation_EXPIRED, err.Error()
+	default:
+		v.Result, v.Message = pb.TicketValidation_VALID, "signature valid; cancellation and use not checked offline"
+	}
+	return v
+}
+
+func printValidation(format string, v *pb.TicketValidation) error {
+	if done, err := printStructured(os.Stdout, format, v); done {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Result:\t%s\n", v.Result)
+	fmt.Fprintf(tw, "Message:\t%s\n", v.Message)
+	if v.TicketNo != 0 {
+		fmt.Fprintf(tw, "Ticket:\t%d (passenger %d)\n", v.TicketNo, v.Position+1)
+		if v.PassengerName != "" {
+			fmt.Fprintf(tw, "Passenger:\t%s\n", v.PassengerName)
+		}
+		fmt.Fprintf(tw, "Departure:\t%d\n", v.DepartureId)
+		fmt.Fprintf(tw, "Seat:\t%s-%d\n", v.Section, v.Seat)
+		fmt.Fprintf(tw, "Valid:\t%s to %s\n", fmtTime(v.ValidFrom), fmtTime(v.ValidUntil))
+	}
+	if v.UsedAt != nil {
+		fmt.Fprintf(tw, "Used at:\t%s\n", fmtTime(v.UsedAt))
+	}
+	if v.InspectionId != 0 {
+		fmt.Fprintf(tw, "Inspection:\t%d\n", v.InspectionId)
+	}
+	return tw.Flush()
+}
+
+func fmtTime(ts *timestamppb.Timestamp) string {
+	return ts.AsTime().Local().Format("Mon 02 Jan 15:04")
+}
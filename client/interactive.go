@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+// prompter reads whole lines so answers may contain spaces.
+type prompter struct {
+	in  *bufio.Scanner
+	eof bool
+}
+
+func (p *prompter) text(label string) string {
+	fmt.Print(label)
+	if !p.in.Scan() {
+		p.eof = true
+		return ""
+	}
+	return strings.TrimSpace(p.in.Text())
+}
+
+// number prompts until a non-negative integer is entered. It returns 0 once
+// input is exhausted.
+func (p *prompter) number(label string) uint64 {
+	for {
+		n, err := strconv.ParseUint(p.text(label), 10, 64)
+		if err == nil || p.eof {
+			return n
+		}
+		fmt.Println("Please enter a number.")
+	}
+}
+
+func runInteractive(args []string) error {
+	fs, cf := newFlagSet("interactive")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	p := &prompter{in: bufio.NewScanner(os.Stdin)}
+	for {
+		fmt.Println("\n--- Train Ticket Reservation ---")
+		fmt.Println("1. Reserve Ticket")
+		fmt.Println("2. Modify Seat Allotment")
+		fmt.Println("3. Cancel Ticket")
+		fmt.Println("4. Close")
+		option := p.text("Choose option: ")
+		if p.eof {
+			return nil
+		}
+
+		switch option {
+		case "4":
+			return nil
+
+		case "1":
+			from := p.text("From: ")
+			to := p.text("To: ")
+			count := int(p.number("Passenger Count: "))
+
+			passengers := []*pb.UserDetails{}
+			for i := 0; i < count; i++ {
+				fmt.Printf("\nPassenger %d\n", i+1)
+				passengers = append(passengers, &pb.UserDetails{
+					FirstName: p.text("First Name: "),
+					LastName:  p.text("Last Name: "),
+					Email:     p.text("Email: "),
+					Address:   p.text("Address: "),
+				})
+			}
+
+			req := &pb.ReservationRequest{
+				FromCode:       from,
+				ToCode:         to,
+				PassengerCount: uint64(count),
+				Passengers:     passengers,
+			}
+
+			// CREATE CONTEXT HERE: After input is finished
+			ctx, cancel := cf.context()
+			fare, err := client.QuoteFare(ctx, req)
+			var resp *pb.ReservationResponse
+			if err == nil {
+				fmt.Printf("\nFare: %s\n", money(fare.Total, fare.Currency))
+				req.PricePaid = fare.Total
+				resp, err = client.ReserveTicket(ctx, req)
+			}
+			cancel()
+
+			if err != nil {
+				fmt.Println("gRPC error:", describe(err))
+			} else {
+				fmt.Println("\n✅ Reservation Successful!")
+				printTickets("table", resp)
+			}
+
+		case "2":
+			ticketNo := p.number("Ticket No: ")
+			count := int(p.number("How many seats to modify? "))
+
+			passengers := []*pb.UserDetails{}
+			for i := 0; i < count; i++ {
+				section := p.text(fmt.Sprintf("Passenger %d - New Section (A/B): ", i+1))
+				seat := p.number("New Seat No: ")
+				passengers = append(passengers, &pb.UserDetails{Section: section, Seat: uint32(seat)})
+			}
+
+			req := &pb.ReservationRequest{
+				TicketNo:       &ticketNo,
+				PassengerCount: uint64(count),
+				Passengers:     passengers,
+			}
+
+			ctx, cancel := cf.context()
+			resp, err := client.ModifyTicket(ctx, req)
+			cancel()
+
+			if err != nil {
+				fmt.Println("gRPC error:", describe(err))
+			} else {
+				fmt.Println("\n🔄 Modification Result:", resp.Status)
+			}
+
+		case "3":
+			ticketNo := p.number("Ticket No to Cancel: ")
+
+			ctx, cancel := cf.context()
+			resp, err := client.CancelTicket(ctx, &pb.ReservationRequest{
+				TicketNo: &ticketNo,
+			})
+			cancel()
+
+			if err != nil {
+				fmt.Println("gRPC error:", describe(err))
+			} else {
+				fmt.Println("\n❌ Ticket Cancelled:", resp.Status)
+				if r := resp.Refund; r != nil {
+					fmt.Printf("Refund: %s (%s)\n", money(r.Amount, r.Currency), r.Policy)
+				}
+			}
+
+		default:
+			fmt.Println("Unknown option.")
+		}
+	}
+}
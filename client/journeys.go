@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// runJourney dispatches the journey subcommands.
+func runJourney(args []string) error {
+	if len(args) == 0 {
+		return usagef("journey: want plan or book")
+	}
+	switch args[0] {
+	case "plan":
+		return runJourneyPlan(args[1:])
+	case "book":
+		return runJourneyBook(args[1:])
+	}
+	return usagef("journey: unknown subcommand %q", args[0])
+}
+
+func runJourneyPlan(args []string) error {
+	fs, cf := newFlagSet("journey plan")
+	var req pb.JourneyRequest
+	fs.StringVar(&req.FromCode, "from", "", "departure station")
+	fs.StringVar(&req.ToCode, "to", "", "arrival station")
+	after := fs.String("after", "", "leave no earlier than this, YYYY-MM-DDTHH:MM local time or RFC 3339 (default: now)")
+	changes := fs.Int("max-changes", -1, "most changes of train (default: the server's MAX_TRANSFERS)")
+	fewest := fs.Bool("fewest-changes", false, "rank by changes before arrival time")
+	passengers := fs.Uint("passengers", 1, "only journeys with this many seats free on every leg")
+	limit := fs.Uint("limit", 0, "at most this many journeys (default 5)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if req.FromCode == "" || req.ToCode == "" {
+		return usagef("journey plan: --from and --to required")
+	}
+	if *after != "" {
+		t, err := time.ParseInLocation("2006-01-02T15:04", *after, time.Local)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, *after); err != nil {
+				return usagef("journey plan: --after %q: want YYYY-MM-DDTHH:MM or RFC 3339", *after)
+			}
+		}
+		req.DepartsAfter = timestamppb.New(t)
+	}
+	if *changes >= 0 {
+		n := uint32(*changes)
+		req.MaxTransfers = &n
+	}
+	if *fewest {
+		req.Sort = pb.JourneyRequest_FEWEST_CHANGES
+	}
+	req.Passengers, req.Limit = uint32(*passengers), uint32(*limit)
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.PlanJourneys(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return printJourneys(cf.output, resp)
+}
+
+// legFlag collects repeated --leg DEPARTURE[:FROM:TO] flags.
+type legFlag []*pb.JourneyReservationRequest_Leg
+
+func (l *legFlag) String() string { return "" }
+
+func (l *legFlag) Set(v string) error {
+	f := strings.Split(v, ":")
+	if len(f) != 1 && len(f) != 3 {
+		return fmt.Errorf("leg %q: want DEPARTURE or DEPARTURE:FROM:TO", v)
+	}
+	id, err := strconv.ParseUint(f[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("leg %q: departure must be a number", v)
+	}
+	leg := &pb.JourneyReservationRequest_Leg{DepartureId: id}
+	if len(f) == 3 {
+		leg.FromCode, leg.ToCode = f[1], f[2]
+	}
+	*l = append(*l, leg)
+	return nil
+}
+
+func runJourneyBook(args []string) error {
+	fs, cf := newFlagSet("journey book")
+	var req pb.JourneyReservationRequest
+	var legs legFlag
+	fs.Var(&legs, "leg", "leg as DEPARTURE:FROM:TO, or DEPARTURE for its whole run, in travel order (repeatable)")
+	fs.Uint64Var(&req.PricePaid, "price", 0, "expected fare of all legs in minor units; booking fails if the fare differs (default: accept the current fare)")
+	fs.StringVar(&req.FareType, "fare-type", "", "fare type, e.g. flexible or saver (default standard)")
+	fs.StringVar(&req.PaymentToken, "payment-token", "", "payment token from the payment provider")
+	var first pb.UserDetails
+	fs.StringVar(&first.FirstName, "first-name", "", "first passenger's first name")
+	fs.StringVar(&first.LastName, "last-name", "", "first passenger's last name")
+	fs.StringVar(&first.Email, "email", "", "first passenger's email")
+	fs.StringVar(&first.Address, "address", "", "first passenger's address")
+	fs.StringVar(&first.Section, "section", "", "preferred section on every leg (default: any)")
+	prefer := fs.String("prefer", "", "first passenger's seat preferences joined by +, e.g. window+quiet")
+	fs.StringVar(&first.Party, "party", "", "first passenger's party")
+	var more passengerFlag
+	fs.Var(&more, "passenger", "additional passenger as first=..,last=..,email=..,address=..,section=..,prefer=..,party=.. (repeatable)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if len(legs) == 0 {
+		return usagef("journey book: --leg required")
+	}
+	pref, err := parsePreferences(*prefer)
+	if err != nil {
+		return usagef("journey book: %v", err)
+	}
+	first.Preferences = pref
+	if first.FirstName != "" || first.Email != "" {
+		req.Passengers = append(req.Passengers, &first)
+	}
+	req.Passengers = append(req.Passengers, more...)
+	if len(req.Passengers) == 0 {
+		return usagef("journey book: --first-name/--email or --passenger required")
+	}
+	req.Legs = legs
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.ReserveJourney(ctx, &req)
+	if err != nil {
+		return err
+	}
+	if done, err := printStructured(os.Stdout, cf.output, resp); done {
+		return err
+	}
+	currency := ""
+	if p := resp.Tickets[0].Payment; p != nil {
+		currency = p.Currency
+	}
+	fmt.Printf("Journey %d, %d tickets, %s\n\n", resp.JourneyId, len(resp.Tickets), money(resp.PricePaid, currency))
+	if err := printTickets(cf.output, resp.Tickets...); err != nil {
+		return err
+	}
+	var unmet []string
+	for _, t := range resp.Tickets {
+		for _, u := range t.UnmetPreferences {
+			unmet = append(unmet, fmt.Sprintf("%d\t%s\t%s\t%s", t.TicketNo, t.Passengers[u.Passenger].FirstName, u.Preference, u.Reason))
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TICKET\tPASSENGER\tUNMET PREFERENCE\tREASON")
+	for _, line := range unmet {
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
+
+func printJourneys(format string, list *pb.JourneyList) error {
+	if done, err := printStructured(os.Stdout, format, list); done {
+		return err
+	}
+	if len(list.Journeys) == 0 {
+		fmt.Println("No journeys.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "JOURNEY\tCHANGES\tDEPARTURE\tTRAIN\tFROM\tTO\tDEPARTS\tARRIVES\tFREE SEATS")
+	for i, j := range list.Journeys {
+		for k, d := range j.Legs {
+			journey, changes := "", ""
+			if k == 0 {
+				journey, changes = strconv.Itoa(i+1), strconv.Itoa(int(j.Changes))
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%d/%d\n", journey, changes, d.DepartureId, d.Train, d.FromCode, d.ToCode,
+				d.DepartsAt.AsTime().Local().Format("Mon 02 Jan 15:04"), d.ArrivesAt.AsTime().Local().Format("15:04"), d.SeatsFree, d.Capacity)
+		}
+	}
+	return tw.Flush()
+}
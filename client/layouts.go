@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+// runLayout dispatches the layout subcommands. They call the TicketAdmin
+// service and need the admin token.
+func runLayout(args []string) error {
+	if len(args) == 0 {
+		return usagef("layout: want put, list, get or attach")
+	}
+	switch args[0] {
+	case "put":
+		return runLayoutPut(args[1:])
+	case "list":
+		return runLayoutList(args[1:])
+	case "get":
+		return runLayoutGet(args[1:])
+	case "attach":
+		return runLayoutAttach(args[1:])
+	}
+	return usagef("layout: unknown subcommand %q", args[0])
+}
+
+// adminFlag registers --admin-token on fs.
+func adminFlag(fs *flag.FlagSet) *string {
+	return fs.String("admin-token", os.Getenv("ADMIN_TOKEN"), "token of the TicketAdmin service (env ADMIN_TOKEN)")
+}
+
+// dialAdmin connects to the TicketAdmin service and returns the context for
+// one request, carrying the admin token.
+func (cf *commonFlags) dialAdmin(token string) (pb.TicketAdminClient, context.Context, func(), error) {
+	if token == "" {
+		return nil, nil, nil, usagef("--admin-token or ADMIN_TOKEN required")
+	}
+	conn, _, err := cf.dial()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx, cancel := cf.context()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	return pb.NewTicketAdminClient(conn), ctx, func() { cancel(); conn.Close() }, nil
+}
+
+func runLayoutPut(args []string) error {
+	fs, cf := newFlagSet("layout put")
+	token := adminFlag(fs)
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("layout put: want one YAML or JSON file, or - for stdin")
+	}
+	var def []byte
+	var err error
+	if fs.Arg(0) == "-" {
+		def, err = io.ReadAll(os.Stdin)
+	} else {
+		def, err = os.ReadFile(fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	admin, ctx, done, err := cf.dialAdmin(*token)
+	if err != nil {
+		return err
+	}
+	defer done()
+	l, err := admin.PutLayout(ctx, &pb.PutLayoutRequest{Definition: string(def)})
+	if err != nil {
+		return err
+	}
+	return printLayout(cf.output, l)
+}
+
+func runLayoutList(args []string) error {
+	fs, cf := newFlagSet("layout list")
+	token := adminFlag(fs)
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	admin, ctx, done, err := cf.dialAdmin(*token)
+	if err != nil {
+		return err
+	}
+	defer done()
+	list, err := admin.ListLayouts(ctx, &pb.EmptyRequest{})
+	if err != nil {
+		return err
+	}
+	if done, err := printStructured(os.Stdout, cf.output, list); done {
+		return err
+	}
+	if len(list.Layouts) == 0 {
+		fmt.Println("No layouts.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LAYOUT\tVERSION\tCOACHES\tCAPACITY\tCREATED")
+	for _, l := range list.Layouts {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\n", l.Name, l.Version, len(l.Coaches), l.Capacity, fmtTime(l.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+func runLayoutGet(args []string) error {
+	fs, cf := newFlagSet("layout get")
+	token := adminFlag(fs)
+	name := fs.String("name", "", "layout name")
+	version := fs.Uint("version", 0, "layout version (default: latest)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return usagef("layout get: --name required")
+	}
+	admin, ctx, done, err := cf.dialAdmin(*token)
+	if err != nil {
+		return err
+	}
+	defer done()
+	l, err := admin.GetLayout(ctx, &pb.LayoutQuery{Name: *name, Version: uint32(*version)})
+	if err != nil {
+		return err
+	}
+	return printLayout(cf.output, l)
+}
+
+func runLayoutAttach(args []string) error {
+	fs, cf := newFlagSet("layout attach")
+	token := adminFlag(fs)
+	name := fs.String("name", "", "layout name")
+	version := fs.Uint("version", 0, "layout version (default: latest)")
+	departure := fs.Uint64("departure", 0, "attach to this departure")
+	train := fs.String("train", "", "attach to the train's upcoming departures and those scheduled later")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return usagef("layout attach: --name required")
+	}
+	if (*departure == 0) == (*train == "") {
+		return usagef("layout attach: --departure or --train required")
+	}
+	admin, ctx, done, err := cf.dialAdmin(*token)
+	if err != nil {
+		return err
+	}
+	defer done()
+	resp, err := admin.AttachLayout(ctx, &pb.AttachLayoutRequest{Name: *name, Version: uint32(*version), DepartureId: *departure, Train: *train})
+	if err != nil {
+		return err
+	}
+	if done, err := printStructured(os.Stdout, cf.output, resp); done {
+		return err
+	}
+	fmt.Printf("Attached to %d departures: %d seats added, %d removed.\n", len(resp.DepartureIds), resp.SeatsAdded, resp.SeatsRemoved)
+	return nil
+}
+
+func printLayout(format string, l *pb.Layout) error {
+	if done, err := printStructured(os.Stdout, format, l); done {
+		return err
+	}
+	fmt.Printf("Layout %s version %d, %d seats\n\n", l.Name, l.Version, l.Capacity)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COACH\tCLASS\tROWS\tROW\tSEATS\tQUIET\tBLOCKED")
+	for _, c := range l.Coaches {
+		blocked := make([]string, len(c.BlockedSeats))
+		for i, seat := range c.BlockedSeats {
+			blocked[i] = fmt.Sprint(seat)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d-%d\t%t\t%s\n", c.Section, c.Class, c.Rows, c.Row,
+			c.FirstSeat, c.FirstSeat+c.Seats-1, c.Quiet, strings.Join(blocked, ","))
+	}
+	return tw.Flush()
+}
@@ -1,138 +1,236 @@
+// Command client is a scriptable command-line client for the
+// TicketReservation service.
+//
+//	client <command> [flags]
+//
+// Run "client help" for the list of commands and "client <command> -h" for
+// the flags of a command.
 package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"os"
 	"time"
 
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	"github.com/Akash-private/Cloudbees_code/internal/rpcclient"
 	pb "github.com/Akash-private/Cloudbees_code/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-func main() {
-	// Updated to use NewClientConn as WithInsecure is deprecated in newer versions
-	conn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer conn.Close()
+// Exit codes. Scripts can rely on these staying stable.
+const (
+	exitOK          = 0
+	exitError       = 1 // any other failure
+	exitUsage       = 2 // bad command line
+	exitNotFound    = 3 // ticket or hold does not exist
+	exitConflict    = 4 // seat taken, hold expired, sold out
+	exitUnavailable = 5 // server unreachable or timed out
+	exitInvalid     = 6 // ticket failed inspection
+)
 
-	client := pb.NewTicketReservationClient(conn)
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
 
-	var option int
-	for {
-		fmt.Println("\n--- Train Ticket Reservation ---")
-		fmt.Println("1. Reserve Ticket")
-		fmt.Println("2. Modify Seat Allotment")
-		fmt.Println("3. Cancel Ticket")
-		fmt.Println("4. Close")
-		fmt.Print("Choose option: ")
-		fmt.Scan(&option)
+var commands []command
+
+func init() {
+	commands = []command{
+		{"reserve", "Reserve a ticket", runReserve},
+		{"modify", "Move a ticket to another section/seat", runModify},
+		{"cancel", "Cancel a ticket", runCancel},
+		{"get", "Show one ticket", runGet},
+		{"document", "Download a ticket as PDF", runDocument},
+		{"inspect", "Validate a ticket token, online or offline", runInspect},
+		{"checkin", "Check in every passenger of a ticket", runCheckIn},
+		{"board", "Board a checked-in passenger (staff)", runBoard},
+		{"manifest", "Show the boarding manifest of a departure (staff)", runManifest},
+		{"waitlist", "Join, list, claim or leave the waitlist of a sold-out departure", runWaitlist},
+		{"list", "List all tickets", runList},
+		{"search", "Search tickets by passenger, email, section or status", runSearch},
+		{"seatmap", "Show seat availability", runSeatMap},
+		{"departures", "List upcoming departures", runDepartures},
+		{"journey", "Plan journeys with changes of train, or book one", runJourney},
+		{"layout", "Store, list, show or attach coach layouts (admin)", runLayout},
+		{"import", "Book passengers from a CSV or JSON file", runImport},
+		{"export", "Write tickets to a CSV or JSON file", runExport},
+		{"interactive", "Menu-driven interactive mode", runInteractive},
+		{"tui", "Full-screen terminal UI with live seat map", runTUI},
+	}
+}
 
-		if option == 4 {
-			break
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(os.Stdout)
+		return
+	}
+	for _, c := range commands {
+		if c.name == name {
+			err := c.run(os.Args[2:])
+			if err != nil && !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(os.Stderr, "error:", describe(err))
+			}
+			os.Exit(exitCode(err))
 		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(exitUsage)
+}
 
-		switch option {
-		case 1:
-			var from, to string
-			var count int
-
-			fmt.Print("From: ")
-			fmt.Scan(&from)
-			fmt.Print("To: ")
-			fmt.Scan(&to)
-			fmt.Print("Passenger Count: ")
-			fmt.Scan(&count)
-
-			passengers := []*pb.UserDetails{}
-			for i := 0; i < count; i++ {
-				p := &pb.UserDetails{}
-				fmt.Printf("\nPassenger %d\n", i+1)
-				fmt.Print("First Name: ")
-				fmt.Scan(&p.FirstName)
-				fmt.Print("Last Name: ")
-				fmt.Scan(&p.LastName)
-				fmt.Print("Email: ")
-				fmt.Scan(&p.Email)
-				fmt.Print("Address: ")
-				fmt.Scan(&p.Address)
-				passengers = append(passengers, p)
-			}
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: client <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Every command accepts --server, --tls, --ca, --timeout and --output.")
+}
 
-			req := &pb.ReservationRequest{
-				FromCode:       from,
-				ToCode:         to,
-				PricePaid:      uint64(count * 20),
-				PassengerCount: uint64(count),
-				Passengers:     passengers,
-			}
+// usageError marks errors caused by the command line rather than the server.
+type usageError struct{ msg string }
 
-			// CREATE CONTEXT HERE: After input is finished
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			resp, err := client.ReserveTicket(ctx, req)
-			cancel()
+func (e usageError) Error() string { return e.msg }
 
-			if err != nil {
-				log.Println("gRPC error:", err)
-			} else {
-				fmt.Println("\n✅ Reservation Successful!")
-				fmt.Println(resp)
-			}
+func usagef(format string, args ...any) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
 
-		case 2:
-			var ticketNo uint64
-			var count int
-			fmt.Print("Ticket No: ")
-			fmt.Scan(&ticketNo)
-			fmt.Print("How many seats to modify? ")
-			fmt.Scan(&count)
-
-			passengers := []*pb.UserDetails{}
-			for i := 0; i < count; i++ {
-				p := &pb.UserDetails{}
-				fmt.Printf("Passenger %d - New Section (A/B): ", i+1)
-				fmt.Scan(&p.Section)
-				fmt.Print("New Seat No: ")
-				fmt.Scan(&p.Seat)
-				passengers = append(passengers, p)
-			}
+func exitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	var ue usageError
+	if errors.As(err, &ue) {
+		return exitUsage
+	}
+	var it invalidTicket
+	if errors.As(err, &it) {
+		return exitInvalid
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		return exitNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists, codes.ResourceExhausted, codes.Aborted:
+		return exitConflict
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return exitUnavailable
+	case codes.InvalidArgument:
+		return exitUsage
+	}
+	return exitError
+}
 
-			req := &pb.ReservationRequest{
-				TicketNo:       &ticketNo,
-				PassengerCount: uint64(count),
-				Passengers:     passengers,
-			}
+func describe(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return fmt.Sprintf("%s (%s)", st.Message(), st.Code())
+	}
+	return err.Error()
+}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			resp, err := client.ModifyTicket(ctx, req)
-			cancel()
+// commonFlags are registered on every command's flag set.
+type commonFlags struct {
+	server  string
+	apiKey  string
+	tls     bool
+	caFile  string
+	timeout time.Duration
+	output  string
+}
 
-			if err != nil {
-				log.Println("gRPC error:", err)
-			} else {
-				fmt.Println("\n🔄 Modification Result:", resp.Status)
-				fmt.Println(resp)
-			}
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cf := &commonFlags{}
+	fs.StringVar(&cf.server, "server", envOr("TICKET_SERVER", "localhost:50051"), "gRPC server address (env TICKET_SERVER)")
+	fs.StringVar(&cf.apiKey, "api-key", os.Getenv("TICKET_API_KEY"), "API key the server knows this client by (env TICKET_API_KEY)")
+	fs.BoolVar(&cf.tls, "tls", false, "connect using TLS")
+	fs.StringVar(&cf.caFile, "ca", "", "PEM file with the CA that signed the server certificate (implies --tls)")
+	fs.DurationVar(&cf.timeout, "timeout", 0, "overall timeout per request (default: per-method deadlines, see RPC_TIMEOUTS)")
+	fs.StringVar(&cf.output, "output", "table", "output format: table, json or yaml")
+	return fs, cf
+}
 
-		case 3:
-			var ticketNo uint64
-			fmt.Print("Ticket No to Cancel: ")
-			fmt.Scan(&ticketNo)
-
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			resp, err := client.CancelTicket(ctx, &pb.ReservationRequest{
-				TicketNo: &ticketNo,
-			})
-			cancel()
-
-			if err != nil {
-				log.Println("gRPC error:", err)
-			} else {
-				fmt.Println("\n❌ Ticket Cancelled:", resp.Status)
-			}
+// parse parses args and checks the common flags.
+func parse(fs *flag.FlagSet, cf *commonFlags, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
 		}
+		return usageError{err.Error()}
+	}
+	switch cf.output {
+	case "table", "json", "yaml":
+	default:
+		return usagef("unknown output format %q", cf.output)
+	}
+	return nil
+}
+
+func (cf *commonFlags) dial() (*grpc.ClientConn, pb.TicketReservationClient, error) {
+	creds := insecure.NewCredentials()
+	switch {
+	case cf.caFile != "":
+		c, err := credentials.NewClientTLSFromFile(cf.caFile, "")
+		if err != nil {
+			return nil, nil, usagef("load CA: %v", err)
+		}
+		creds = c
+	case cf.tls:
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	rc, err := rpcclient.LoadConfig()
+	if err != nil {
+		return nil, nil, usagef("%v", err)
+	}
+	opts := append(rpcclient.DialOptions(rc), grpc.WithTransportCredentials(creds))
+	conn, err := grpc.NewClient(cf.server, opts...)
+	if err != nil {
+		return nil, nil, usagef("invalid --server: %v", err)
+	}
+	return conn, pb.NewTicketReservationClient(conn), nil
+}
+
+// authContext returns a context carrying the API key.
+func (cf *commonFlags) authContext() context.Context {
+	if cf.apiKey == "" {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), logging.APIKeyKey, cf.apiKey)
+}
+
+// context returns the context for one request. Without --timeout the
+// per-method deadlines of the service config apply.
+func (cf *commonFlags) context() (context.Context, context.CancelFunc) {
+	ctx := cf.authContext()
+	if cf.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cf.timeout)
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
+	return def
 }
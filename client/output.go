@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+var jsonOut = protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true, EmitUnpopulated: true}
+
+// printStructured writes m as JSON or YAML. It reports false for the table
+// format so callers can fall back to their own layout.
+func printStructured(w io.Writer, format string, m proto.Message) (bool, error) {
+	switch format {
+	case "json":
+		b, err := jsonOut.Marshal(m)
+		if err != nil {
+			return true, err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return true, err
+	case "yaml":
+		b, err := jsonOut.Marshal(m)
+		if err != nil {
+			return true, err
+		}
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return true, err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return true, err
+		}
+		return true, enc.Close()
+	}
+	return false, nil
+}
+
+func printTickets(format string, tickets ...*pb.ReservationResponse) error {
+	if len(tickets) == 1 {
+		if done, err := printStructured(os.Stdout, format, tickets[0]); done {
+			return err
+		}
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TICKET\tDEPARTURE\tROUTE\tPASSENGER\tEMAIL\tSECTION\tSEAT\tSTATUS")
+	for _, t := range tickets {
+		route := ""
+		if t.FromCode != "" || t.ToCode != "" {
+			route = t.FromCode + " → " + t.ToCode
+		}
+		if len(t.Passengers) == 0 {
+			fmt.Fprintf(tw, "%d\t%d\t%s\t\t\t\t\t%s\n", t.TicketNo, t.DepartureId, route, t.Status)
+			continue
+		}
+		for _, p := range t.Passengers {
+			name := p.FirstName
+			if p.LastName != "" {
+				name += " " + p.LastName
+			}
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\t%d\t%s\n", t.TicketNo, t.DepartureId, route, name, p.Email, p.Section, p.Seat, t.Status)
+		}
+	}
+	return tw.Flush()
+}
+
+// printBooking prints a new booking and, in table form, the seat
+// preferences it could not meet.
+func printBooking(format string, t *pb.ReservationResponse) error {
+	if err := printTickets(format, t); err != nil || len(t.UnmetPreferences) == 0 || format != "table" {
+		return err
+	}
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PASSENGER\tUNMET PREFERENCE\tREASON")
+	for _, u := range t.UnmetPreferences {
+		name := ""
+		if int(u.Passenger) < len(t.Passengers) {
+			name = t.Passengers[u.Passenger].FirstName
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, u.Preference, u.Reason)
+	}
+	return tw.Flush()
+}
+
+func printRefund(format string, r *pb.Refund) error {
+	if done, err := printStructured(os.Stdout, format, r); done {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if r.RefundId != 0 {
+		fmt.Fprintf(tw, "Refund:\t%d\n", r.RefundId)
+	}
+	fmt.Fprintf(tw, "Ticket:\t%d (%s fare)\n", r.TicketNo, r.FareType)
+	fmt.Fprintf(tw, "Paid:\t%s\n", money(r.PricePaid, r.Currency))
+	fmt.Fprintf(tw, "Fee:\t%s\n", money(r.Fee, r.Currency))
+	fmt.Fprintf(tw, "Refunded:\t%s\n", money(r.Amount, r.Currency))
+	fmt.Fprintf(tw, "Policy:\t%s\n", r.Policy)
+	return tw.Flush()
+}
+
+// money formats an amount in minor units, e.g. "GBP 45.00".
+func money(amount uint64, currency string) string {
+	return fmt.Sprintf("%s %d.%02d", currency, amount/100, amount%100)
+}
+
+func printTicketList(format string, resp *pb.AllTicketsResponse) error {
+	if done, err := printStructured(os.Stdout, format, resp); done {
+		return err
+	}
+	if len(resp.Tickets) == 0 {
+		fmt.Println("No tickets found.")
+		return nil
+	}
+	return printTickets(format, resp.Tickets...)
+}
+
+// printSeatMap draws each section as rows of ten seats. Free seats are shown
+// by number, held seats as "h" and booked seats as "x".
+func printSeatMap(format string, m *pb.SeatMap) error {
+	if done, err := printStructured(os.Stdout, format, m); done {
+		return err
+	}
+	fmt.Printf("Departure %d, %s to %s", m.DepartureId, m.FromCode, m.ToCode)
+	if m.Layout != "" {
+		fmt.Printf(", layout %s version %d", m.Layout, m.LayoutVersion)
+	}
+	fmt.Println()
+	section, col := "", 0
+	for _, s := range m.Seats {
+		if s.Section != section {
+			if section != "" {
+				fmt.Println()
+			}
+			section, col = s.Section, 0
+			fmt.Printf("Section %s", section)
+			if s.CoachClass != "" {
+				class := s.CoachClass
+				if s.QuietCoach {
+					class += ", quiet"
+				}
+				fmt.Printf(" (%s)", class)
+			}
+		}
+		if col%10 == 0 {
+			fmt.Print("\n ")
+		}
+		col++
+		switch s.State {
+		case pb.SeatMap_HELD:
+			fmt.Printf(" %3s", "h")
+		case pb.SeatMap_BOOKED:
+			fmt.Printf(" %3s", "x")
+		case pb.SeatMap_BLOCKED:
+			fmt.Printf(" %3s", "-")
+		default:
+			fmt.Printf(" %3d", s.Seat)
+		}
+	}
+	fmt.Println()
+	fmt.Println("\nh = held, x = booked, - = not for sale")
+	return nil
+}
+
+func printDepartures(format string, list *pb.DepartureList) error {
+	if done, err := printStructured(os.Stdout, format, list); done {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEPARTURE\tTRAIN\tFROM\tTO\tVIA\tDEPARTS\tARRIVES\tFREE SEATS")
+	for _, d := range list.Departures {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d/%d\n", d.DepartureId, d.Train, d.FromCode, d.ToCode, via(d),
+			d.DepartsAt.AsTime().Local().Format("Mon 02 Jan 15:04"), d.ArrivesAt.AsTime().Local().Format("15:04"), d.SeatsFree, d.Capacity)
+	}
+	return tw.Flush()
+}
+
+// via lists the stops a departure makes between the passenger's two.
+func via(d *pb.Departure) string {
+	var codes []string
+	on := false
+	for _, stop := range d.Stops {
+		switch {
+		case stop.Code == d.FromCode && !on:
+			on = true
+		case stop.Code == d.ToCode && on:
+			on = false
+		case on:
+			codes = append(codes, stop.Code)
+		}
+	}
+	if len(codes) == 0 {
+		return "-"
+	}
+	return strings.Join(codes, ", ")
+}
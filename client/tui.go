@@ -0,0 +1,660 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"golang.org/x/term"
+)
+
+// The terminal UI is drawn with plain ANSI escape sequences on a raw-mode
+// terminal: departures on the left, the seat map of the selected departure in
+// the middle, the user's bookings on the right and the server's live event
+// feed at the bottom.
+
+const (
+	paneDepartures = iota
+	paneSeats
+	paneBookings
+	paneCount
+)
+
+const (
+	keyUp = iota + 0x100
+	keyDown
+	keyLeft
+	keyRight
+	keyEnter
+	keyTab
+	keyEsc
+	keyBackspace
+)
+
+const seatsPerRow = 10
+
+type tui struct {
+	cf     *commonFlags
+	client pb.TicketReservationClient
+	email  string
+
+	focus      int
+	departures []*pb.Departure
+	depIdx     int
+	seatMap    *pb.SeatMap
+	seatIdx    int
+	bookings   []*pb.ReservationResponse
+	bookIdx    int
+	events     []string
+	moving     *pb.ReservationResponse // booking being moved to another seat
+	status     string
+
+	// prompt state: while prompting, keys edit input instead of navigating.
+	prompt   string
+	input    []rune
+	onSubmit func(string)
+}
+
+func runTUI(args []string) error {
+	fs, cf := newFlagSet("tui")
+	email := fs.String("email", "", "show bookings for this email and use it for new bookings")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return usagef("tui: stdin is not a terminal")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	old, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l") // alternate screen, hide cursor
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		term.Restore(int(os.Stdin.Fd()), old)
+	}()
+
+	t := &tui{cf: cf, client: client, email: *email, status: "Loading…"}
+	keys := make(chan int)
+	go readKeys(keys)
+	events := make(chan *pb.Event)
+	ctx, cancel := context.WithCancel(cf.authContext())
+	defer cancel()
+	go t.watch(ctx, events)
+
+	t.loadDepartures()
+	t.loadBookings()
+	t.draw()
+
+	tick := time.NewTicker(30 * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			if t.handleKey(k) {
+				return nil
+			}
+		case e := <-events:
+			t.onEvent(e)
+		case <-tick.C:
+			t.loadDepartures()
+		}
+		t.draw()
+	}
+}
+
+// readKeys decodes raw terminal input into runes and the key constants above.
+func readKeys(out chan<- int) {
+	defer close(out)
+	r := bufio.NewReader(os.Stdin)
+	for {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			return
+		}
+		switch c {
+		case '\r', '\n':
+			out <- keyEnter
+		case '\t':
+			out <- keyTab
+		case 127, 8:
+			out <- keyBackspace
+		case 3: // Ctrl-C
+			out <- 'q'
+		case 27:
+			if r.Buffered() == 0 {
+				out <- keyEsc
+				continue
+			}
+			if b, _ := r.ReadByte(); b != '[' {
+				out <- keyEsc
+				continue
+			}
+			switch b, _ := r.ReadByte(); b {
+			case 'A':
+				out <- keyUp
+			case 'B':
+				out <- keyDown
+			case 'C':
+				out <- keyRight
+			case 'D':
+				out <- keyLeft
+			}
+		default:
+			out <- int(c)
+		}
+	}
+}
+
+// watch streams server events into ch, reconnecting after failures.
+func (t *tui) watch(ctx context.Context, ch chan<- *pb.Event) {
+	for ctx.Err() == nil {
+		stream, err := t.client.WatchEvents(ctx, &pb.WatchRequest{})
+		if err == nil {
+			for {
+				e, err := stream.Recv()
+				if err != nil {
+					break
+				}
+				ch <- e
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
+
+func (t *tui) onEvent(e *pb.Event) {
+	line := fmt.Sprintf("%s  %-15s dep %d", e.At.AsTime().Local().Format("15:04:05"), e.Type, e.DepartureId)
+	if e.TicketNo != 0 {
+		line += fmt.Sprintf("  ticket %d", e.TicketNo)
+	}
+	if e.Section != "" {
+		line += fmt.Sprintf("  seat %s-%d", e.Section, e.Seat)
+	}
+	t.events = append(t.events, line)
+	if len(t.events) > 50 {
+		t.events = t.events[len(t.events)-50:]
+	}
+
+	if t.seatMap != nil && (e.DepartureId == 0 || e.DepartureId == t.seatMap.DepartureId) {
+		t.loadSeatMap()
+	}
+	if strings.HasPrefix(e.Type, "Ticket") {
+		t.loadBookings()
+	}
+}
+
+func (t *tui) call(fn func(ctx context.Context) error) bool {
+	ctx, cancel := t.cf.context()
+	defer cancel()
+	if err := fn(ctx); err != nil {
+		t.status = "Error: " + describe(err)
+		return false
+	}
+	return true
+}
+
+func (t *tui) loadDepartures() {
+	t.call(func(ctx context.Context) error {
+		resp, err := t.client.ListDepartures(ctx, &pb.DeparturesRequest{})
+		if err != nil {
+			return err
+		}
+		t.departures = resp.Departures
+		if t.depIdx >= len(t.departures) {
+			t.depIdx = 0
+		}
+		t.status = "Tab: switch pane  ↑↓←→: move  Enter: book/confirm  m: move booking  c: cancel booking  q: quit"
+		return nil
+	})
+	t.loadSeatMap()
+}
+
+func (t *tui) selectedDeparture() *pb.Departure {
+	if t.depIdx < len(t.departures) {
+		return t.departures[t.depIdx]
+	}
+	return nil
+}
+
+func (t *tui) loadSeatMap() {
+	d := t.selectedDeparture()
+	if d == nil {
+		t.seatMap = nil
+		return
+	}
+	t.call(func(ctx context.Context) error {
+		m, err := t.client.GetSeatMap(ctx, &pb.SeatMapRequest{DepartureId: d.DepartureId})
+		if err != nil {
+			return err
+		}
+		if t.seatMap == nil || t.seatMap.DepartureId != m.DepartureId || t.seatIdx >= len(m.Seats) {
+			t.seatIdx = 0
+		}
+		t.seatMap = m
+		return nil
+	})
+}
+
+func (t *tui) loadBookings() {
+	if t.email == "" {
+		return
+	}
+	t.call(func(ctx context.Context) error {
+		resp, err := t.client.SearchTickets(ctx, &pb.SearchRequest{Email: t.email})
+		if err != nil {
+			return err
+		}
+		t.bookings = resp.Tickets
+		if t.bookIdx >= len(t.bookings) {
+			t.bookIdx = 0
+		}
+		return nil
+	})
+}
+
+func (t *tui) ask(prompt, initial string, onSubmit func(string)) {
+	t.prompt, t.input, t.onSubmit = prompt, []rune(initial), onSubmit
+}
+
+// handleKey applies one key press and reports whether the UI should exit.
+func (t *tui) handleKey(k int) bool {
+	if t.onSubmit != nil {
+		switch k {
+		case keyEnter:
+			fn, v := t.onSubmit, strings.TrimSpace(string(t.input))
+			t.prompt, t.input, t.onSubmit = "", nil, nil
+			fn(v)
+		case keyEsc:
+			t.prompt, t.input, t.onSubmit = "", nil, nil
+			t.status = "Cancelled."
+		case keyBackspace:
+			if len(t.input) > 0 {
+				t.input = t.input[:len(t.input)-1]
+			}
+		default:
+			if k < 0x100 && k >= ' ' {
+				t.input = append(t.input, rune(k))
+			}
+		}
+		return false
+	}
+
+	switch k {
+	case 'q':
+		return true
+	case keyTab:
+		t.focus = (t.focus + 1) % paneCount
+	case keyEsc:
+		t.moving = nil
+		t.status = "Move cancelled."
+	case 'r':
+		t.loadDepartures()
+		t.loadBookings()
+	case 'm':
+		if t.focus == paneBookings && t.bookIdx < len(t.bookings) {
+			t.moving = t.bookings[t.bookIdx]
+			t.focus = paneSeats
+			t.status = fmt.Sprintf("Moving ticket %d: pick a seat and press Enter (Esc to abort).", t.moving.TicketNo)
+		}
+	case 'c':
+		if t.focus == paneBookings && t.bookIdx < len(t.bookings) {
+			b := t.bookings[t.bookIdx]
+			t.ask(fmt.Sprintf("Cancel ticket %d? (y/n) ", b.TicketNo), "", func(v string) {
+				if strings.EqualFold(v, "y") {
+					t.cancelBooking(b)
+				}
+			})
+		}
+	case keyEnter:
+		if t.focus == paneSeats {
+			t.onSeatEnter()
+		}
+		if t.focus == paneDepartures {
+			t.focus = paneSeats
+		}
+	case keyUp, keyDown, keyLeft, keyRight:
+		t.move(k)
+	}
+	return false
+}
+
+func (t *tui) move(k int) {
+	switch t.focus {
+	case paneDepartures:
+		if k == keyUp && t.depIdx > 0 {
+			t.depIdx--
+			t.loadSeatMap()
+		}
+		if k == keyDown && t.depIdx < len(t.departures)-1 {
+			t.depIdx++
+			t.loadSeatMap()
+		}
+	case paneBookings:
+		if k == keyUp && t.bookIdx > 0 {
+			t.bookIdx--
+		}
+		if k == keyDown && t.bookIdx < len(t.bookings)-1 {
+			t.bookIdx++
+		}
+	case paneSeats:
+		if t.seatMap == nil {
+			return
+		}
+		n := len(t.seatMap.Seats)
+		next := t.seatIdx
+		switch k {
+		case keyLeft:
+			next--
+		case keyRight:
+			next++
+		case keyUp:
+			next -= seatsPerRow
+		case keyDown:
+			next += seatsPerRow
+		}
+		if next >= 0 && next < n {
+			t.seatIdx = next
+		}
+	}
+}
+
+func (t *tui) onSeatEnter() {
+	if t.seatMap == nil || t.seatIdx >= len(t.seatMap.Seats) {
+		return
+	}
+	seat := t.seatMap.Seats[t.seatIdx]
+	if seat.State != pb.SeatMap_FREE {
+		t.status = fmt.Sprintf("Seat %s-%d is not free.", seat.Section, seat.Seat)
+		return
+	}
+
+	if b := t.moving; b != nil {
+		t.moving = nil
+		ok := t.call(func(ctx context.Context) error {
+			_, err := t.client.ModifyTicket(ctx, &pb.ReservationRequest{
+				TicketNo:       &b.TicketNo,
+				PassengerCount: 1,
+				Passengers:     []*pb.UserDetails{{Section: seat.Section, Seat: seat.Seat}},
+			})
+			return err
+		})
+		if ok {
+			t.status = fmt.Sprintf("Ticket %d moved to %s-%d.", b.TicketNo, seat.Section, seat.Seat)
+		}
+		t.loadSeatMap()
+		t.loadBookings()
+		return
+	}
+
+	departureID := t.seatMap.DepartureId
+	t.ask(fmt.Sprintf("Passenger name for %s-%d: ", seat.Section, seat.Seat), "", func(name string) {
+		if name == "" {
+			t.status = "Name required."
+			return
+		}
+		t.ask("Email: ", t.email, func(email string) {
+			first, last, _ := strings.Cut(name, " ")
+			var ticket uint64
+			ok := t.call(func(ctx context.Context) error {
+				resp, err := t.client.ReserveTicket(ctx, &pb.ReservationRequest{
+					DepartureId:    departureID,
+					PassengerCount: 1,
+					Passengers: []*pb.UserDetails{{
+						FirstName: first, LastName: last, Email: email,
+						Section: seat.Section, Seat: seat.Seat,
+					}},
+				})
+				if err == nil {
+					ticket = resp.TicketNo
+				}
+				return err
+			})
+			if ok {
+				t.status = fmt.Sprintf("Booked ticket %d in seat %s-%d.", ticket, seat.Section, seat.Seat)
+				if t.email == "" {
+					t.email = email
+				}
+			}
+			t.loadSeatMap()
+			t.loadBookings()
+		})
+	})
+}
+
+func (t *tui) cancelBooking(b *pb.ReservationResponse) {
+	var resp *pb.ReservationResponse
+	ok := t.call(func(ctx context.Context) error {
+		var err error
+		resp, err = t.client.CancelTicket(ctx, &pb.ReservationRequest{TicketNo: &b.TicketNo})
+		return err
+	})
+	if ok {
+		t.status = fmt.Sprintf("Ticket %d cancelled.", b.TicketNo)
+		if r := resp.Refund; r != nil {
+			t.status += fmt.Sprintf(" Refund %s.", money(r.Amount, r.Currency))
+		}
+	}
+	t.loadSeatMap()
+	t.loadBookings()
+}
+
+// draw renders the whole screen. It is cheap enough to redraw after every
+// key press or event.
+func (t *tui) draw() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width < 100 || height < 24 {
+		fmt.Print("\x1b[H\x1b[2J")
+		fmt.Print("Please enlarge the terminal to at least 100x24 (q to quit).")
+		return
+	}
+
+	leftW, rightW := 30, 30
+	midW := width - leftW - rightW
+	paneH := height - 10
+
+	left := t.departureLines(leftW-2, paneH-2)
+	mid := t.seatLines(midW-2, paneH-2)
+	right := t.bookingLines(rightW-2, paneH-2)
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	who := t.email
+	if who == "" {
+		who = "no --email given"
+	}
+	b.WriteString(fit(" Train Reservation — "+who, width) + "\r\n")
+
+	title := "Seat map"
+	if d := t.selectedDeparture(); d != nil {
+		title = fmt.Sprintf("%s %s→%s %s", d.Train, d.FromCode, d.ToCode, d.DepartsAt.AsTime().Local().Format("Mon 15:04"))
+	}
+	tops := []string{
+		boxTop("Departures", leftW, t.focus == paneDepartures),
+		boxTop(title, midW, t.focus == paneSeats),
+		boxTop("My bookings", rightW, t.focus == paneBookings),
+	}
+	b.WriteString(strings.Join(tops, "") + "\r\n")
+	for i := 0; i < paneH-2; i++ {
+		b.WriteString("│" + line(left, i, leftW-2) + "││" + line(mid, i, midW-2) + "││" + line(right, i, rightW-2) + "│\r\n")
+	}
+	b.WriteString("└" + strings.Repeat("─", leftW-2) + "┘└" + strings.Repeat("─", midW-2) + "┘└" + strings.Repeat("─", rightW-2) + "┘\r\n")
+
+	b.WriteString(" Live events\r\n")
+	feed := t.events
+	if len(feed) > 5 {
+		feed = feed[len(feed)-5:]
+	}
+	for i := 0; i < 5; i++ {
+		s := ""
+		if i < len(feed) {
+			s = "  " + feed[i]
+		}
+		b.WriteString(fit(s, width) + "\r\n")
+	}
+
+	if t.onSubmit != nil {
+		b.WriteString("\x1b[7m" + fit(" "+t.prompt+string(t.input)+"_", width) + "\x1b[0m")
+	} else {
+		b.WriteString("\x1b[7m" + fit(" "+t.status, width) + "\x1b[0m")
+	}
+	fmt.Print(b.String())
+}
+
+func (t *tui) departureLines(w, h int) []string {
+	var lines []string
+	start := 0
+	if t.depIdx >= h {
+		start = t.depIdx - h + 1
+	}
+	for i := start; i < len(t.departures) && len(lines) < h; i++ {
+		d := t.departures[i]
+		s := fmt.Sprintf("%s %s %-6s→%-6s %3d", d.DepartsAt.AsTime().Local().Format("02 15:04"), d.Train, d.FromCode, d.ToCode, d.SeatsFree)
+		lines = append(lines, highlight(fit(s, w), i == t.depIdx, t.focus == paneDepartures))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No upcoming departures.")
+	}
+	return lines
+}
+
+func (t *tui) seatLines(w, h int) []string {
+	if t.seatMap == nil {
+		return []string{"No departure selected."}
+	}
+	var lines []string
+	var row strings.Builder
+	rowLen, section := 0, ""
+	flush := func() {
+		if rowLen > 0 {
+			lines = append(lines, row.String())
+			row.Reset()
+			rowLen = 0
+		}
+	}
+	for i, s := range t.seatMap.Seats {
+		if s.Section != section {
+			flush()
+			section = s.Section
+			lines = append(lines, "", " Section "+section)
+		}
+		if rowLen == seatsPerRow {
+			flush()
+		}
+		if rowLen == 0 {
+			row.WriteString(" ")
+		}
+		cell := fmt.Sprintf("%3d ", s.Seat)
+		switch s.State {
+		case pb.SeatMap_HELD:
+			cell = "  h "
+		case pb.SeatMap_BOOKED:
+			cell = "  x "
+		case pb.SeatMap_BLOCKED:
+			cell = "  - "
+		}
+		row.WriteString(highlight(cell, i == t.seatIdx, t.focus == paneSeats))
+		rowLen++
+	}
+	flush()
+	lines = append(lines, "", " h = held   x = booked   - = not for sale")
+	return lines
+}
+
+func (t *tui) bookingLines(w, h int) []string {
+	if t.email == "" {
+		return []string{"Start with --email to", "see your bookings."}
+	}
+	var lines []string
+	for i, b := range t.bookings {
+		seat := ""
+		if len(b.Passengers) > 0 {
+			seat = fmt.Sprintf("%s-%d", b.Passengers[0].Section, b.Passengers[0].Seat)
+		}
+		s := fmt.Sprintf("#%-5d dep %-4d %-5s %s", b.TicketNo, b.DepartureId, seat, b.Status)
+		lines = append(lines, highlight(fit(s, w), i == t.bookIdx, t.focus == paneBookings))
+		if len(lines) == h {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No bookings yet.")
+	}
+	return lines
+}
+
+func boxTop(title string, w int, focused bool) string {
+	title = " " + title + " "
+	if utf8.RuneCountInString(title) > w-4 {
+		title = string([]rune(title)[:w-4])
+	}
+	s := "┌─" + title + strings.Repeat("─", w-3-utf8.RuneCountInString(title)) + "┐"
+	if focused {
+		return "\x1b[1m" + s + "\x1b[0m"
+	}
+	return s
+}
+
+func highlight(s string, selected, focused bool) string {
+	switch {
+	case selected && focused:
+		return "\x1b[7m" + s + "\x1b[0m"
+	case selected:
+		return "\x1b[4m" + s + "\x1b[0m"
+	}
+	return s
+}
+
+// line returns lines[i] padded to w visible columns, ignoring escape codes.
+func line(lines []string, i, w int) string {
+	if i >= len(lines) {
+		return strings.Repeat(" ", w)
+	}
+	n := visibleLen(lines[i])
+	if n >= w {
+		return lines[i]
+	}
+	return lines[i] + strings.Repeat(" ", w-n)
+}
+
+func visibleLen(s string) int {
+	n, esc := 0, false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			esc = true
+		case esc && r == 'm':
+			esc = false
+		case !esc:
+			n++
+		}
+	}
+	return n
+}
+
+// fit truncates or pads s to exactly w runes.
+func fit(s string, w int) string {
+	r := []rune(s)
+	if len(r) > w {
+		return string(r[:w])
+	}
+	return s + strings.Repeat(" ", w-len(r))
+}
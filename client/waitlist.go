@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+// runWaitlist dispatches the waitlist subcommands.
+func runWaitlist(args []string) error {
+	if len(args) == 0 {
+		return usagef("waitlist: want join, list, claim or leave")
+	}
+	switch args[0] {
+	case "join":
+		return runWaitlistJoin(args[1:])
+	case "list":
+		return runWaitlistList(args[1:])
+	case "claim":
+		return runWaitlistClaim(args[1:])
+	case "leave":
+		return runWaitlistLeave(args[1:])
+	}
+	return usagef("waitlist: unknown subcommand %q", args[0])
+}
+
+func runWaitlistJoin(args []string) error {
+	fs, cf := newFlagSet("waitlist join")
+	from := fs.String("from", "", "departure station code")
+	to := fs.String("to", "", "arrival station code")
+	departure := fs.Uint64("departure", 0, "departure ID (default: next departure from --from to --to)")
+	priority := fs.Int("priority", 0, "place ahead of entries with a lower priority")
+	var first pb.UserDetails
+	fs.StringVar(&first.FirstName, "first-name", "", "first passenger's first name")
+	fs.StringVar(&first.LastName, "last-name", "", "first passenger's last name")
+	fs.StringVar(&first.Email, "email", "", "first passenger's email")
+	fs.StringVar(&first.Address, "address", "", "first passenger's address")
+	prefer := fs.String("prefer", "", "first passenger's seat preferences joined by +, e.g. window+quiet")
+	fs.StringVar(&first.Party, "party", "", "first passenger's party")
+	var more passengerFlag
+	fs.Var(&more, "passenger", "additional passenger as first=..,last=..,email=..,address=..,prefer=..,party=.. (repeatable)")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	pref, err := parsePreferences(*prefer)
+	if err != nil {
+		return usagef("waitlist join: %v", err)
+	}
+	first.Preferences = pref
+
+	var passengers []*pb.UserDetails
+	if first.FirstName != "" || first.Email != "" {
+		passengers = append(passengers, &first)
+	}
+	passengers = append(passengers, more...)
+	if len(passengers) == 0 {
+		return usagef("waitlist join: --first-name/--email or --passenger required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	e, err := client.JoinWaitlist(ctx, &pb.JoinWaitlistRequest{
+		Request: &pb.ReservationRequest{
+			FromCode:       *from,
+			ToCode:         *to,
+			DepartureId:    *departure,
+			PassengerCount: uint64(len(passengers)),
+			Passengers:     passengers,
+		},
+		Priority: int32(*priority),
+	})
+	if err != nil {
+		return err
+	}
+	return printWaitlist(cf.output, &pb.WaitlistEntries{Entries: []*pb.WaitlistEntry{e}})
+}
+
+func runWaitlistList(args []string) error {
+	fs, cf := newFlagSet("waitlist list")
+	departure := fs.Uint64("departure", 0, "departure ID")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *departure == 0 {
+		return usagef("waitlist list: --departure required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.ListWaitlist(ctx, &pb.WaitlistQuery{DepartureId: *departure})
+	if err != nil {
+		return err
+	}
+	return printWaitlist(cf.output, resp)
+}
+
+func runWaitlistClaim(args []string) error {
+	fs, cf := newFlagSet("waitlist claim")
+	id := fs.Uint64("id", 0, "waitlist entry ID")
+	price := fs.Uint64("price", 0, "expected fare in minor units; booking fails if the fare differs (default: accept the current fare)")
+	token := fs.String("payment-token", "", "payment token from the payment provider")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return usagef("waitlist claim: --id required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	resp, err := client.ClaimWaitlist(ctx, &pb.ClaimWaitlistRequest{WaitlistId: *id, PricePaid: *price, PaymentToken: *token})
+	if err != nil {
+		return err
+	}
+	return printBooking(cf.output, resp)
+}
+
+func runWaitlistLeave(args []string) error {
+	fs, cf := newFlagSet("waitlist leave")
+	id := fs.Uint64("id", 0, "waitlist entry ID")
+	if err := parse(fs, cf, args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return usagef("waitlist leave: --id required")
+	}
+
+	conn, client, err := cf.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	e, err := client.LeaveWaitlist(ctx, &pb.WaitlistQuery{WaitlistId: *id})
+	if err != nil {
+		return err
+	}
+	return printWaitlist(cf.output, &pb.WaitlistEntries{Entries: []*pb.WaitlistEntry{e}})
+}
+
+func printWaitlist(format string, list *pb.WaitlistEntries) error {
+	if done, err := printStructured(os.Stdout, format, list); done {
+		return err
+	}
+	if len(list.Entries) == 0 {
+		fmt.Println("Nobody is waiting.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDEPARTURE\tSEATS\tPRIORITY\tSTATUS\tDETAIL")
+	for _, e := range list.Entries {
+		var detail string
+		switch e.Status {
+		case pb.WaitlistEntry_WAITING:
+			detail = fmt.Sprintf("#%d in line", e.Position)
+		case pb.WaitlistEntry_PROMOTED:
+			detail = "claim by " + fmtTime(e.HoldExpiresAt)
+		case pb.WaitlistEntry_BOOKED:
+			detail = fmt.Sprintf("ticket %d", e.TicketNo)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%s\t%s\n", e.WaitlistId, e.DepartureId, e.PassengerCount, e.Priority, e.Status, detail)
+	}
+	return tw.Flush()
+}
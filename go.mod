@@ -4,8 +4,12 @@ go 1.24.6
 
 require (
 	github.com/lib/pq v1.10.9
+	golang.org/x/crypto v0.43.0
+	golang.org/x/term v0.36.0
 	google.golang.org/grpc v1.77.0
 	google.golang.org/protobuf v1.36.10
+	gopkg.in/yaml.v3 v3.0.1
+	rsc.io/qr v0.2.0
 )
 
 require (
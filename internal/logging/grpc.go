@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ticketNumbered is implemented by every request and response message that
+// refers to a single ticket.
+type ticketNumbered interface {
+	GetTicketNo() uint64
+}
+
+// UnaryServerInterceptor accepts (or creates) a request ID from the incoming
+// metadata, echoes it back as a response header and writes one access log
+// line per RPC.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		id := requestID(ctx)
+
+		ctx, principal := incoming(ctx, logger, id)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, id))
+
+		resp, err := handler(ctx, req)
+
+		var ticketNo uint64
+		if t, ok := req.(ticketNumbered); ok {
+			ticketNo = t.GetTicketNo()
+		}
+		if t, ok := resp.(ticketNumbered); ok && ticketNo == 0 && err == nil {
+			ticketNo = t.GetTicketNo()
+		}
+
+		logAccess(ctx, info.FullMethod, *principal, ticketNo, start, err)
+		return resp, err
+	}
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor. The access log line is written when the stream ends.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		id := requestID(ss.Context())
+		ctx, principal := incoming(ss.Context(), logger, id)
+		ss.SetHeader(metadata.Pairs(RequestIDKey, id))
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logAccess(ctx, info.FullMethod, *principal, 0, start, err)
+		return err
+	}
+}
+
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if id := first(md, RequestIDKey); id != "" {
+		return id
+	}
+	return NewRequestID()
+}
+
+// incoming prepares the context of an RPC. The principal it returns starts
+// as "anonymous" and is filled in by SetPrincipal once the caller is
+// authenticated; the x-principal metadata is only a claim and is not logged.
+func incoming(ctx context.Context, logger *slog.Logger, id string) (context.Context, *string) {
+	principal := "anonymous"
+	ctx = context.WithValue(ctx, principalCtxKey, &principal)
+	return WithRequestID(WithLogger(ctx, logger), id), &principal
+}
+
+func logAccess(ctx context.Context, method, principal string, ticketNo uint64, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("principal", principal),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("code", code.String()),
+	}
+	if ticketNo != 0 {
+		attrs = append(attrs, slog.Uint64("ticket_no", ticketNo))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	FromContext(ctx).LogAttrs(ctx, level, "rpc", attrs...)
+}
+
+// UnaryClientInterceptor forwards the request ID stored in ctx to the server.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := RequestID(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, RequestIDKey, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
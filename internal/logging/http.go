@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Middleware assigns a request ID to every HTTP request (reusing an incoming
+// X-Request-ID header if present), exposes it via the request context so it
+// can be forwarded over gRPC, and writes one access log line per request.
+func Middleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(RequestIDKey)
+		if id == "" {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDKey, id)
+
+		ctx := WithLogger(r.Context(), logger)
+		ctx = WithRequestID(ctx, id)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "http",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
@@ -0,0 +1,111 @@
+// Package logging wires log/slog for the server, web bridge and CLI: JSON
+// output, a request ID that travels from the web bridge through gRPC
+// metadata, per-RPC access logs and masking of passenger PII.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// RequestIDKey is both the HTTP header and the gRPC metadata key that carries
+// the request ID between services.
+const RequestIDKey = "x-request-id"
+
+// PrincipalKey is the gRPC metadata key identifying the caller on whose
+// behalf an RPC is made.
+const PrincipalKey = "x-principal"
+
+// APIKeyKey is the gRPC metadata key carrying the API key that authenticates
+// the caller. The server only trusts PrincipalKey from the web bridge's key.
+const APIKeyKey = "x-api-key"
+
+type ctxKey int
+
+const (
+	requestIDCtxKey ctxKey = iota
+	loggerCtxKey
+	principalCtxKey
+)
+
+// showPII disables masking of names and emails. Only meant for local debugging.
+var showPII = os.Getenv("LOG_SHOW_PII") == "true"
+
+// New returns a JSON logger tagged with the service name. The level is taken
+// from LOG_LEVEL (debug, info, warn, error) and defaults to info.
+func New(service string) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		level = slog.LevelInfo
+	}
+	h := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	return slog.New(h).With("service", service)
+}
+
+// NewRequestID returns a random 128-bit hex identifier.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID stores id in ctx and attaches it to the logger carried by ctx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDCtxKey, id)
+	return WithLogger(ctx, FromContext(ctx).With("request_id", id))
+}
+
+// RequestID returns the request ID stored in ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey).(string)
+	return id
+}
+
+// SetPrincipal records who the server authenticated the caller as, for the
+// access log line of the RPC ctx belongs to. RPCs that never authenticate
+// are logged as "anonymous".
+func SetPrincipal(ctx context.Context, principal string) {
+	if p, ok := ctx.Value(principalCtxKey).(*string); ok {
+		*p = principal
+	}
+}
+
+// WithLogger stores l in ctx.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey, l)
+}
+
+// FromContext returns the logger stored in ctx, falling back to slog.Default.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerCtxKey).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// Name returns an attribute holding a person's name, masked unless
+// LOG_SHOW_PII=true.
+func Name(key, v string) slog.Attr {
+	if showPII || v == "" {
+		return slog.String(key, v)
+	}
+	r := []rune(v)
+	return slog.String(key, string(r[0])+"***")
+}
+
+// Email returns an attribute holding an email address with the local part
+// masked unless LOG_SHOW_PII=true.
+func Email(key, v string) slog.Attr {
+	if showPII || v == "" {
+		return slog.String(key, v)
+	}
+	local, domain, ok := strings.Cut(v, "@")
+	if !ok || local == "" {
+		return slog.String(key, "***")
+	}
+	return slog.String(key, string([]rune(local)[0])+"***@"+domain)
+}
@@ -0,0 +1,74 @@
+package rpcclient
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// HedgingInterceptor sends another attempt of the listed methods (full method
+// names) whenever the previous one has not answered within delay, up to
+// maxAttempts in total, and returns the first successful reply. The other
+// attempts are cancelled. grpc-go does not implement the hedgingPolicy of the
+// service config, hence the interceptor.
+//
+// Only use it for RPCs without side effects.
+func HedgingInterceptor(methods map[string]bool, delay time.Duration, maxAttempts int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		out, ok := reply.(proto.Message)
+		if !methods[method] || maxAttempts < 2 || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type result struct {
+			reply proto.Message
+			err   error
+		}
+		results := make(chan result, maxAttempts)
+		sent, pending := 0, 0
+		launch := func() {
+			r := out.ProtoReflect().New().Interface()
+			sent++
+			pending++
+			go func() {
+				results <- result{r, invoker(ctx, method, req, r, cc, opts...)}
+			}()
+		}
+
+		launch()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		var lastErr error
+		for pending > 0 {
+			select {
+			case <-timer.C:
+				if sent < maxAttempts {
+					launch()
+					timer.Reset(delay)
+				}
+			case res := <-results:
+				pending--
+				if res.err == nil {
+					proto.Merge(out, res.reply)
+					return nil
+				}
+				lastErr = res.err
+				if status.Code(res.err) != codes.Unavailable {
+					return res.err
+				}
+				if sent < maxAttempts {
+					launch()
+					timer.Reset(delay)
+				}
+			}
+		}
+		return lastErr
+	}
+}
@@ -0,0 +1,205 @@
+// Package rpcclient holds the dial options shared by the web bridge and the
+// CLI: a gRPC service config with per-method deadlines and retry policies,
+// exponential backoff when reconnecting, and hedging for reads.
+package rpcclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+)
+
+const service = "ticket_reservation.TicketReservation"
+
+// Default deadlines per method. Writes get longer than reads because they
+// wait for row locks; WatchEvents is a long-lived stream and has none.
+var defaultTimeouts = map[string]time.Duration{
+	"ReserveTicket":       8 * time.Second,
+	"ModifyTicket":        8 * time.Second,
+	"CancelTicket":        8 * time.Second,
+	"HoldSeat":            5 * time.Second,
+	"GetTicket":           3 * time.Second,
+	"GetAllTickets":       5 * time.Second,
+	"SearchTickets":       5 * time.Second,
+	"GetSeatMap":          3 * time.Second,
+	"ListDepartures":      3 * time.Second,
+	"QuoteFare":           3 * time.Second,
+	"GetTicketDocument":   5 * time.Second,
+	"ValidateTicket":      3 * time.Second,
+	"CheckIn":             5 * time.Second,
+	"Board":               5 * time.Second,
+	"GetBoardingManifest": 5 * time.Second,
+	"JoinWaitlist":        5 * time.Second,
+	"ListWaitlist":        3 * time.Second,
+	"ClaimWaitlist":       8 * time.Second,
+	"LeaveWaitlist":       5 * time.Second,
+	"QuoteCancellation":   3 * time.Second,
+	"PlanJourneys":        5 * time.Second,
+	"ReserveJourney":      10 * time.Second,
+	"CreateAccount":       5 * time.Second,
+	"SignIn":              5 * time.Second,
+	"GetAccount":          3 * time.Second,
+}
+
+// retried lists the RPCs that are safe to repeat: they only read.
+var retried = []string{"GetTicket", "GetAllTickets", "SearchTickets", "GetSeatMap", "ListDepartures", "QuoteFare", "GetTicketDocument", "GetBoardingManifest", "ListWaitlist", "QuoteCancellation", "GetAccount", "PlanJourneys"}
+
+// hedged lists the reads worth sending twice when the first attempt is slow.
+var hedged = []string{"GetAllTickets"}
+
+// Config tunes the client side of the connection.
+type Config struct {
+	// Timeouts maps a TicketReservation method name to its deadline.
+	Timeouts map[string]time.Duration
+	// MaxAttempts bounds retries and hedged attempts, including the first.
+	MaxAttempts int
+	// HedgeDelay is how long a hedged read waits before sending another
+	// attempt.
+	HedgeDelay time.Duration
+	// MaxBackoff caps the delay between reconnection attempts.
+	MaxBackoff time.Duration
+}
+
+// LoadConfig reads the configuration from the environment:
+//
+//	RPC_TIMEOUTS     per-method deadlines, e.g. "GetAllTickets=2s,ReserveTicket=10s"
+//	RPC_MAX_ATTEMPTS attempts per retried or hedged call (default 3)
+//	RPC_HEDGE_DELAY  delay before a hedged attempt (default 300ms)
+//	RPC_MAX_BACKOFF  longest wait between reconnects (default 20s)
+func LoadConfig() (Config, error) {
+	cfg := Config{
+		Timeouts:    make(map[string]time.Duration, len(defaultTimeouts)),
+		MaxAttempts: 3,
+		HedgeDelay:  300 * time.Millisecond,
+		MaxBackoff:  20 * time.Second,
+	}
+	for m, d := range defaultTimeouts {
+		cfg.Timeouts[m] = d
+	}
+
+	if v := os.Getenv("RPC_TIMEOUTS"); v != "" {
+		for _, kv := range strings.Split(v, ",") {
+			method, d, ok := strings.Cut(strings.TrimSpace(kv), "=")
+			if !ok {
+				return Config{}, fmt.Errorf("RPC_TIMEOUTS: expected Method=duration, got %q", kv)
+			}
+			if _, known := defaultTimeouts[method]; !known {
+				return Config{}, fmt.Errorf("RPC_TIMEOUTS: unknown method %q", method)
+			}
+			dur, err := time.ParseDuration(d)
+			if err != nil || dur <= 0 {
+				return Config{}, fmt.Errorf("RPC_TIMEOUTS: invalid duration %q for %s", d, method)
+			}
+			cfg.Timeouts[method] = dur
+		}
+	}
+	if v := os.Getenv("RPC_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 5 {
+			return Config{}, fmt.Errorf("RPC_MAX_ATTEMPTS must be between 1 and 5, got %q", v)
+		}
+		cfg.MaxAttempts = n
+	}
+	for key, dst := range map[string]*time.Duration{"RPC_HEDGE_DELAY": &cfg.HedgeDelay, "RPC_MAX_BACKOFF": &cfg.MaxBackoff} {
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return Config{}, fmt.Errorf("%s: invalid duration %q", key, v)
+			}
+			*dst = d
+		}
+	}
+	return cfg, nil
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// ServiceConfig renders cfg as a gRPC service config. Only UNAVAILABLE is
+// retried: the server uses RESOURCE_EXHAUSTED and FAILED_PRECONDITION for
+// sold-out departures and taken seats, which a retry cannot fix.
+func ServiceConfig(cfg Config) string {
+	methods := make([]string, 0, len(cfg.Timeouts))
+	for m := range cfg.Timeouts {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+
+	var mcs []methodConfig
+	for _, m := range methods {
+		mc := methodConfig{
+			Name:    []methodName{{Service: service, Method: m}},
+			Timeout: fmt.Sprintf("%.3fs", cfg.Timeouts[m].Seconds()),
+		}
+		if contains(retried, m) && cfg.MaxAttempts > 1 {
+			mc.RetryPolicy = &retryPolicy{
+				MaxAttempts:          cfg.MaxAttempts,
+				InitialBackoff:       "0.1s",
+				MaxBackoff:           "1s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			}
+		}
+		mcs = append(mcs, mc)
+	}
+
+	b, _ := json.Marshal(map[string]any{
+		"methodConfig": mcs,
+		// Pause retries after a burst of failures until calls succeed again.
+		"retryThrottling": map[string]any{"maxTokens": 10, "tokenRatio": 0.1},
+	})
+	return string(b)
+}
+
+// DialOptions returns the options every TicketReservation client should dial
+// with, in addition to its transport credentials. extra interceptors see each
+// call once, before hedging may fan it out into several attempts.
+func DialOptions(cfg Config, extra ...grpc.UnaryClientInterceptor) []grpc.DialOption {
+	hedgedMethods := make(map[string]bool, len(hedged))
+	for _, m := range hedged {
+		hedgedMethods["/"+service+"/"+m] = true
+	}
+	interceptors := append([]grpc.UnaryClientInterceptor{logging.UnaryClientInterceptor()}, extra...)
+	interceptors = append(interceptors, HedgingInterceptor(hedgedMethods, cfg.HedgeDelay, cfg.MaxAttempts))
+
+	bc := backoff.DefaultConfig
+	bc.MaxDelay = cfg.MaxBackoff
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(ServiceConfig(cfg)),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: bc, MinConnectTimeout: 5 * time.Second}),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Package tickettoken issues and verifies the signed tokens printed on
+// tickets. A token is "TKT2.<claims>.<signature>": base64url JSON claims
+// signed with Ed25519, so anyone holding the public key can check a ticket
+// without reaching the server.
+package tickettoken
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const prefix = "TKT2"
+
+var (
+	ErrMalformed    = errors.New("not a ticket token")
+	ErrSignature    = errors.New("signature does not match")
+	ErrNotYetValid  = errors.New("ticket is not valid yet")
+	ErrExpired      = errors.New("ticket has expired")
+	ErrUnknownKeyID = errors.New("token was signed with another key")
+)
+
+// Claims is what a token says about one passenger's seat.
+type Claims struct {
+	TicketNo    uint64 `json:"t"`
+	DepartureID uint64 `json:"d"`
+	Position    uint32 `json:"p"`
+	Section     string `json:"sec"`
+	Seat        uint32 `json:"seat"`
+	// NotBefore and Expires are Unix seconds.
+	NotBefore int64 `json:"nbf"`
+	Expires   int64 `json:"exp"`
+	// KeyID names the signing key, so keys can be rotated.
+	KeyID string `json:"kid"`
+}
+
+func (c Claims) ValidFrom() time.Time  { return time.Unix(c.NotBefore, 0) }
+func (c Claims) ValidUntil() time.Time { return time.Unix(c.Expires, 0) }
+
+// CheckTime reports whether the token may be used at now.
+func (c Claims) CheckTime(now time.Time) error {
+	switch {
+	case now.Before(c.ValidFrom()):
+		return ErrNotYetValid
+	case !now.Before(c.ValidUntil()):
+		return ErrExpired
+	}
+	return nil
+}
+
+// KeyID is a short fingerprint of a public key.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:4])
+}
+
+// Sign returns the token for c. KeyID is filled in from the key.
+func Sign(priv ed25519.PrivateKey, c Claims) string {
+	c.KeyID = KeyID(priv.Public().(ed25519.PublicKey))
+	b, _ := json.Marshal(c)
+	payload := prefix + "." + base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(priv, []byte(payload)))
+}
+
+// Parse decodes the claims without checking the signature.
+func Parse(token string) (Claims, error) {
+	var c Claims
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 || parts[0] != prefix {
+		return c, ErrMalformed
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(b, &c) != nil {
+		return c, ErrMalformed
+	}
+	return c, nil
+}
+
+// Verify checks the signature of token and returns its claims. It does not
+// check the validity window; see Claims.CheckTime.
+func Verify(pub ed25519.PublicKey, token string) (Claims, error) {
+	c, err := Parse(token)
+	if err != nil {
+		return c, err
+	}
+	if c.KeyID != KeyID(pub) {
+		return c, ErrUnknownKeyID
+	}
+	token = strings.TrimSpace(token)
+	i := strings.LastIndexByte(token, '.')
+	sig, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil || !ed25519.Verify(pub, []byte(token[:i]), sig) {
+		return c, ErrSignature
+	}
+	return c, nil
+}
+
+// ParsePrivateKey reads a base64 Ed25519 seed (32 bytes) or private key
+// (64 bytes).
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("ticket signing key: %v", err)
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	}
+	return nil, fmt.Errorf("ticket signing key: want %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(b))
+}
+
+// ParsePublicKey reads a base64 Ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("ticket public key: %v", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("ticket public key: want %d bytes, got %d", ed25519.PublicKeySize, len(b))
+	}
+	return ed25519.PublicKey(b), nil
+}
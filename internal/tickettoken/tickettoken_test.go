@@ -0,0 +1,162 @@
+package tickettoken
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(seed byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+}
+
+var departs = time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+
+func testClaims() Claims {
+	return Claims{
+		TicketNo:    42,
+		DepartureID: 7,
+		Position:    1,
+		Section:     "A",
+		Seat:        12,
+		NotBefore:   departs.Add(-24 * time.Hour).Unix(),
+		Expires:     departs.Add(3 * time.Hour).Unix(),
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	priv := testKey(1)
+	pub := priv.Public().(ed25519.PublicKey)
+	want := testClaims()
+	want.KeyID = KeyID(pub)
+
+	token := Sign(priv, testClaims())
+	if !strings.HasPrefix(token, prefix+".") {
+		t.Fatalf("token %q does not start with %s.", token, prefix)
+	}
+	got, err := Verify(pub, token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got != want {
+		t.Errorf("Verify claims = %+v, want %+v", got, want)
+	}
+	// Scanners may pick up surrounding whitespace.
+	if _, err := Verify(pub, " "+token+"\n"); err != nil {
+		t.Errorf("Verify with whitespace: %v", err)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	priv := testKey(1)
+	pub := priv.Public().(ed25519.PublicKey)
+	token := Sign(priv, testClaims())
+	parts := strings.Split(token, ".")
+
+	// The same claims with another seat, keeping the original signature.
+	c := testClaims()
+	c.Seat = 13
+	c.KeyID = KeyID(pub)
+	b, _ := json.Marshal(c)
+	otherSeat := parts[0] + "." + base64.RawURLEncoding.EncodeToString(b) + "." + parts[2]
+
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	sig[0] ^= 1
+	flippedSig := parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(sig)
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"changed claims", otherSeat, ErrSignature},
+		{"changed signature", flippedSig, ErrSignature},
+		{"signature not base64", parts[0] + "." + parts[1] + ".!!", ErrSignature},
+		{"no signature", parts[0] + "." + parts[1], ErrMalformed},
+		{"other prefix", "TKT1." + parts[1] + "." + parts[2], ErrMalformed},
+		{"claims not JSON", parts[0] + ".bm90IGpzb24." + parts[2], ErrMalformed},
+		{"empty", "", ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(pub, tt.token); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyWrongKey(t *testing.T) {
+	priv, other := testKey(1), testKey(2)
+	otherPub := other.Public().(ed25519.PublicKey)
+
+	token := Sign(priv, testClaims())
+	if _, err := Verify(otherPub, token); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("Verify with another key = %v, want %v", err, ErrUnknownKeyID)
+	}
+
+	// A token signed with another key but naming ours fails on the
+	// signature.
+	c := testClaims()
+	c.KeyID = KeyID(otherPub)
+	b, _ := json.Marshal(c)
+	payload := prefix + "." + base64.RawURLEncoding.EncodeToString(b)
+	forged := payload + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(priv, []byte(payload)))
+	if _, err := Verify(otherPub, forged); !errors.Is(err, ErrSignature) {
+		t.Errorf("Verify forged key ID = %v, want %v", err, ErrSignature)
+	}
+}
+
+func TestCheckTime(t *testing.T) {
+	c := testClaims()
+	tests := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		{"before window", c.ValidFrom().Add(-time.Second), ErrNotYetValid},
+		{"window opens", c.ValidFrom(), nil},
+		{"at departure", departs, nil},
+		{"last second", c.ValidUntil().Add(-time.Second), nil},
+		{"expires", c.ValidUntil(), ErrExpired},
+		{"after expiry", c.ValidUntil().Add(time.Hour), ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.CheckTime(tt.now); err != tt.want {
+				t.Errorf("CheckTime(%v) = %v, want %v", tt.now, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	priv := testKey(3)
+	pub := priv.Public().(ed25519.PublicKey)
+	enc := base64.StdEncoding.EncodeToString
+
+	for name, s := range map[string]string{"seed": enc(priv.Seed()), "private key": enc(priv)} {
+		got, err := ParsePrivateKey(s)
+		if err != nil {
+			t.Errorf("ParsePrivateKey(%s): %v", name, err)
+		} else if !got.Equal(priv) {
+			t.Errorf("ParsePrivateKey(%s) returned another key", name)
+		}
+	}
+	if got, err := ParsePublicKey(enc(pub)); err != nil || !got.Equal(pub) {
+		t.Errorf("ParsePublicKey = %v, %v", got, err)
+	}
+	for _, s := range []string{"", "not base64!", enc([]byte("short"))} {
+		if _, err := ParsePrivateKey(s); err == nil {
+			t.Errorf("ParsePrivateKey(%q) succeeded", s)
+		}
+		if _, err := ParsePublicKey(s); err == nil {
+			t.Errorf("ParsePublicKey(%q) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,2004 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.26.1
+// source: proto/ticket_admin.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WebhookDelivery_Status int32
+
+const (
+	WebhookDelivery_PENDING   WebhookDelivery_Status = 0
+	WebhookDelivery_DELIVERED WebhookDelivery_Status = 1
+	// Given up after WEBHOOK_MAX_ATTEMPTS failed attempts.
+	WebhookDelivery_DEAD WebhookDelivery_Status = 2
+)
+
+// Enum value maps for WebhookDelivery_Status.
+var (
+	WebhookDelivery_Status_name = map[int32]string{
+		0: "PENDING",
+		1: "DELIVERED",
+		2: "DEAD",
+	}
+	WebhookDelivery_Status_value = map[string]int32{
+		"PENDING":   0,
+		"DELIVERED": 1,
+		"DEAD":      2,
+	}
+)
+
+func (x WebhookDelivery_Status) Enum() *WebhookDelivery_Status {
+	p := new(WebhookDelivery_Status)
+	*p = x
+	return p
+}
+
+func (x WebhookDelivery_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WebhookDelivery_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (WebhookDelivery_Status) Type() protoreflect.EnumType {
+	return &file_proto_ticket_admin_proto_enumTypes[0]
+}
+
+func (x WebhookDelivery_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WebhookDelivery_Status.Descriptor instead.
+func (WebhookDelivery_Status) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type Coach_Numbering int32
+
+const (
+	// Row by row from the front, left to right.
+	Coach_FROM_FRONT Coach_Numbering = 0
+	// Row by row from the back, left to right.
+	Coach_FROM_BACK Coach_Numbering = 1
+)
+
+// Enum value maps for Coach_Numbering.
+var (
+	Coach_Numbering_name = map[int32]string{
+		0: "FROM_FRONT",
+		1: "FROM_BACK",
+	}
+	Coach_Numbering_value = map[string]int32{
+		"FROM_FRONT": 0,
+		"FROM_BACK":  1,
+	}
+)
+
+func (x Coach_Numbering) Enum() *Coach_Numbering {
+	p := new(Coach_Numbering)
+	*p = x
+	return p
+}
+
+func (x Coach_Numbering) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Coach_Numbering) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_admin_proto_enumTypes[1].Descriptor()
+}
+
+func (Coach_Numbering) Type() protoreflect.EnumType {
+	return &file_proto_ticket_admin_proto_enumTypes[1]
+}
+
+func (x Coach_Numbering) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Coach_Numbering.Descriptor instead.
+func (Coach_Numbering) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{16, 0}
+}
+
+type HoldList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Holds         []*Hold                `protobuf:"bytes,1,rep,name=holds,proto3" json:"holds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldList) Reset() {
+	*x = HoldList{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldList) ProtoMessage() {}
+
+func (x *HoldList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldList.ProtoReflect.Descriptor instead.
+func (*HoldList) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HoldList) GetHolds() []*Hold {
+	if x != nil {
+		return x.Holds
+	}
+	return nil
+}
+
+type ExpireHoldsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldIds       []string               `protobuf:"bytes,1,rep,name=hold_ids,json=holdIds,proto3" json:"hold_ids,omitempty"`
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireHoldsRequest) Reset() {
+	*x = ExpireHoldsRequest{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireHoldsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireHoldsRequest) ProtoMessage() {}
+
+func (x *ExpireHoldsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireHoldsRequest.ProtoReflect.Descriptor instead.
+func (*ExpireHoldsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExpireHoldsRequest) GetHoldIds() []string {
+	if x != nil {
+		return x.HoldIds
+	}
+	return nil
+}
+
+func (x *ExpireHoldsRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type ExpireHoldsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expired       uint32                 `protobuf:"varint,1,opt,name=expired,proto3" json:"expired,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireHoldsResponse) Reset() {
+	*x = ExpireHoldsResponse{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireHoldsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireHoldsResponse) ProtoMessage() {}
+
+func (x *ExpireHoldsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireHoldsResponse.ProtoReflect.Descriptor instead.
+func (*ExpireHoldsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExpireHoldsResponse) GetExpired() uint32 {
+	if x != nil {
+		return x.Expired
+	}
+	return 0
+}
+
+type ReindexSeatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Counted per leg of each seat, so a seat of a run with three stops
+	// counts twice.
+	Seats    uint32 `protobuf:"varint,1,opt,name=seats,proto3" json:"seats,omitempty"`
+	Occupied uint32 `protobuf:"varint,2,opt,name=occupied,proto3" json:"occupied,omitempty"`
+	Held     uint32 `protobuf:"varint,3,opt,name=held,proto3" json:"held,omitempty"`
+	// Tickets that claim a seat already taken by another ticket or a seat that
+	// does not exist. They are left out of the index.
+	ConflictingTickets []uint64 `protobuf:"varint,4,rep,packed,name=conflicting_tickets,json=conflictingTickets,proto3" json:"conflicting_tickets,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ReindexSeatsResponse) Reset() {
+	*x = ReindexSeatsResponse{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReindexSeatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReindexSeatsResponse) ProtoMessage() {}
+
+func (x *ReindexSeatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReindexSeatsResponse.ProtoReflect.Descriptor instead.
+func (*ReindexSeatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReindexSeatsResponse) GetSeats() uint32 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+func (x *ReindexSeatsResponse) GetOccupied() uint32 {
+	if x != nil {
+		return x.Occupied
+	}
+	return 0
+}
+
+func (x *ReindexSeatsResponse) GetHeld() uint32 {
+	if x != nil {
+		return x.Held
+	}
+	return 0
+}
+
+func (x *ReindexSeatsResponse) GetConflictingTickets() []uint64 {
+	if x != nil {
+		return x.ConflictingTickets
+	}
+	return nil
+}
+
+type SetAccountRoleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Email string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// "customer" or "admin".
+	Role          string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAccountRoleRequest) Reset() {
+	*x = SetAccountRoleRequest{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAccountRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAccountRoleRequest) ProtoMessage() {}
+
+func (x *SetAccountRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAccountRoleRequest.ProtoReflect.Descriptor instead.
+func (*SetAccountRoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetAccountRoleRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SetAccountRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type Webhook struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId uint64                 `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	// http or https URL the events are POSTed to.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Domain event types to deliver, e.g. TicketCancelled; empty for all.
+	EventTypes []string `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	// Only deliver events of tickets booked by this account, e.g. a travel
+	// agency's; zero for every ticket.
+	AccountId uint64 `protobuf:"varint,4,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Key of the HMAC-SHA256 in the X-Signature header. CreateWebhook makes
+	// one up when it is empty.
+	Secret        string                 `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Webhook) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type WebhookList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookList) Reset() {
+	*x = WebhookList{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookList) ProtoMessage() {}
+
+func (x *WebhookList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookList.ProtoReflect.Descriptor instead.
+func (*WebhookList) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WebhookList) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type WebhookQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     uint64                 `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookQuery) Reset() {
+	*x = WebhookQuery{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookQuery) ProtoMessage() {}
+
+func (x *WebhookQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookQuery.ProtoReflect.Descriptor instead.
+func (*WebhookQuery) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WebhookQuery) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+type WebhookDelivery struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId uint64                 `protobuf:"varint,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	WebhookId  uint64                 `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Url        string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	EventId    uint64                 `protobuf:"varint,4,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType  string                 `protobuf:"bytes,5,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	TicketNo   uint64                 `protobuf:"varint,6,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	Status     WebhookDelivery_Status `protobuf:"varint,7,opt,name=status,proto3,enum=ticket_reservation.WebhookDelivery_Status" json:"status,omitempty"`
+	Attempts   uint32                 `protobuf:"varint,8,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// HTTP status of the last attempt; zero if it got no answer.
+	LastStatusCode uint32                 `protobuf:"varint,9,opt,name=last_status_code,json=lastStatusCode,proto3" json:"last_status_code,omitempty"`
+	LastError      string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When a PENDING delivery is tried next.
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	DeliveredAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WebhookDelivery) GetDeliveryId() uint64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetStatus() WebhookDelivery_Status {
+	if x != nil {
+		return x.Status
+	}
+	return WebhookDelivery_PENDING
+}
+
+func (x *WebhookDelivery) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastStatusCode() uint32 {
+	if x != nil {
+		return x.LastStatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+type WebhookDeliveryQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limits ListWebhookDeliveries to one subscription.
+	WebhookId uint64 `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	// Limits ListWebhookDeliveries to one status, e.g. DEAD for the
+	// dead-letter list.
+	Status *WebhookDelivery_Status `protobuf:"varint,2,opt,name=status,proto3,enum=ticket_reservation.WebhookDelivery_Status,oneof" json:"status,omitempty"`
+	// At most this many deliveries; 50 when zero, at most 500.
+	Limit uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The delivery RetryWebhookDelivery sends again.
+	DeliveryId    uint64 `protobuf:"varint,4,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDeliveryQuery) Reset() {
+	*x = WebhookDeliveryQuery{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDeliveryQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeliveryQuery) ProtoMessage() {}
+
+func (x *WebhookDeliveryQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeliveryQuery.ProtoReflect.Descriptor instead.
+func (*WebhookDeliveryQuery) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WebhookDeliveryQuery) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDeliveryQuery) GetStatus() WebhookDelivery_Status {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return WebhookDelivery_PENDING
+}
+
+func (x *WebhookDeliveryQuery) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *WebhookDeliveryQuery) GetDeliveryId() uint64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+type WebhookDeliveryList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDeliveryList) Reset() {
+	*x = WebhookDeliveryList{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDeliveryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeliveryList) ProtoMessage() {}
+
+func (x *WebhookDeliveryList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeliveryList.ProtoReflect.Descriptor instead.
+func (*WebhookDeliveryList) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WebhookDeliveryList) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type RebuildProjectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DryRun        bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildProjectionsRequest) Reset() {
+	*x = RebuildProjectionsRequest{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildProjectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildProjectionsRequest) ProtoMessage() {}
+
+func (x *RebuildProjectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildProjectionsRequest.ProtoReflect.Descriptor instead.
+func (*RebuildProjectionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RebuildProjectionsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type RebuildProjectionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tickets the ledger holds, cancelled ones included.
+	Tickets uint32 `protobuf:"varint,1,opt,name=tickets,proto3" json:"tickets,omitempty"`
+	// Tickets whose rows were missing or differed from the ledger and were
+	// written again.
+	Rewritten []uint64 `protobuf:"varint,2,rep,packed,name=rewritten,proto3" json:"rewritten,omitempty"`
+	// Cancelled tickets that were still in the tickets table.
+	Deleted []uint64 `protobuf:"varint,3,rep,packed,name=deleted,proto3" json:"deleted,omitempty"`
+	// Tickets in the tickets table the ledger knows nothing of. They are
+	// left alone.
+	Unrecorded    []uint64              `protobuf:"varint,4,rep,packed,name=unrecorded,proto3" json:"unrecorded,omitempty"`
+	Seats         *ReindexSeatsResponse `protobuf:"bytes,5,opt,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildProjectionsResponse) Reset() {
+	*x = RebuildProjectionsResponse{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildProjectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildProjectionsResponse) ProtoMessage() {}
+
+func (x *RebuildProjectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildProjectionsResponse.ProtoReflect.Descriptor instead.
+func (*RebuildProjectionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RebuildProjectionsResponse) GetTickets() uint32 {
+	if x != nil {
+		return x.Tickets
+	}
+	return 0
+}
+
+func (x *RebuildProjectionsResponse) GetRewritten() []uint64 {
+	if x != nil {
+		return x.Rewritten
+	}
+	return nil
+}
+
+func (x *RebuildProjectionsResponse) GetDeleted() []uint64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *RebuildProjectionsResponse) GetUnrecorded() []uint64 {
+	if x != nil {
+		return x.Unrecorded
+	}
+	return nil
+}
+
+func (x *RebuildProjectionsResponse) GetSeats() *ReindexSeatsResponse {
+	if x != nil {
+		return x.Seats
+	}
+	return nil
+}
+
+type SeatOccupancyRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DepartureId uint64                 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// Unset for now.
+	At *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	// Limits the answer to one coach, or to one seat of it.
+	Section       string `protobuf:"bytes,3,opt,name=section,proto3" json:"section,omitempty"`
+	Seat          uint32 `protobuf:"varint,4,opt,name=seat,proto3" json:"seat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatOccupancyRequest) Reset() {
+	*x = SeatOccupancyRequest{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatOccupancyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatOccupancyRequest) ProtoMessage() {}
+
+func (x *SeatOccupancyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatOccupancyRequest.ProtoReflect.Descriptor instead.
+func (*SeatOccupancyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SeatOccupancyRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *SeatOccupancyRequest) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *SeatOccupancyRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *SeatOccupancyRequest) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+type SeatOccupancy struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DepartureId uint64                 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	At          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	// Occupied seats, ordered by section, then seat.
+	Seats         []*SeatOccupancy_Seat `protobuf:"bytes,3,rep,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatOccupancy) Reset() {
+	*x = SeatOccupancy{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatOccupancy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatOccupancy) ProtoMessage() {}
+
+func (x *SeatOccupancy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatOccupancy.ProtoReflect.Descriptor instead.
+func (*SeatOccupancy) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SeatOccupancy) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *SeatOccupancy) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *SeatOccupancy) GetSeats() []*SeatOccupancy_Seat {
+	if x != nil {
+		return x.Seats
+	}
+	return nil
+}
+
+// Layout describes the coaches of a train. Departures without one use the
+// SECTIONS, SEATS_PER_SECTION and QUIET_SECTIONS settings.
+type Layout struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Set by the server, starting at 1.
+	Version uint32   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Coaches []*Coach `protobuf:"bytes,3,rep,name=coaches,proto3" json:"coaches,omitempty"`
+	// Seats that can be sold, i.e. not blocked. Set by the server.
+	Capacity      uint32                 `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Layout) Reset() {
+	*x = Layout{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Layout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Layout) ProtoMessage() {}
+
+func (x *Layout) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Layout.ProtoReflect.Descriptor instead.
+func (*Layout) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Layout) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Layout) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Layout) GetCoaches() []*Coach {
+	if x != nil {
+		return x.Coaches
+	}
+	return nil
+}
+
+func (x *Layout) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Layout) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Coach struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The section passengers book, e.g. "A".
+	Section string `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	// Travel class, e.g. "first". Defaults to "standard".
+	Class string `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	Rows  uint32 `protobuf:"varint,3,opt,name=rows,proto3" json:"rows,omitempty"`
+	// The seats across a row from left to right: W for window, A for aisle,
+	// M for middle, and _ for the gangway. Defaults to "WA_AW".
+	Row       string          `protobuf:"bytes,4,opt,name=row,proto3" json:"row,omitempty"`
+	Numbering Coach_Numbering `protobuf:"varint,5,opt,name=numbering,proto3,enum=ticket_reservation.Coach_Numbering" json:"numbering,omitempty"`
+	// Number of the first seat. Defaults to 1.
+	FirstSeat uint32 `protobuf:"varint,6,opt,name=first_seat,json=firstSeat,proto3" json:"first_seat,omitempty"`
+	// Seats in the coach when the last numbered row is not full. Defaults to
+	// every place of every row.
+	Seats uint32 `protobuf:"varint,7,opt,name=seats,proto3" json:"seats,omitempty"`
+	Quiet bool   `protobuf:"varint,8,opt,name=quiet,proto3" json:"quiet,omitempty"`
+	// How many rows from the front face the direction of travel. Defaults to
+	// the front half.
+	ForwardRows *uint32 `protobuf:"varint,9,opt,name=forward_rows,json=forwardRows,proto3,oneof" json:"forward_rows,omitempty"`
+	// Rows by the doors, counted from 1 at the front.
+	ExitRows []uint32 `protobuf:"varint,10,rep,packed,name=exit_rows,json=exitRows,proto3" json:"exit_rows,omitempty"`
+	// Priority seats with room for mobility aids.
+	AccessibleSeats []uint32 `protobuf:"varint,11,rep,packed,name=accessible_seats,json=accessibleSeats,proto3" json:"accessible_seats,omitempty"`
+	// Seats that are never sold, e.g. staff seats or broken ones.
+	BlockedSeats  []uint32 `protobuf:"varint,12,rep,packed,name=blocked_seats,json=blockedSeats,proto3" json:"blocked_seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Coach) Reset() {
+	*x = Coach{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coach) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coach) ProtoMessage() {}
+
+func (x *Coach) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coach.ProtoReflect.Descriptor instead.
+func (*Coach) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Coach) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Coach) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *Coach) GetRows() uint32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *Coach) GetRow() string {
+	if x != nil {
+		return x.Row
+	}
+	return ""
+}
+
+func (x *Coach) GetNumbering() Coach_Numbering {
+	if x != nil {
+		return x.Numbering
+	}
+	return Coach_FROM_FRONT
+}
+
+func (x *Coach) GetFirstSeat() uint32 {
+	if x != nil {
+		return x.FirstSeat
+	}
+	return 0
+}
+
+func (x *Coach) GetSeats() uint32 {
+	if x != nil {
+		return x.Seats
+	}
+	return 0
+}
+
+func (x *Coach) GetQuiet() bool {
+	if x != nil {
+		return x.Quiet
+	}
+	return false
+}
+
+func (x *Coach) GetForwardRows() uint32 {
+	if x != nil && x.ForwardRows != nil {
+		return *x.ForwardRows
+	}
+	return 0
+}
+
+func (x *Coach) GetExitRows() []uint32 {
+	if x != nil {
+		return x.ExitRows
+	}
+	return nil
+}
+
+func (x *Coach) GetAccessibleSeats() []uint32 {
+	if x != nil {
+		return x.AccessibleSeats
+	}
+	return nil
+}
+
+func (x *Coach) GetBlockedSeats() []uint32 {
+	if x != nil {
+		return x.BlockedSeats
+	}
+	return nil
+}
+
+type PutLayoutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A Layout as YAML or JSON, without version.
+	Definition    string `protobuf:"bytes,1,opt,name=definition,proto3" json:"definition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutLayoutRequest) Reset() {
+	*x = PutLayoutRequest{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutLayoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutLayoutRequest) ProtoMessage() {}
+
+func (x *PutLayoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutLayoutRequest.ProtoReflect.Descriptor instead.
+func (*PutLayoutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PutLayoutRequest) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
+type LayoutQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Zero means the latest version.
+	Version       uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LayoutQuery) Reset() {
+	*x = LayoutQuery{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LayoutQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LayoutQuery) ProtoMessage() {}
+
+func (x *LayoutQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LayoutQuery.ProtoReflect.Descriptor instead.
+func (*LayoutQuery) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LayoutQuery) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LayoutQuery) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type LayoutList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Layouts       []*Layout              `protobuf:"bytes,1,rep,name=layouts,proto3" json:"layouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LayoutList) Reset() {
+	*x = LayoutList{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LayoutList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LayoutList) ProtoMessage() {}
+
+func (x *LayoutList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LayoutList.ProtoReflect.Descriptor instead.
+func (*LayoutList) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LayoutList) GetLayouts() []*Layout {
+	if x != nil {
+		return x.Layouts
+	}
+	return nil
+}
+
+type AttachLayoutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Zero means the latest version.
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Either a departure or a train.
+	DepartureId   uint64 `protobuf:"varint,3,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	Train         string `protobuf:"bytes,4,opt,name=train,proto3" json:"train,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachLayoutRequest) Reset() {
+	*x = AttachLayoutRequest{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachLayoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachLayoutRequest) ProtoMessage() {}
+
+func (x *AttachLayoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachLayoutRequest.ProtoReflect.Descriptor instead.
+func (*AttachLayoutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AttachLayoutRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AttachLayoutRequest) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *AttachLayoutRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *AttachLayoutRequest) GetTrain() string {
+	if x != nil {
+		return x.Train
+	}
+	return ""
+}
+
+type AttachLayoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DepartureIds  []uint64               `protobuf:"varint,1,rep,packed,name=departure_ids,json=departureIds,proto3" json:"departure_ids,omitempty"`
+	SeatsAdded    uint32                 `protobuf:"varint,2,opt,name=seats_added,json=seatsAdded,proto3" json:"seats_added,omitempty"`
+	SeatsRemoved  uint32                 `protobuf:"varint,3,opt,name=seats_removed,json=seatsRemoved,proto3" json:"seats_removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachLayoutResponse) Reset() {
+	*x = AttachLayoutResponse{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachLayoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachLayoutResponse) ProtoMessage() {}
+
+func (x *AttachLayoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachLayoutResponse.ProtoReflect.Descriptor instead.
+func (*AttachLayoutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AttachLayoutResponse) GetDepartureIds() []uint64 {
+	if x != nil {
+		return x.DepartureIds
+	}
+	return nil
+}
+
+func (x *AttachLayoutResponse) GetSeatsAdded() uint32 {
+	if x != nil {
+		return x.SeatsAdded
+	}
+	return 0
+}
+
+func (x *AttachLayoutResponse) GetSeatsRemoved() uint32 {
+	if x != nil {
+		return x.SeatsRemoved
+	}
+	return 0
+}
+
+type SeatOccupancy_Seat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	Seat          uint32                 `protobuf:"varint,2,opt,name=seat,proto3" json:"seat,omitempty"`
+	TicketNo      uint64                 `protobuf:"varint,3,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	Position      uint32                 `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	PassengerName string                 `protobuf:"bytes,5,opt,name=passenger_name,json=passengerName,proto3" json:"passenger_name,omitempty"`
+	// Status of the ticket at the time.
+	Status string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// The last ledger event of the ticket before the time, and when it was
+	// recorded.
+	EventId    uint64                 `protobuf:"varint,7,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType  string                 `protobuf:"bytes,8,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	RecordedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	// Where the passenger boards and alights.
+	FromCode      string `protobuf:"bytes,10,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string `protobuf:"bytes,11,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatOccupancy_Seat) Reset() {
+	*x = SeatOccupancy_Seat{}
+	mi := &file_proto_ticket_admin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatOccupancy_Seat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatOccupancy_Seat) ProtoMessage() {}
+
+func (x *SeatOccupancy_Seat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_admin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatOccupancy_Seat.ProtoReflect.Descriptor instead.
+func (*SeatOccupancy_Seat) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_admin_proto_rawDescGZIP(), []int{14, 0}
+}
+
+func (x *SeatOccupancy_Seat) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *SeatOccupancy_Seat) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *SeatOccupancy_Seat) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *SeatOccupancy_Seat) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *SeatOccupancy_Seat) GetPassengerName() string {
+	if x != nil {
+		return x.PassengerName
+	}
+	return ""
+}
+
+func (x *SeatOccupancy_Seat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SeatOccupancy_Seat) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *SeatOccupancy_Seat) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *SeatOccupancy_Seat) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+func (x *SeatOccupancy_Seat) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *SeatOccupancy_Seat) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+var File_proto_ticket_admin_proto protoreflect.FileDescriptor
+
+const file_proto_ticket_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x18proto/ticket_admin.proto\x12\x12ticket_reservation\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1eproto/ticket_reservation.proto\":\n" +
+	"\bHoldList\x12.\n" +
+	"\x05holds\x18\x01 \x03(\v2\x18.ticket_reservation.HoldR\x05holds\"A\n" +
+	"\x12ExpireHoldsRequest\x12\x19\n" +
+	"\bhold_ids\x18\x01 \x03(\tR\aholdIds\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"/\n" +
+	"\x13ExpireHoldsResponse\x12\x18\n" +
+	"\aexpired\x18\x01 \x01(\rR\aexpired\"\x8d\x01\n" +
+	"\x14ReindexSeatsResponse\x12\x14\n" +
+	"\x05seats\x18\x01 \x01(\rR\x05seats\x12\x1a\n" +
+	"\boccupied\x18\x02 \x01(\rR\boccupied\x12\x12\n" +
+	"\x04held\x18\x03 \x01(\rR\x04held\x12/\n" +
+	"\x13conflicting_tickets\x18\x04 \x03(\x04R\x12conflictingTickets\"A\n" +
+	"\x15SetAccountRoleRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"\xcd\x01\n" +
+	"\aWebhook\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\x04R\twebhookId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x04 \x01(\x04R\taccountId\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\tR\x06secret\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"F\n" +
+	"\vWebhookList\x127\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x1b.ticket_reservation.WebhookR\bwebhooks\"-\n" +
+	"\fWebhookQuery\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\x04R\twebhookId\"\xd1\x04\n" +
+	"\x0fWebhookDelivery\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\x04R\n" +
+	"deliveryId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x04R\twebhookId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x19\n" +
+	"\bevent_id\x18\x04 \x01(\x04R\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x05 \x01(\tR\teventType\x12\x1b\n" +
+	"\tticket_no\x18\x06 \x01(\x04R\bticketNo\x12B\n" +
+	"\x06status\x18\a \x01(\x0e2*.ticket_reservation.WebhookDelivery.StatusR\x06status\x12\x1a\n" +
+	"\battempts\x18\b \x01(\rR\battempts\x12(\n" +
+	"\x10last_status_code\x18\t \x01(\rR\x0elastStatusCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12B\n" +
+	"\x0fnext_attempt_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12=\n" +
+	"\fdelivered_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\".\n" +
+	"\x06Status\x12\v\n" +
+	"\aPENDING\x10\x00\x12\r\n" +
+	"\tDELIVERED\x10\x01\x12\b\n" +
+	"\x04DEAD\x10\x02\"\xc0\x01\n" +
+	"\x14WebhookDeliveryQuery\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\x04R\twebhookId\x12G\n" +
+	"\x06status\x18\x02 \x01(\x0e2*.ticket_reservation.WebhookDelivery.StatusH\x00R\x06status\x88\x01\x01\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\x12\x1f\n" +
+	"\vdelivery_id\x18\x04 \x01(\x04R\n" +
+	"deliveryIdB\t\n" +
+	"\a_status\"Z\n" +
+	"\x13WebhookDeliveryList\x12C\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2#.ticket_reservation.WebhookDeliveryR\n" +
+	"deliveries\"4\n" +
+	"\x19RebuildProjectionsRequest\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\"\xce\x01\n" +
+	"\x1aRebuildProjectionsResponse\x12\x18\n" +
+	"\atickets\x18\x01 \x01(\rR\atickets\x12\x1c\n" +
+	"\trewritten\x18\x02 \x03(\x04R\trewritten\x12\x18\n" +
+	"\adeleted\x18\x03 \x03(\x04R\adeleted\x12\x1e\n" +
+	"\n" +
+	"unrecorded\x18\x04 \x03(\x04R\n" +
+	"unrecorded\x12>\n" +
+	"\x05seats\x18\x05 \x01(\v2(.ticket_reservation.ReindexSeatsResponseR\x05seats\"\x93\x01\n" +
+	"\x14SeatOccupancyRequest\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x18\n" +
+	"\asection\x18\x03 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x04 \x01(\rR\x04seat\"\xf8\x03\n" +
+	"\rSeatOccupancy\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12<\n" +
+	"\x05seats\x18\x03 \x03(\v2&.ticket_reservation.SeatOccupancy.SeatR\x05seats\x1a\xd9\x02\n" +
+	"\x04Seat\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x02 \x01(\rR\x04seat\x12\x1b\n" +
+	"\tticket_no\x18\x03 \x01(\x04R\bticketNo\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\rR\bposition\x12%\n" +
+	"\x0epassenger_name\x18\x05 \x01(\tR\rpassengerName\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x19\n" +
+	"\bevent_id\x18\a \x01(\x04R\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\b \x01(\tR\teventType\x12;\n" +
+	"\vrecorded_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAt\x12\x1b\n" +
+	"\tfrom_code\x18\n" +
+	" \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\v \x01(\tR\x06toCode\"\xc2\x01\n" +
+	"\x06Layout\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\x123\n" +
+	"\acoaches\x18\x03 \x03(\v2\x19.ticket_reservation.CoachR\acoaches\x12\x1a\n" +
+	"\bcapacity\x18\x04 \x01(\rR\bcapacity\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xbd\x03\n" +
+	"\x05Coach\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x14\n" +
+	"\x05class\x18\x02 \x01(\tR\x05class\x12\x12\n" +
+	"\x04rows\x18\x03 \x01(\rR\x04rows\x12\x10\n" +
+	"\x03row\x18\x04 \x01(\tR\x03row\x12A\n" +
+	"\tnumbering\x18\x05 \x01(\x0e2#.ticket_reservation.Coach.NumberingR\tnumbering\x12\x1d\n" +
+	"\n" +
+	"first_seat\x18\x06 \x01(\rR\tfirstSeat\x12\x14\n" +
+	"\x05seats\x18\a \x01(\rR\x05seats\x12\x14\n" +
+	"\x05quiet\x18\b \x01(\bR\x05quiet\x12&\n" +
+	"\fforward_rows\x18\t \x01(\rH\x00R\vforwardRows\x88\x01\x01\x12\x1b\n" +
+	"\texit_rows\x18\n" +
+	" \x03(\rR\bexitRows\x12)\n" +
+	"\x10accessible_seats\x18\v \x03(\rR\x0faccessibleSeats\x12#\n" +
+	"\rblocked_seats\x18\f \x03(\rR\fblockedSeats\"*\n" +
+	"\tNumbering\x12\x0e\n" +
+	"\n" +
+	"FROM_FRONT\x10\x00\x12\r\n" +
+	"\tFROM_BACK\x10\x01B\x0f\n" +
+	"\r_forward_rows\"2\n" +
+	"\x10PutLayoutRequest\x12\x1e\n" +
+	"\n" +
+	"definition\x18\x01 \x01(\tR\n" +
+	"definition\";\n" +
+	"\vLayoutQuery\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\"B\n" +
+	"\n" +
+	"LayoutList\x124\n" +
+	"\alayouts\x18\x01 \x03(\v2\x1a.ticket_reservation.LayoutR\alayouts\"|\n" +
+	"\x13AttachLayoutRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\x12!\n" +
+	"\fdeparture_id\x18\x03 \x01(\x04R\vdepartureId\x12\x14\n" +
+	"\x05train\x18\x04 \x01(\tR\x05train\"\x81\x01\n" +
+	"\x14AttachLayoutResponse\x12#\n" +
+	"\rdeparture_ids\x18\x01 \x03(\x04R\fdepartureIds\x12\x1f\n" +
+	"\vseats_added\x18\x02 \x01(\rR\n" +
+	"seatsAdded\x12#\n" +
+	"\rseats_removed\x18\x03 \x01(\rR\fseatsRemoved2\xf2\n" +
+	"\n" +
+	"\vTicketAdmin\x12M\n" +
+	"\tListHolds\x12 .ticket_reservation.EmptyRequest\x1a\x1c.ticket_reservation.HoldList\"\x00\x12`\n" +
+	"\vExpireHolds\x12&.ticket_reservation.ExpireHoldsRequest\x1a'.ticket_reservation.ExpireHoldsResponse\"\x00\x12\\\n" +
+	"\fReindexSeats\x12 .ticket_reservation.EmptyRequest\x1a(.ticket_reservation.ReindexSeatsResponse\"\x00\x12Z\n" +
+	"\x0eSetAccountRole\x12).ticket_reservation.SetAccountRoleRequest\x1a\x1b.ticket_reservation.Account\"\x00\x12K\n" +
+	"\rCreateWebhook\x12\x1b.ticket_reservation.Webhook\x1a\x1b.ticket_reservation.Webhook\"\x00\x12S\n" +
+	"\fListWebhooks\x12 .ticket_reservation.EmptyRequest\x1a\x1f.ticket_reservation.WebhookList\"\x00\x12P\n" +
+	"\rDeleteWebhook\x12 .ticket_reservation.WebhookQuery\x1a\x1b.ticket_reservation.Webhook\"\x00\x12l\n" +
+	"\x15ListWebhookDeliveries\x12(.ticket_reservation.WebhookDeliveryQuery\x1a'.ticket_reservation.WebhookDeliveryList\"\x00\x12g\n" +
+	"\x14RetryWebhookDelivery\x12(.ticket_reservation.WebhookDeliveryQuery\x1a#.ticket_reservation.WebhookDelivery\"\x00\x12u\n" +
+	"\x12RebuildProjections\x12-.ticket_reservation.RebuildProjectionsRequest\x1a..ticket_reservation.RebuildProjectionsResponse\"\x00\x12a\n" +
+	"\x10GetSeatOccupancy\x12(.ticket_reservation.SeatOccupancyRequest\x1a!.ticket_reservation.SeatOccupancy\"\x00\x12O\n" +
+	"\tPutLayout\x12$.ticket_reservation.PutLayoutRequest\x1a\x1a.ticket_reservation.Layout\"\x00\x12Q\n" +
+	"\vListLayouts\x12 .ticket_reservation.EmptyRequest\x1a\x1e.ticket_reservation.LayoutList\"\x00\x12J\n" +
+	"\tGetLayout\x12\x1f.ticket_reservation.LayoutQuery\x1a\x1a.ticket_reservation.Layout\"\x00\x12c\n" +
+	"\fAttachLayout\x12'.ticket_reservation.AttachLayoutRequest\x1a(.ticket_reservation.AttachLayoutResponse\"\x00B5Z3github.com/Akash-private/Cloudbees_code/proto;protob\x06proto3"
+
+var (
+	file_proto_ticket_admin_proto_rawDescOnce sync.Once
+	file_proto_ticket_admin_proto_rawDescData []byte
+)
+
+func file_proto_ticket_admin_proto_rawDescGZIP() []byte {
+	file_proto_ticket_admin_proto_rawDescOnce.Do(func() {
+		file_proto_ticket_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_ticket_admin_proto_rawDesc), len(file_proto_ticket_admin_proto_rawDesc)))
+	})
+	return file_proto_ticket_admin_proto_rawDescData
+}
+
+var file_proto_ticket_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_ticket_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_proto_ticket_admin_proto_goTypes = []any{
+	(WebhookDelivery_Status)(0),        // 0: ticket_reservation.WebhookDelivery.Status
+	(Coach_Numbering)(0),               // 1: ticket_reservation.Coach.Numbering
+	(*HoldList)(nil),                   // 2: ticket_reservation.HoldList
+	(*ExpireHoldsRequest)(nil),         // 3: ticket_reservation.ExpireHoldsRequest
+	(*ExpireHoldsResponse)(nil),        // 4: ticket_reservation.ExpireHoldsResponse
+	(*ReindexSeatsResponse)(nil),       // 5: ticket_reservation.ReindexSeatsResponse
+	(*SetAccountRoleRequest)(nil),      // 6: ticket_reservation.SetAccountRoleRequest
+	(*Webhook)(nil),                    // 7: ticket_reservation.Webhook
+	(*WebhookList)(nil),                // 8: ticket_reservation.WebhookList
+	(*WebhookQuery)(nil),               // 9: ticket_reservation.WebhookQuery
+	(*WebhookDelivery)(nil),            // 10: ticket_reservation.WebhookDelivery
+	(*WebhookDeliveryQuery)(nil),       // 11: ticket_reservation.WebhookDeliveryQuery
+	(*WebhookDeliveryList)(nil),        // 12: ticket_reservation.WebhookDeliveryList
+	(*RebuildProjectionsRequest)(nil),  // 13: ticket_reservation.RebuildProjectionsRequest
+	(*RebuildProjectionsResponse)(nil), // 14: ticket_reservation.RebuildProjectionsResponse
+	(*SeatOccupancyRequest)(nil),       // 15: ticket_reservation.SeatOccupancyRequest
+	(*SeatOccupancy)(nil),              // 16: ticket_reservation.SeatOccupancy
+	(*Layout)(nil),                     // 17: ticket_reservation.Layout
+	(*Coach)(nil),                      // 18: ticket_reservation.Coach
+	(*PutLayoutRequest)(nil),           // 19: ticket_reservation.PutLayoutRequest
+	(*LayoutQuery)(nil),                // 20: ticket_reservation.LayoutQuery
+	(*LayoutList)(nil),                 // 21: ticket_reservation.LayoutList
+	(*AttachLayoutRequest)(nil),        // 22: ticket_reservation.AttachLayoutRequest
+	(*AttachLayoutResponse)(nil),       // 23: ticket_reservation.AttachLayoutResponse
+	(*SeatOccupancy_Seat)(nil),         // 24: ticket_reservation.SeatOccupancy.Seat
+	(*Hold)(nil),                       // 25: ticket_reservation.Hold
+	(*timestamppb.Timestamp)(nil),      // 26: google.protobuf.Timestamp
+	(*EmptyRequest)(nil),               // 27: ticket_reservation.EmptyRequest
+	(*Account)(nil),                    // 28: ticket_reservation.Account
+}
+var file_proto_ticket_admin_proto_depIdxs = []int32{
+	25, // 0: ticket_reservation.HoldList.holds:type_name -> ticket_reservation.Hold
+	26, // 1: ticket_reservation.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 2: ticket_reservation.WebhookList.webhooks:type_name -> ticket_reservation.Webhook
+	0,  // 3: ticket_reservation.WebhookDelivery.status:type_name -> ticket_reservation.WebhookDelivery.Status
+	26, // 4: ticket_reservation.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	26, // 5: ticket_reservation.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	26, // 6: ticket_reservation.WebhookDelivery.delivered_at:type_name -> google.protobuf.Timestamp
+	0,  // 7: ticket_reservation.WebhookDeliveryQuery.status:type_name -> ticket_reservation.WebhookDelivery.Status
+	10, // 8: ticket_reservation.WebhookDeliveryList.deliveries:type_name -> ticket_reservation.WebhookDelivery
+	5,  // 9: ticket_reservation.RebuildProjectionsResponse.seats:type_name -> ticket_reservation.ReindexSeatsResponse
+	26, // 10: ticket_reservation.SeatOccupancyRequest.at:type_name -> google.protobuf.Timestamp
+	26, // 11: ticket_reservation.SeatOccupancy.at:type_name -> google.protobuf.Timestamp
+	24, // 12: ticket_reservation.SeatOccupancy.seats:type_name -> ticket_reservation.SeatOccupancy.Seat
+	18, // 13: ticket_reservation.Layout.coaches:type_name -> ticket_reservation.Coach
+	26, // 14: ticket_reservation.Layout.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 15: ticket_reservation.Coach.numbering:type_name -> ticket_reservation.Coach.Numbering
+	17, // 16: ticket_reservation.LayoutList.layouts:type_name -> ticket_reservation.Layout
+	26, // 17: ticket_reservation.SeatOccupancy.Seat.recorded_at:type_name -> google.protobuf.Timestamp
+	27, // 18: ticket_reservation.TicketAdmin.ListHolds:input_type -> ticket_reservation.EmptyRequest
+	3,  // 19: ticket_reservation.TicketAdmin.ExpireHolds:input_type -> ticket_reservation.ExpireHoldsRequest
+	27, // 20: ticket_reservation.TicketAdmin.ReindexSeats:input_type -> ticket_reservation.EmptyRequest
+	6,  // 21: ticket_reservation.TicketAdmin.SetAccountRole:input_type -> ticket_reservation.SetAccountRoleRequest
+	7,  // 22: ticket_reservation.TicketAdmin.CreateWebhook:input_type -> ticket_reservation.Webhook
+	27, // 23: ticket_reservation.TicketAdmin.ListWebhooks:input_type -> ticket_reservation.EmptyRequest
+	9,  // 24: ticket_reservation.TicketAdmin.DeleteWebhook:input_type -> ticket_reservation.WebhookQuery
+	11, // 25: ticket_reservation.TicketAdmin.ListWebhookDeliveries:input_type -> ticket_reservation.WebhookDeliveryQuery
+	11, // 26: ticket_reservation.TicketAdmin.RetryWebhookDelivery:input_type -> ticket_reservation.WebhookDeliveryQuery
+	13, // 27: ticket_reservation.TicketAdmin.RebuildProjections:input_type -> ticket_reservation.RebuildProjectionsRequest
+	15, // 28: ticket_reservation.TicketAdmin.GetSeatOccupancy:input_type -> ticket_reservation.SeatOccupancyRequest
+	19, // 29: ticket_reservation.TicketAdmin.PutLayout:input_type -> ticket_reservation.PutLayoutRequest
+	27, // 30: ticket_reservation.TicketAdmin.ListLayouts:input_type -> ticket_reservation.EmptyRequest
+	20, // 31: ticket_reservation.TicketAdmin.GetLayout:input_type -> ticket_reservation.LayoutQuery
+	22, // 32: ticket_reservation.TicketAdmin.AttachLayout:input_type -> ticket_reservation.AttachLayoutRequest
+	2,  // 33: ticket_reservation.TicketAdmin.ListHolds:output_type -> ticket_reservation.HoldList
+	4,  // 34: ticket_reservation.TicketAdmin.ExpireHolds:output_type -> ticket_reservation.ExpireHoldsResponse
+	5,  // 35: ticket_reservation.TicketAdmin.ReindexSeats:output_type -> ticket_reservation.ReindexSeatsResponse
+	28, // 36: ticket_reservation.TicketAdmin.SetAccountRole:output_type -> ticket_reservation.Account
+	7,  // 37: ticket_reservation.TicketAdmin.CreateWebhook:output_type -> ticket_reservation.Webhook
+	8,  // 38: ticket_reservation.TicketAdmin.ListWebhooks:output_type -> ticket_reservation.WebhookList
+	7,  // 39: ticket_reservation.TicketAdmin.DeleteWebhook:output_type -> ticket_reservation.Webhook
+	12, // 40: ticket_reservation.TicketAdmin.ListWebhookDeliveries:output_type -> ticket_reservation.WebhookDeliveryList
+	10, // 41: ticket_reservation.TicketAdmin.RetryWebhookDelivery:output_type -> ticket_reservation.WebhookDelivery
+	14, // 42: ticket_reservation.TicketAdmin.RebuildProjections:output_type -> ticket_reservation.RebuildProjectionsResponse
+	16, // 43: ticket_reservation.TicketAdmin.GetSeatOccupancy:output_type -> ticket_reservation.SeatOccupancy
+	17, // 44: ticket_reservation.TicketAdmin.PutLayout:output_type -> ticket_reservation.Layout
+	21, // 45: ticket_reservation.TicketAdmin.ListLayouts:output_type -> ticket_reservation.LayoutList
+	17, // 46: ticket_reservation.TicketAdmin.GetLayout:output_type -> ticket_reservation.Layout
+	23, // 47: ticket_reservation.TicketAdmin.AttachLayout:output_type -> ticket_reservation.AttachLayoutResponse
+	33, // [33:48] is the sub-list for method output_type
+	18, // [18:33] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_proto_ticket_admin_proto_init() }
+func file_proto_ticket_admin_proto_init() {
+	if File_proto_ticket_admin_proto != nil {
+		return
+	}
+	file_proto_ticket_reservation_proto_init()
+	file_proto_ticket_admin_proto_msgTypes[9].OneofWrappers = []any{}
+	file_proto_ticket_admin_proto_msgTypes[16].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_ticket_admin_proto_rawDesc), len(file_proto_ticket_admin_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_ticket_admin_proto_goTypes,
+		DependencyIndexes: file_proto_ticket_admin_proto_depIdxs,
+		EnumInfos:         file_proto_ticket_admin_proto_enumTypes,
+		MessageInfos:      file_proto_ticket_admin_proto_msgTypes,
+	}.Build()
+	File_proto_ticket_admin_proto = out.File
+	file_proto_ticket_admin_proto_goTypes = nil
+	file_proto_ticket_admin_proto_depIdxs = nil
+}
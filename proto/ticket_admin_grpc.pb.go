@@ -0,0 +1,707 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.26.1
+// source: proto/ticket_admin.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TicketAdmin_ListHolds_FullMethodName             = "/ticket_reservation.TicketAdmin/ListHolds"
+	TicketAdmin_ExpireHolds_FullMethodName           = "/ticket_reservation.TicketAdmin/ExpireHolds"
+	TicketAdmin_ReindexSeats_FullMethodName          = "/ticket_reservation.TicketAdmin/ReindexSeats"
+	TicketAdmin_SetAccountRole_FullMethodName        = "/ticket_reservation.TicketAdmin/SetAccountRole"
+	TicketAdmin_CreateWebhook_FullMethodName         = "/ticket_reservation.TicketAdmin/CreateWebhook"
+	TicketAdmin_ListWebhooks_FullMethodName          = "/ticket_reservation.TicketAdmin/ListWebhooks"
+	TicketAdmin_DeleteWebhook_FullMethodName         = "/ticket_reservation.TicketAdmin/DeleteWebhook"
+	TicketAdmin_ListWebhookDeliveries_FullMethodName = "/ticket_reservation.TicketAdmin/ListWebhookDeliveries"
+	TicketAdmin_RetryWebhookDelivery_FullMethodName  = "/ticket_reservation.TicketAdmin/RetryWebhookDelivery"
+	TicketAdmin_RebuildProjections_FullMethodName    = "/ticket_reservation.TicketAdmin/RebuildProjections"
+	TicketAdmin_GetSeatOccupancy_FullMethodName      = "/ticket_reservation.TicketAdmin/GetSeatOccupancy"
+	TicketAdmin_PutLayout_FullMethodName             = "/ticket_reservation.TicketAdmin/PutLayout"
+	TicketAdmin_ListLayouts_FullMethodName           = "/ticket_reservation.TicketAdmin/ListLayouts"
+	TicketAdmin_GetLayout_FullMethodName             = "/ticket_reservation.TicketAdmin/GetLayout"
+	TicketAdmin_AttachLayout_FullMethodName          = "/ticket_reservation.TicketAdmin/AttachLayout"
+)
+
+// TicketAdminClient is the client API for TicketAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Operational RPCs for staff. Every call must carry an
+// "authorization: Bearer <ADMIN_TOKEN>" metadata entry.
+type TicketAdminClient interface {
+	// Lists holds that have not yet expired or been booked.
+	ListHolds(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*HoldList, error)
+	// Releases the given holds, or every active hold if all is set.
+	ExpireHolds(ctx context.Context, in *ExpireHoldsRequest, opts ...grpc.CallOption) (*ExpireHoldsResponse, error)
+	// Rebuilds the seat availability index from the tickets table.
+	ReindexSeats(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ReindexSeatsResponse, error)
+	// Changes the role of the account with the given email.
+	SetAccountRole(ctx context.Context, in *SetAccountRoleRequest, opts ...grpc.CallOption) (*Account, error)
+	// Subscribes a partner URL to domain events from now on. Every delivery
+	// is signed with the subscription's secret, which is only returned here.
+	CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error)
+	// Lists the webhook subscriptions, without their secrets.
+	ListWebhooks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*WebhookList, error)
+	// Removes a subscription together with its delivery log.
+	DeleteWebhook(ctx context.Context, in *WebhookQuery, opts ...grpc.CallOption) (*Webhook, error)
+	// Lists webhook deliveries, newest first. Failed deliveries are retried
+	// with exponential backoff and end up DEAD after WEBHOOK_MAX_ATTEMPTS.
+	ListWebhookDeliveries(ctx context.Context, in *WebhookDeliveryQuery, opts ...grpc.CallOption) (*WebhookDeliveryList, error)
+	// Queues a delivery to be sent again straight away, e.g. a DEAD one after
+	// the partner fixed their endpoint.
+	RetryWebhookDelivery(ctx context.Context, in *WebhookDeliveryQuery, opts ...grpc.CallOption) (*WebhookDelivery, error)
+	// Replays the ticket ledger onto the tickets and ticket_passengers tables
+	// and rebuilds the seat index from them. With dry_run it only reports
+	// what would change.
+	RebuildProjections(ctx context.Context, in *RebuildProjectionsRequest, opts ...grpc.CallOption) (*RebuildProjectionsResponse, error)
+	// Who held which seats of a departure at a point in time, from the
+	// ticket ledger.
+	GetSeatOccupancy(ctx context.Context, in *SeatOccupancyRequest, opts ...grpc.CallOption) (*SeatOccupancy, error)
+	// Stores a coach layout given as YAML or JSON. A changed layout under a
+	// known name becomes its next version; an unchanged one returns the
+	// version already stored.
+	PutLayout(ctx context.Context, in *PutLayoutRequest, opts ...grpc.CallOption) (*Layout, error)
+	// Lists the latest version of every layout.
+	ListLayouts(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*LayoutList, error)
+	GetLayout(ctx context.Context, in *LayoutQuery, opts ...grpc.CallOption) (*Layout, error)
+	// Attaches a layout version to a departure, or to a train's upcoming
+	// departures and those scheduled later, and rebuilds their seats. It
+	// fails if a booked or held seat would disappear or be blocked.
+	AttachLayout(ctx context.Context, in *AttachLayoutRequest, opts ...grpc.CallOption) (*AttachLayoutResponse, error)
+}
+
+type ticketAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTicketAdminClient(cc grpc.ClientConnInterface) TicketAdminClient {
+	return &ticketAdminClient{cc}
+}
+
+func (c *ticketAdminClient) ListHolds(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*HoldList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HoldList)
+	err := c.cc.Invoke(ctx, TicketAdmin_ListHolds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) ExpireHolds(ctx context.Context, in *ExpireHoldsRequest, opts ...grpc.CallOption) (*ExpireHoldsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExpireHoldsResponse)
+	err := c.cc.Invoke(ctx, TicketAdmin_ExpireHolds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) ReindexSeats(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ReindexSeatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReindexSeatsResponse)
+	err := c.cc.Invoke(ctx, TicketAdmin_ReindexSeats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) SetAccountRole(ctx context.Context, in *SetAccountRoleRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TicketAdmin_SetAccountRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, TicketAdmin_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) ListWebhooks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*WebhookList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookList)
+	err := c.cc.Invoke(ctx, TicketAdmin_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) DeleteWebhook(ctx context.Context, in *WebhookQuery, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, TicketAdmin_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) ListWebhookDeliveries(ctx context.Context, in *WebhookDeliveryQuery, opts ...grpc.CallOption) (*WebhookDeliveryList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookDeliveryList)
+	err := c.cc.Invoke(ctx, TicketAdmin_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) RetryWebhookDelivery(ctx context.Context, in *WebhookDeliveryQuery, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, TicketAdmin_RetryWebhookDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) RebuildProjections(ctx context.Context, in *RebuildProjectionsRequest, opts ...grpc.CallOption) (*RebuildProjectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RebuildProjectionsResponse)
+	err := c.cc.Invoke(ctx, TicketAdmin_RebuildProjections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) GetSeatOccupancy(ctx context.Context, in *SeatOccupancyRequest, opts ...grpc.CallOption) (*SeatOccupancy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeatOccupancy)
+	err := c.cc.Invoke(ctx, TicketAdmin_GetSeatOccupancy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) PutLayout(ctx context.Context, in *PutLayoutRequest, opts ...grpc.CallOption) (*Layout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Layout)
+	err := c.cc.Invoke(ctx, TicketAdmin_PutLayout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) ListLayouts(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*LayoutList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LayoutList)
+	err := c.cc.Invoke(ctx, TicketAdmin_ListLayouts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) GetLayout(ctx context.Context, in *LayoutQuery, opts ...grpc.CallOption) (*Layout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Layout)
+	err := c.cc.Invoke(ctx, TicketAdmin_GetLayout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketAdminClient) AttachLayout(ctx context.Context, in *AttachLayoutRequest, opts ...grpc.CallOption) (*AttachLayoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AttachLayoutResponse)
+	err := c.cc.Invoke(ctx, TicketAdmin_AttachLayout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TicketAdminServer is the server API for TicketAdmin service.
+// All implementations must embed UnimplementedTicketAdminServer
+// for forward compatibility.
+//
+// Operational RPCs for staff. Every call must carry an
+// "authorization: Bearer <ADMIN_TOKEN>" metadata entry.
+type TicketAdminServer interface {
+	// Lists holds that have not yet expired or been booked.
+	ListHolds(context.Context, *EmptyRequest) (*HoldList, error)
+	// Releases the given holds, or every active hold if all is set.
+	ExpireHolds(context.Context, *ExpireHoldsRequest) (*ExpireHoldsResponse, error)
+	// Rebuilds the seat availability index from the tickets table.
+	ReindexSeats(context.Context, *EmptyRequest) (*ReindexSeatsResponse, error)
+	// Changes the role of the account with the given email.
+	SetAccountRole(context.Context, *SetAccountRoleRequest) (*Account, error)
+	// Subscribes a partner URL to domain events from now on. Every delivery
+	// is signed with the subscription's secret, which is only returned here.
+	CreateWebhook(context.Context, *Webhook) (*Webhook, error)
+	// Lists the webhook subscriptions, without their secrets.
+	ListWebhooks(context.Context, *EmptyRequest) (*WebhookList, error)
+	// Removes a subscription together with its delivery log.
+	DeleteWebhook(context.Context, *WebhookQuery) (*Webhook, error)
+	// Lists webhook deliveries, newest first. Failed deliveries are retried
+	// with exponential backoff and end up DEAD after WEBHOOK_MAX_ATTEMPTS.
+	ListWebhookDeliveries(context.Context, *WebhookDeliveryQuery) (*WebhookDeliveryList, error)
+	// Queues a delivery to be sent again straight away, e.g. a DEAD one after
+	// the partner fixed their endpoint.
+	RetryWebhookDelivery(context.Context, *WebhookDeliveryQuery) (*WebhookDelivery, error)
+	// Replays the ticket ledger onto the tickets and ticket_passengers tables
+	// and rebuilds the seat index from them. With dry_run it only reports
+	// what would change.
+	RebuildProjections(context.Context, *RebuildProjectionsRequest) (*RebuildProjectionsResponse, error)
+	// Who held which seats of a departure at a point in time, from the
+	// ticket ledger.
+	GetSeatOccupancy(context.Context, *SeatOccupancyRequest) (*SeatOccupancy, error)
+	// Stores a coach layout given as YAML or JSON. A changed layout under a
+	// known name becomes its next version; an unchanged one returns the
+	// version already stored.
+	PutLayout(context.Context, *PutLayoutRequest) (*Layout, error)
+	// Lists the latest version of every layout.
+	ListLayouts(context.Context, *EmptyRequest) (*LayoutList, error)
+	GetLayout(context.Context, *LayoutQuery) (*Layout, error)
+	// Attaches a layout version to a departure, or to a train's upcoming
+	// departures and those scheduled later, and rebuilds their seats. It
+	// fails if a booked or held seat would disappear or be blocked.
+	AttachLayout(context.Context, *AttachLayoutRequest) (*AttachLayoutResponse, error)
+	mustEmbedUnimplementedTicketAdminServer()
+}
+
+// UnimplementedTicketAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTicketAdminServer struct{}
+
+func (UnimplementedTicketAdminServer) ListHolds(context.Context, *EmptyRequest) (*HoldList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHolds not implemented")
+}
+func (UnimplementedTicketAdminServer) ExpireHolds(context.Context, *ExpireHoldsRequest) (*ExpireHoldsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExpireHolds not implemented")
+}
+func (UnimplementedTicketAdminServer) ReindexSeats(context.Context, *EmptyRequest) (*ReindexSeatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReindexSeats not implemented")
+}
+func (UnimplementedTicketAdminServer) SetAccountRole(context.Context, *SetAccountRoleRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAccountRole not implemented")
+}
+func (UnimplementedTicketAdminServer) CreateWebhook(context.Context, *Webhook) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedTicketAdminServer) ListWebhooks(context.Context, *EmptyRequest) (*WebhookList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedTicketAdminServer) DeleteWebhook(context.Context, *WebhookQuery) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedTicketAdminServer) ListWebhookDeliveries(context.Context, *WebhookDeliveryQuery) (*WebhookDeliveryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedTicketAdminServer) RetryWebhookDelivery(context.Context, *WebhookDeliveryQuery) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryWebhookDelivery not implemented")
+}
+func (UnimplementedTicketAdminServer) RebuildProjections(context.Context, *RebuildProjectionsRequest) (*RebuildProjectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebuildProjections not implemented")
+}
+func (UnimplementedTicketAdminServer) GetSeatOccupancy(context.Context, *SeatOccupancyRequest) (*SeatOccupancy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeatOccupancy not implemented")
+}
+func (UnimplementedTicketAdminServer) PutLayout(context.Context, *PutLayoutRequest) (*Layout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutLayout not implemented")
+}
+func (UnimplementedTicketAdminServer) ListLayouts(context.Context, *EmptyRequest) (*LayoutList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLayouts not implemented")
+}
+func (UnimplementedTicketAdminServer) GetLayout(context.Context, *LayoutQuery) (*Layout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLayout not implemented")
+}
+func (UnimplementedTicketAdminServer) AttachLayout(context.Context, *AttachLayoutRequest) (*AttachLayoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachLayout not implemented")
+}
+func (UnimplementedTicketAdminServer) mustEmbedUnimplementedTicketAdminServer() {}
+func (UnimplementedTicketAdminServer) testEmbeddedByValue()                     {}
+
+// UnsafeTicketAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TicketAdminServer will
+// result in compilation errors.
+type UnsafeTicketAdminServer interface {
+	mustEmbedUnimplementedTicketAdminServer()
+}
+
+func RegisterTicketAdminServer(s grpc.ServiceRegistrar, srv TicketAdminServer) {
+	// If the following call pancis, it indicates UnimplementedTicketAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TicketAdmin_ServiceDesc, srv)
+}
+
+func _TicketAdmin_ListHolds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).ListHolds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_ListHolds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).ListHolds(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_ExpireHolds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireHoldsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).ExpireHolds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_ExpireHolds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).ExpireHolds(ctx, req.(*ExpireHoldsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_ReindexSeats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).ReindexSeats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_ReindexSeats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).ReindexSeats(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_SetAccountRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAccountRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).SetAccountRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_SetAccountRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).SetAccountRole(ctx, req.(*SetAccountRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).CreateWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).ListWebhooks(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).DeleteWebhook(ctx, req.(*WebhookQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookDeliveryQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).ListWebhookDeliveries(ctx, req.(*WebhookDeliveryQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_RetryWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookDeliveryQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).RetryWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_RetryWebhookDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).RetryWebhookDelivery(ctx, req.(*WebhookDeliveryQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_RebuildProjections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebuildProjectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).RebuildProjections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_RebuildProjections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).RebuildProjections(ctx, req.(*RebuildProjectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_GetSeatOccupancy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeatOccupancyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).GetSeatOccupancy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_GetSeatOccupancy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).GetSeatOccupancy(ctx, req.(*SeatOccupancyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_PutLayout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutLayoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).PutLayout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_PutLayout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).PutLayout(ctx, req.(*PutLayoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_ListLayouts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).ListLayouts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_ListLayouts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).ListLayouts(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_GetLayout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LayoutQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).GetLayout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_GetLayout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).GetLayout(ctx, req.(*LayoutQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketAdmin_AttachLayout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachLayoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketAdminServer).AttachLayout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketAdmin_AttachLayout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketAdminServer).AttachLayout(ctx, req.(*AttachLayoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TicketAdmin_ServiceDesc is the grpc.ServiceDesc for TicketAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TicketAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ticket_reservation.TicketAdmin",
+	HandlerType: (*TicketAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListHolds",
+			Handler:    _TicketAdmin_ListHolds_Handler,
+		},
+		{
+			MethodName: "ExpireHolds",
+			Handler:    _TicketAdmin_ExpireHolds_Handler,
+		},
+		{
+			MethodName: "ReindexSeats",
+			Handler:    _TicketAdmin_ReindexSeats_Handler,
+		},
+		{
+			MethodName: "SetAccountRole",
+			Handler:    _TicketAdmin_SetAccountRole_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _TicketAdmin_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _TicketAdmin_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _TicketAdmin_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _TicketAdmin_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RetryWebhookDelivery",
+			Handler:    _TicketAdmin_RetryWebhookDelivery_Handler,
+		},
+		{
+			MethodName: "RebuildProjections",
+			Handler:    _TicketAdmin_RebuildProjections_Handler,
+		},
+		{
+			MethodName: "GetSeatOccupancy",
+			Handler:    _TicketAdmin_GetSeatOccupancy_Handler,
+		},
+		{
+			MethodName: "PutLayout",
+			Handler:    _TicketAdmin_PutLayout_Handler,
+		},
+		{
+			MethodName: "ListLayouts",
+			Handler:    _TicketAdmin_ListLayouts_Handler,
+		},
+		{
+			MethodName: "GetLayout",
+			Handler:    _TicketAdmin_GetLayout_Handler,
+		},
+		{
+			MethodName: "AttachLayout",
+			Handler:    _TicketAdmin_AttachLayout_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/ticket_admin.proto",
+}
@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,33 +22,3567 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SeatPreferences_Position int32
+
+const (
+	SeatPreferences_ANY_POSITION SeatPreferences_Position = 0
+	SeatPreferences_WINDOW       SeatPreferences_Position = 1
+	SeatPreferences_AISLE        SeatPreferences_Position = 2
+)
+
+// Enum value maps for SeatPreferences_Position.
+var (
+	SeatPreferences_Position_name = map[int32]string{
+		0: "ANY_POSITION",
+		1: "WINDOW",
+		2: "AISLE",
+	}
+	SeatPreferences_Position_value = map[string]int32{
+		"ANY_POSITION": 0,
+		"WINDOW":       1,
+		"AISLE":        2,
+	}
+)
+
+func (x SeatPreferences_Position) Enum() *SeatPreferences_Position {
+	p := new(SeatPreferences_Position)
+	*p = x
+	return p
+}
+
+func (x SeatPreferences_Position) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SeatPreferences_Position) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[0].Descriptor()
+}
+
+func (SeatPreferences_Position) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[0]
+}
+
+func (x SeatPreferences_Position) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SeatPreferences_Position.Descriptor instead.
+func (SeatPreferences_Position) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type SeatPreferences_Facing int32
+
+const (
+	SeatPreferences_ANY_DIRECTION SeatPreferences_Facing = 0
+	// Facing the direction of travel.
+	SeatPreferences_FORWARD  SeatPreferences_Facing = 1
+	SeatPreferences_BACKWARD SeatPreferences_Facing = 2
+)
+
+// Enum value maps for SeatPreferences_Facing.
+var (
+	SeatPreferences_Facing_name = map[int32]string{
+		0: "ANY_DIRECTION",
+		1: "FORWARD",
+		2: "BACKWARD",
+	}
+	SeatPreferences_Facing_value = map[string]int32{
+		"ANY_DIRECTION": 0,
+		"FORWARD":       1,
+		"BACKWARD":      2,
+	}
+)
+
+func (x SeatPreferences_Facing) Enum() *SeatPreferences_Facing {
+	p := new(SeatPreferences_Facing)
+	*p = x
+	return p
+}
+
+func (x SeatPreferences_Facing) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SeatPreferences_Facing) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[1].Descriptor()
+}
+
+func (SeatPreferences_Facing) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[1]
+}
+
+func (x SeatPreferences_Facing) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SeatPreferences_Facing.Descriptor instead.
+func (SeatPreferences_Facing) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{1, 1}
+}
+
+type SeatMap_State int32
+
+const (
+	SeatMap_FREE   SeatMap_State = 0
+	SeatMap_HELD   SeatMap_State = 1
+	SeatMap_BOOKED SeatMap_State = 2
+	// Never sold, see the departure's layout.
+	SeatMap_BLOCKED SeatMap_State = 3
+)
+
+// Enum value maps for SeatMap_State.
+var (
+	SeatMap_State_name = map[int32]string{
+		0: "FREE",
+		1: "HELD",
+		2: "BOOKED",
+		3: "BLOCKED",
+	}
+	SeatMap_State_value = map[string]int32{
+		"FREE":    0,
+		"HELD":    1,
+		"BOOKED":  2,
+		"BLOCKED": 3,
+	}
+)
+
+func (x SeatMap_State) Enum() *SeatMap_State {
+	p := new(SeatMap_State)
+	*p = x
+	return p
+}
+
+func (x SeatMap_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SeatMap_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[2].Descriptor()
+}
+
+func (SeatMap_State) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[2]
+}
+
+func (x SeatMap_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SeatMap_State.Descriptor instead.
+func (SeatMap_State) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{10, 0}
+}
+
+type JourneyRequest_Sort int32
+
+const (
+	// By arrival, then changes, then the latest departure.
+	JourneyRequest_EARLIEST_ARRIVAL JourneyRequest_Sort = 0
+	// By changes, then arrival.
+	JourneyRequest_FEWEST_CHANGES JourneyRequest_Sort = 1
+)
+
+// Enum value maps for JourneyRequest_Sort.
+var (
+	JourneyRequest_Sort_name = map[int32]string{
+		0: "EARLIEST_ARRIVAL",
+		1: "FEWEST_CHANGES",
+	}
+	JourneyRequest_Sort_value = map[string]int32{
+		"EARLIEST_ARRIVAL": 0,
+		"FEWEST_CHANGES":   1,
+	}
+)
+
+func (x JourneyRequest_Sort) Enum() *JourneyRequest_Sort {
+	p := new(JourneyRequest_Sort)
+	*p = x
+	return p
+}
+
+func (x JourneyRequest_Sort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JourneyRequest_Sort) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[3].Descriptor()
+}
+
+func (JourneyRequest_Sort) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[3]
+}
+
+func (x JourneyRequest_Sort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JourneyRequest_Sort.Descriptor instead.
+func (JourneyRequest_Sort) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{15, 0}
+}
+
+type TicketValidation_Result int32
+
+const (
+	TicketValidation_VALID         TicketValidation_Result = 0
+	TicketValidation_MALFORMED     TicketValidation_Result = 1
+	TicketValidation_BAD_SIGNATURE TicketValidation_Result = 2
+	TicketValidation_NOT_YET_VALID TicketValidation_Result = 3
+	TicketValidation_EXPIRED       TicketValidation_Result = 4
+	// The ticket was cancelled after the token was issued.
+	TicketValidation_CANCELLED TicketValidation_Result = 5
+	// The passenger has moved to another seat since the token was issued.
+	TicketValidation_SEAT_CHANGED TicketValidation_Result = 6
+	TicketValidation_ALREADY_USED TicketValidation_Result = 7
+)
+
+// Enum value maps for TicketValidation_Result.
+var (
+	TicketValidation_Result_name = map[int32]string{
+		0: "VALID",
+		1: "MALFORMED",
+		2: "BAD_SIGNATURE",
+		3: "NOT_YET_VALID",
+		4: "EXPIRED",
+		5: "CANCELLED",
+		6: "SEAT_CHANGED",
+		7: "ALREADY_USED",
+	}
+	TicketValidation_Result_value = map[string]int32{
+		"VALID":         0,
+		"MALFORMED":     1,
+		"BAD_SIGNATURE": 2,
+		"NOT_YET_VALID": 3,
+		"EXPIRED":       4,
+		"CANCELLED":     5,
+		"SEAT_CHANGED":  6,
+		"ALREADY_USED":  7,
+	}
+)
+
+func (x TicketValidation_Result) Enum() *TicketValidation_Result {
+	p := new(TicketValidation_Result)
+	*p = x
+	return p
+}
+
+func (x TicketValidation_Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TicketValidation_Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[4].Descriptor()
+}
+
+func (TicketValidation_Result) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[4]
+}
+
+func (x TicketValidation_Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TicketValidation_Result.Descriptor instead.
+func (TicketValidation_Result) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{30, 0}
+}
+
+type BoardingManifest_State int32
+
+const (
+	BoardingManifest_BOOKED     BoardingManifest_State = 0
+	BoardingManifest_CHECKED_IN BoardingManifest_State = 1
+	BoardingManifest_BOARDED    BoardingManifest_State = 2
+	// Not boarded when the train left.
+	BoardingManifest_NO_SHOW BoardingManifest_State = 3
+)
+
+// Enum value maps for BoardingManifest_State.
+var (
+	BoardingManifest_State_name = map[int32]string{
+		0: "BOOKED",
+		1: "CHECKED_IN",
+		2: "BOARDED",
+		3: "NO_SHOW",
+	}
+	BoardingManifest_State_value = map[string]int32{
+		"BOOKED":     0,
+		"CHECKED_IN": 1,
+		"BOARDED":    2,
+		"NO_SHOW":    3,
+	}
+)
+
+func (x BoardingManifest_State) Enum() *BoardingManifest_State {
+	p := new(BoardingManifest_State)
+	*p = x
+	return p
+}
+
+func (x BoardingManifest_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BoardingManifest_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[5].Descriptor()
+}
+
+func (BoardingManifest_State) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[5]
+}
+
+func (x BoardingManifest_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BoardingManifest_State.Descriptor instead.
+func (BoardingManifest_State) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{33, 0}
+}
+
+type WaitlistEntry_Status int32
+
+const (
+	WaitlistEntry_WAITING WaitlistEntry_Status = 0
+	// Seats are held until hold_expires_at; claim them with ClaimWaitlist.
+	WaitlistEntry_PROMOTED WaitlistEntry_Status = 1
+	WaitlistEntry_BOOKED   WaitlistEntry_Status = 2
+	// The promotion was not claimed in time, or the train left first.
+	WaitlistEntry_EXPIRED WaitlistEntry_Status = 3
+	WaitlistEntry_LEFT    WaitlistEntry_Status = 4
+)
+
+// Enum value maps for WaitlistEntry_Status.
+var (
+	WaitlistEntry_Status_name = map[int32]string{
+		0: "WAITING",
+		1: "PROMOTED",
+		2: "BOOKED",
+		3: "EXPIRED",
+		4: "LEFT",
+	}
+	WaitlistEntry_Status_value = map[string]int32{
+		"WAITING":  0,
+		"PROMOTED": 1,
+		"BOOKED":   2,
+		"EXPIRED":  3,
+		"LEFT":     4,
+	}
+)
+
+func (x WaitlistEntry_Status) Enum() *WaitlistEntry_Status {
+	p := new(WaitlistEntry_Status)
+	*p = x
+	return p
+}
+
+func (x WaitlistEntry_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WaitlistEntry_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ticket_reservation_proto_enumTypes[6].Descriptor()
+}
+
+func (WaitlistEntry_Status) Type() protoreflect.EnumType {
+	return &file_proto_ticket_reservation_proto_enumTypes[6]
+}
+
+func (x WaitlistEntry_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WaitlistEntry_Status.Descriptor instead.
+func (WaitlistEntry_Status) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{37, 0}
+}
+
 type UserDetails struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	FirstName string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName  string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email     string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Address   string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Seat      uint32                 `protobuf:"varint,5,opt,name=seat,proto3" json:"seat,omitempty"`
+	Section   string                 `protobuf:"bytes,6,opt,name=section,proto3" json:"section,omitempty"`
+	// What the passenger would like from an allocated seat. A section without
+	// a seat is a preference too.
+	Preferences *SeatPreferences `protobuf:"bytes,7,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	// Passengers of a booking with the same party, e.g. a family, are seated
+	// together even when the booking as a whole cannot be.
+	Party         string `protobuf:"bytes,8,opt,name=party,proto3" json:"party,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDetails) Reset() {
+	*x = UserDetails{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDetails) ProtoMessage() {}
+
+func (x *UserDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDetails.ProtoReflect.Descriptor instead.
+func (*UserDetails) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UserDetails) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *UserDetails) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *UserDetails) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserDetails) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *UserDetails) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *UserDetails) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *UserDetails) GetPreferences() *SeatPreferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+func (x *UserDetails) GetParty() string {
+	if x != nil {
+		return x.Party
+	}
+	return ""
+}
+
+type SeatPreferences struct {
+	state      protoimpl.MessageState   `protogen:"open.v1"`
+	Position   SeatPreferences_Position `protobuf:"varint,1,opt,name=position,proto3,enum=ticket_reservation.SeatPreferences_Position" json:"position,omitempty"`
+	Facing     SeatPreferences_Facing   `protobuf:"varint,2,opt,name=facing,proto3,enum=ticket_reservation.SeatPreferences_Facing" json:"facing,omitempty"`
+	QuietCoach bool                     `protobuf:"varint,3,opt,name=quiet_coach,json=quietCoach,proto3" json:"quiet_coach,omitempty"`
+	NearExit   bool                     `protobuf:"varint,4,opt,name=near_exit,json=nearExit,proto3" json:"near_exit,omitempty"`
+	// A priority seat near the doors with room for mobility aids.
+	Accessible    bool `protobuf:"varint,5,opt,name=accessible,proto3" json:"accessible,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatPreferences) Reset() {
+	*x = SeatPreferences{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatPreferences) ProtoMessage() {}
+
+func (x *SeatPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatPreferences.ProtoReflect.Descriptor instead.
+func (*SeatPreferences) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SeatPreferences) GetPosition() SeatPreferences_Position {
+	if x != nil {
+		return x.Position
+	}
+	return SeatPreferences_ANY_POSITION
+}
+
+func (x *SeatPreferences) GetFacing() SeatPreferences_Facing {
+	if x != nil {
+		return x.Facing
+	}
+	return SeatPreferences_ANY_DIRECTION
+}
+
+func (x *SeatPreferences) GetQuietCoach() bool {
+	if x != nil {
+		return x.QuietCoach
+	}
+	return false
+}
+
+func (x *SeatPreferences) GetNearExit() bool {
+	if x != nil {
+		return x.NearExit
+	}
+	return false
+}
+
+func (x *SeatPreferences) GetAccessible() bool {
+	if x != nil {
+		return x.Accessible
+	}
+	return false
+}
+
+// UnmetPreference is a seat preference the allocated seat does not meet.
+type UnmetPreference struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index of the passenger in passengers.
+	Passenger uint32 `protobuf:"varint,1,opt,name=passenger,proto3" json:"passenger,omitempty"`
+	// window, aisle, forward, backward, quiet_coach, near_exit, accessible,
+	// section or together.
+	Preference    string `protobuf:"bytes,2,opt,name=preference,proto3" json:"preference,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnmetPreference) Reset() {
+	*x = UnmetPreference{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmetPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmetPreference) ProtoMessage() {}
+
+func (x *UnmetPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmetPreference.ProtoReflect.Descriptor instead.
+func (*UnmetPreference) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UnmetPreference) GetPassenger() uint32 {
+	if x != nil {
+		return x.Passenger
+	}
+	return 0
+}
+
+func (x *UnmetPreference) GetPreference() string {
+	if x != nil {
+		return x.Preference
+	}
+	return ""
+}
+
+func (x *UnmetPreference) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReservationRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TicketNo *uint64                `protobuf:"varint,1,opt,name=ticket_no,json=ticketNo,proto3,oneof" json:"ticket_no,omitempty"`
+	// Stops to travel between, anywhere along the departure's run; the seats
+	// are only taken between them. Empty means the first or last stop.
+	FromCode string `protobuf:"bytes,2,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode   string `protobuf:"bytes,3,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	// Fare the customer agreed to (see QuoteFare). When set, ReserveTicket
+	// fails with FAILED_PRECONDITION if the fare has changed.
+	PricePaid      uint64 `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
+	PassengerCount uint64 `protobuf:"varint,5,opt,name=passenger_count,json=passengerCount,proto3" json:"passenger_count,omitempty"`
+	// The first passenger is the lead passenger. Each passenger gets a seat:
+	// the one given, or one allocated by their preferences, next to the
+	// others of the booking where possible.
+	Passengers []*UserDetails `protobuf:"bytes,6,rep,name=passengers,proto3" json:"passengers,omitempty"`
+	// Books the seat held by HoldSeat instead of allocating one.
+	HoldId string `protobuf:"bytes,7,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	// Departure to book. When zero, the next departure matching from_code and
+	// to_code is used.
+	DepartureId uint64 `protobuf:"varint,8,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// Fare type to book, e.g. "flexible" or "saver". It sets the price per
+	// passenger and the cancellation policy. Empty means "standard".
+	FareType string `protobuf:"bytes,9,opt,name=fare_type,json=fareType,proto3" json:"fare_type,omitempty"`
+	// Payment method from the payment provider's client library. The fare is
+	// authorized and captured before the ticket is confirmed.
+	PaymentToken  string `protobuf:"bytes,10,opt,name=payment_token,json=paymentToken,proto3" json:"payment_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationRequest) Reset() {
+	*x = ReservationRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationRequest) ProtoMessage() {}
+
+func (x *ReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReservationRequest) GetTicketNo() uint64 {
+	if x != nil && x.TicketNo != nil {
+		return *x.TicketNo
+	}
+	return 0
+}
+
+func (x *ReservationRequest) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *ReservationRequest) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+func (x *ReservationRequest) GetPricePaid() uint64 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+func (x *ReservationRequest) GetPassengerCount() uint64 {
+	if x != nil {
+		return x.PassengerCount
+	}
+	return 0
+}
+
+func (x *ReservationRequest) GetPassengers() []*UserDetails {
+	if x != nil {
+		return x.Passengers
+	}
+	return nil
+}
+
+func (x *ReservationRequest) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *ReservationRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *ReservationRequest) GetFareType() string {
+	if x != nil {
+		return x.FareType
+	}
+	return ""
+}
+
+func (x *ReservationRequest) GetPaymentToken() string {
+	if x != nil {
+		return x.PaymentToken
+	}
+	return ""
+}
+
+type ReservationResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TicketNo uint64                 `protobuf:"varint,1,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	// Where the ticket's passengers board and alight.
+	FromCode       string         `protobuf:"bytes,2,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode         string         `protobuf:"bytes,3,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	PricePaid      uint64         `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
+	PassengerCount uint64         `protobuf:"varint,5,opt,name=passenger_count,json=passengerCount,proto3" json:"passenger_count,omitempty"`
+	Passengers     []*UserDetails `protobuf:"bytes,6,rep,name=passengers,proto3" json:"passengers,omitempty"`
+	Status         string         `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	DepartureId    uint64         `protobuf:"varint,8,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// Account that booked the ticket, zero for anonymous bookings.
+	AccountId uint64 `protobuf:"varint,9,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Signed ticket tokens, one per passenger in passenger order. Only set
+	// when a single ticket is returned.
+	Tokens   []string `protobuf:"bytes,10,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	FareType string   `protobuf:"bytes,11,opt,name=fare_type,json=fareType,proto3" json:"fare_type,omitempty"`
+	// What was refunded. Only set by CancelTicket.
+	Refund *Refund `protobuf:"bytes,12,opt,name=refund,proto3" json:"refund,omitempty"`
+	// Payment of the fare; unset for tickets booked before payments were
+	// taken.
+	Payment *Payment `protobuf:"bytes,13,opt,name=payment,proto3" json:"payment,omitempty"`
+	// Seat preferences that could not be met. Only set by ReserveTicket,
+	// ClaimWaitlist and ReserveJourney.
+	UnmetPreferences []*UnmetPreference `protobuf:"bytes,14,rep,name=unmet_preferences,json=unmetPreferences,proto3" json:"unmet_preferences,omitempty"`
+	// The journey the ticket is a leg of, zero for a ticket booked on its own.
+	JourneyId     uint64 `protobuf:"varint,15,opt,name=journey_id,json=journeyId,proto3" json:"journey_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationResponse) Reset() {
+	*x = ReservationResponse{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationResponse) ProtoMessage() {}
+
+func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReservationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReservationResponse) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *ReservationResponse) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *ReservationResponse) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+func (x *ReservationResponse) GetPricePaid() uint64 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+func (x *ReservationResponse) GetPassengerCount() uint64 {
+	if x != nil {
+		return x.PassengerCount
+	}
+	return 0
+}
+
+func (x *ReservationResponse) GetPassengers() []*UserDetails {
+	if x != nil {
+		return x.Passengers
+	}
+	return nil
+}
+
+func (x *ReservationResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReservationResponse) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *ReservationResponse) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ReservationResponse) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *ReservationResponse) GetFareType() string {
+	if x != nil {
+		return x.FareType
+	}
+	return ""
+}
+
+func (x *ReservationResponse) GetRefund() *Refund {
+	if x != nil {
+		return x.Refund
+	}
+	return nil
+}
+
+func (x *ReservationResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *ReservationResponse) GetUnmetPreferences() []*UnmetPreference {
+	if x != nil {
+		return x.UnmetPreferences
+	}
+	return nil
+}
+
+func (x *ReservationResponse) GetJourneyId() uint64 {
+	if x != nil {
+		return x.JourneyId
+	}
+	return 0
+}
+
+type Payment struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Provider string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// The provider's payment ID.
+	Reference string `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	// PENDING until the provider answers, then CAPTURED or DECLINED. A
+	// cancelled ticket's payment becomes VOID_PENDING or REFUND_PENDING, and
+	// VOIDED or REFUNDED once the provider has been told.
+	Status   string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Amount   uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Refunded uint64 `protobuf:"varint,5,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	Currency string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Why the payment was declined.
+	Reason        string `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Payment) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Payment) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Payment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetRefunded() uint64 {
+	if x != nil {
+		return x.Refunded
+	}
+	return 0
+}
+
+func (x *Payment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Payment) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type HoldRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Section     string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	Seat        uint32                 `protobuf:"varint,2,opt,name=seat,proto3" json:"seat,omitempty"`
+	DepartureId uint64                 `protobuf:"varint,3,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// Stops the seat is held between, as in ReservationRequest.
+	FromCode      string `protobuf:"bytes,4,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string `protobuf:"bytes,5,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldRequest) Reset() {
+	*x = HoldRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldRequest) ProtoMessage() {}
+
+func (x *HoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldRequest.ProtoReflect.Descriptor instead.
+func (*HoldRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HoldRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *HoldRequest) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *HoldRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *HoldRequest) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *HoldRequest) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+type Hold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldId        string                 `protobuf:"bytes,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	Section       string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"`
+	Seat          uint32                 `protobuf:"varint,3,opt,name=seat,proto3" json:"seat,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	DepartureId   uint64                 `protobuf:"varint,5,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	FromCode      string                 `protobuf:"bytes,6,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string                 `protobuf:"bytes,7,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hold) Reset() {
+	*x = Hold{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hold) ProtoMessage() {}
+
+func (x *Hold) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hold.ProtoReflect.Descriptor instead.
+func (*Hold) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Hold) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *Hold) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Hold) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *Hold) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Hold) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *Hold) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *Hold) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive substring of the passenger name.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Section       string `protobuf:"bytes,3,opt,name=section,proto3" json:"section,omitempty"`
+	Status        string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	DepartureId   uint64 `protobuf:"varint,5,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	AccountId     uint64 `protobuf:"varint,6,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type SeatMapRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Section string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	// Defaults to the next departure.
+	DepartureId uint64 `protobuf:"varint,2,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// A seat is FREE if it is free all the way between these stops. Empty
+	// means the first or last stop.
+	FromCode      string `protobuf:"bytes,3,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string `protobuf:"bytes,4,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatMapRequest) Reset() {
+	*x = SeatMapRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatMapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatMapRequest) ProtoMessage() {}
+
+func (x *SeatMapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatMapRequest.ProtoReflect.Descriptor instead.
+func (*SeatMapRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SeatMapRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *SeatMapRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *SeatMapRequest) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *SeatMapRequest) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+type SeatMap struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Seats       []*SeatMap_Seat        `protobuf:"bytes,1,rep,name=seats,proto3" json:"seats,omitempty"`
+	DepartureId uint64                 `protobuf:"varint,2,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// The departure's layout; empty for the configured default.
+	Layout        string `protobuf:"bytes,3,opt,name=layout,proto3" json:"layout,omitempty"`
+	LayoutVersion uint32 `protobuf:"varint,4,opt,name=layout_version,json=layoutVersion,proto3" json:"layout_version,omitempty"`
+	FromCode      string `protobuf:"bytes,5,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string `protobuf:"bytes,6,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatMap) Reset() {
+	*x = SeatMap{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatMap) ProtoMessage() {}
+
+func (x *SeatMap) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatMap.ProtoReflect.Descriptor instead.
+func (*SeatMap) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SeatMap) GetSeats() []*SeatMap_Seat {
+	if x != nil {
+		return x.Seats
+	}
+	return nil
+}
+
+func (x *SeatMap) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *SeatMap) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+func (x *SeatMap) GetLayoutVersion() uint32 {
+	if x != nil {
+		return x.LayoutVersion
+	}
+	return 0
+}
+
+func (x *SeatMap) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *SeatMap) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+type DeparturesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Departures that call at from_code and later at to_code match, wherever
+	// along their run. Empty means the first or last stop.
+	FromCode string `protobuf:"bytes,1,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode   string `protobuf:"bytes,2,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	// Only departures on this day (YYYY-MM-DD, UTC).
+	Date          string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeparturesRequest) Reset() {
+	*x = DeparturesRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeparturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeparturesRequest) ProtoMessage() {}
+
+func (x *DeparturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeparturesRequest.ProtoReflect.Descriptor instead.
+func (*DeparturesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeparturesRequest) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *DeparturesRequest) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+func (x *DeparturesRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type Departure struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DepartureId uint64                 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	Train       string                 `protobuf:"bytes,2,opt,name=train,proto3" json:"train,omitempty"`
+	// The part of the run asked for: the stops boarded and alighted at, the
+	// times there and the seats free all the way between them.
+	FromCode  string                 `protobuf:"bytes,3,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode    string                 `protobuf:"bytes,4,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	DepartsAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=departs_at,json=departsAt,proto3" json:"departs_at,omitempty"`
+	ArrivesAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=arrives_at,json=arrivesAt,proto3" json:"arrives_at,omitempty"`
+	SeatsFree uint32                 `protobuf:"varint,7,opt,name=seats_free,json=seatsFree,proto3" json:"seats_free,omitempty"`
+	// Seats that can be sold, from the departure's layout.
+	Capacity uint32 `protobuf:"varint,8,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// Every stop of the run, in order.
+	Stops         []*Stop `protobuf:"bytes,9,rep,name=stops,proto3" json:"stops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Departure) Reset() {
+	*x = Departure{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Departure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Departure) ProtoMessage() {}
+
+func (x *Departure) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Departure.ProtoReflect.Descriptor instead.
+func (*Departure) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Departure) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *Departure) GetTrain() string {
+	if x != nil {
+		return x.Train
+	}
+	return ""
+}
+
+func (x *Departure) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *Departure) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+func (x *Departure) GetDepartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartsAt
+	}
+	return nil
+}
+
+func (x *Departure) GetArrivesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivesAt
+	}
+	return nil
+}
+
+func (x *Departure) GetSeatsFree() uint32 {
+	if x != nil {
+		return x.SeatsFree
+	}
+	return 0
+}
+
+func (x *Departure) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Departure) GetStops() []*Stop {
+	if x != nil {
+		return x.Stops
+	}
+	return nil
+}
+
+// Stop is a station a departure calls at. At the first stop arrives_at is
+// the departure time, and at the last departs_at the arrival time.
+type Stop struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	ArrivesAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=arrives_at,json=arrivesAt,proto3" json:"arrives_at,omitempty"`
+	DepartsAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=departs_at,json=departsAt,proto3" json:"departs_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stop) Reset() {
+	*x = Stop{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stop) ProtoMessage() {}
+
+func (x *Stop) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stop.ProtoReflect.Descriptor instead.
+func (*Stop) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Stop) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Stop) GetArrivesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivesAt
+	}
+	return nil
+}
+
+func (x *Stop) GetDepartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartsAt
+	}
+	return nil
+}
+
+type DepartureList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Departures    []*Departure           `protobuf:"bytes,1,rep,name=departures,proto3" json:"departures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepartureList) Reset() {
+	*x = DepartureList{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepartureList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepartureList) ProtoMessage() {}
+
+func (x *DepartureList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepartureList.ProtoReflect.Descriptor instead.
+func (*DepartureList) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DepartureList) GetDepartures() []*Departure {
+	if x != nil {
+		return x.Departures
+	}
+	return nil
+}
+
+type JourneyRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	FromCode string                 `protobuf:"bytes,1,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode   string                 `protobuf:"bytes,2,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	// Leave no earlier than this; now when unset.
+	DepartsAfter *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=departs_after,json=departsAfter,proto3" json:"departs_after,omitempty"`
+	// Most changes of train, at most MAX_TRANSFERS; that when unset.
+	MaxTransfers *uint32             `protobuf:"varint,4,opt,name=max_transfers,json=maxTransfers,proto3,oneof" json:"max_transfers,omitempty"`
+	Sort         JourneyRequest_Sort `protobuf:"varint,5,opt,name=sort,proto3,enum=ticket_reservation.JourneyRequest_Sort" json:"sort,omitempty"`
+	// Only itineraries with this many seats free on every leg; 1 when unset.
+	Passengers uint32 `protobuf:"varint,6,opt,name=passengers,proto3" json:"passengers,omitempty"`
+	// At most this many itineraries, 5 when unset and never more than 20.
+	Limit         uint32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JourneyRequest) Reset() {
+	*x = JourneyRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JourneyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JourneyRequest) ProtoMessage() {}
+
+func (x *JourneyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JourneyRequest.ProtoReflect.Descriptor instead.
+func (*JourneyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *JourneyRequest) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
+}
+
+func (x *JourneyRequest) GetToCode() string {
+	if x != nil {
+		return x.ToCode
+	}
+	return ""
+}
+
+func (x *JourneyRequest) GetDepartsAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartsAfter
+	}
+	return nil
+}
+
+func (x *JourneyRequest) GetMaxTransfers() uint32 {
+	if x != nil && x.MaxTransfers != nil {
+		return *x.MaxTransfers
+	}
+	return 0
+}
+
+func (x *JourneyRequest) GetSort() JourneyRequest_Sort {
+	if x != nil {
+		return x.Sort
+	}
+	return JourneyRequest_EARLIEST_ARRIVAL
+}
+
+func (x *JourneyRequest) GetPassengers() uint32 {
+	if x != nil {
+		return x.Passengers
+	}
+	return 0
+}
+
+func (x *JourneyRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Journey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The departures to take, each between the stops boarded and alighted
+	// at, as ListDepartures reports them.
+	Legs          []*Departure           `protobuf:"bytes,1,rep,name=legs,proto3" json:"legs,omitempty"`
+	DepartsAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=departs_at,json=departsAt,proto3" json:"departs_at,omitempty"`
+	ArrivesAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=arrives_at,json=arrivesAt,proto3" json:"arrives_at,omitempty"`
+	Changes       uint32                 `protobuf:"varint,4,opt,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Journey) Reset() {
+	*x = Journey{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Journey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Journey) ProtoMessage() {}
+
+func (x *Journey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Journey.ProtoReflect.Descriptor instead.
+func (*Journey) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Journey) GetLegs() []*Departure {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *Journey) GetDepartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartsAt
+	}
+	return nil
+}
+
+func (x *Journey) GetArrivesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivesAt
+	}
+	return nil
+}
+
+func (x *Journey) GetChanges() uint32 {
+	if x != nil {
+		return x.Changes
+	}
+	return 0
+}
+
+type JourneyList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Journeys      []*Journey             `protobuf:"bytes,1,rep,name=journeys,proto3" json:"journeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JourneyList) Reset() {
+	*x = JourneyList{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JourneyList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JourneyList) ProtoMessage() {}
+
+func (x *JourneyList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JourneyList.ProtoReflect.Descriptor instead.
+func (*JourneyList) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *JourneyList) GetJourneys() []*Journey {
+	if x != nil {
+		return x.Journeys
+	}
+	return nil
+}
+
+type JourneyReservationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// In travel order; each leg starts where the one before ends.
+	Legs       []*JourneyReservationRequest_Leg `protobuf:"bytes,1,rep,name=legs,proto3" json:"legs,omitempty"`
+	Passengers []*UserDetails                   `protobuf:"bytes,2,rep,name=passengers,proto3" json:"passengers,omitempty"`
+	FareType   string                           `protobuf:"bytes,3,opt,name=fare_type,json=fareType,proto3" json:"fare_type,omitempty"`
+	// Fare the customer agreed to for all legs together, as for
+	// ReserveTicket.
+	PricePaid uint64 `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
+	// Pays for every leg, as for ReserveTicket.
+	PaymentToken  string `protobuf:"bytes,5,opt,name=payment_token,json=paymentToken,proto3" json:"payment_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JourneyReservationRequest) Reset() {
+	*x = JourneyReservationRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JourneyReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JourneyReservationRequest) ProtoMessage() {}
+
+func (x *JourneyReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JourneyReservationRequest.ProtoReflect.Descriptor instead.
+func (*JourneyReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *JourneyReservationRequest) GetLegs() []*JourneyReservationRequest_Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *JourneyReservationRequest) GetPassengers() []*UserDetails {
+	if x != nil {
+		return x.Passengers
+	}
+	return nil
+}
+
+func (x *JourneyReservationRequest) GetFareType() string {
+	if x != nil {
+		return x.FareType
+	}
+	return ""
+}
+
+func (x *JourneyReservationRequest) GetPricePaid() uint64 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+func (x *JourneyReservationRequest) GetPaymentToken() string {
+	if x != nil {
+		return x.PaymentToken
+	}
+	return ""
+}
+
+type JourneyReservation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	JourneyId uint64                 `protobuf:"varint,1,opt,name=journey_id,json=journeyId,proto3" json:"journey_id,omitempty"`
+	// One ticket per leg, in travel order.
+	Tickets []*ReservationResponse `protobuf:"bytes,2,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	// Total of the tickets' fares.
+	PricePaid     uint64 `protobuf:"varint,3,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JourneyReservation) Reset() {
+	*x = JourneyReservation{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JourneyReservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JourneyReservation) ProtoMessage() {}
+
+func (x *JourneyReservation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JourneyReservation.ProtoReflect.Descriptor instead.
+func (*JourneyReservation) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JourneyReservation) GetJourneyId() uint64 {
+	if x != nil {
+		return x.JourneyId
+	}
+	return 0
+}
+
+func (x *JourneyReservation) GetTickets() []*ReservationResponse {
+	if x != nil {
+		return x.Tickets
+	}
+	return nil
+}
+
+func (x *JourneyReservation) GetPricePaid() uint64 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only events for this departure. Zero means all departures.
+	DepartureId   uint64 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// TicketReserved, TicketConfirmed, TicketPaymentFailed, TicketModified,
+	// TicketCancelled, TicketCheckedIn, PassengerBoarded, TicketNoShow,
+	// SeatHeld, HoldsExpired, SeatsReindexed, LayoutAttached,
+	// WaitlistPromoted or WaitlistExpired.
+	Type        string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	DepartureId uint64                 `protobuf:"varint,2,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	TicketNo    uint64                 `protobuf:"varint,3,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	Section     string                 `protobuf:"bytes,4,opt,name=section,proto3" json:"section,omitempty"`
+	Seat        uint32                 `protobuf:"varint,5,opt,name=seat,proto3" json:"seat,omitempty"`
+	At          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=at,proto3" json:"at,omitempty"`
+	// Set on waitlist events.
+	WaitlistId    uint64 `protobuf:"varint,7,opt,name=waitlist_id,json=waitlistId,proto3" json:"waitlist_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *Event) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *Event) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Event) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *Event) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *Event) GetWaitlistId() uint64 {
+	if x != nil {
+		return x.WaitlistId
+	}
+	return 0
+}
+
+type CreateAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Email string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// At least 8 characters.
+	Password      string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreateAccountRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type SignInRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignInRequest) Reset() {
+	*x = SignInRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignInRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignInRequest) ProtoMessage() {}
+
+func (x *SignInRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignInRequest.ProtoReflect.Descriptor instead.
+func (*SignInRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SignInRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SignInRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type Account struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId uint64                 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Email     string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// "customer", "staff" or "admin".
+	Role          string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Account) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Account) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Account) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Account) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type Fare struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DepartureId uint64                 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// Amounts are in minor units of currency, e.g. pence.
+	Total         uint64       `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Currency      string       `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Lines         []*Fare_Line `protobuf:"bytes,4,rep,name=lines,proto3" json:"lines,omitempty"`
+	FareType      string       `protobuf:"bytes,5,opt,name=fare_type,json=fareType,proto3" json:"fare_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fare) Reset() {
+	*x = Fare{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fare) ProtoMessage() {}
+
+func (x *Fare) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fare.ProtoReflect.Descriptor instead.
+func (*Fare) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Fare) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *Fare) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Fare) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Fare) GetLines() []*Fare_Line {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Fare) GetFareType() string {
+	if x != nil {
+		return x.FareType
+	}
+	return ""
+}
+
+type Refund struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Zero in a quote.
+	RefundId uint64 `protobuf:"varint,1,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	TicketNo uint64 `protobuf:"varint,2,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	FareType string `protobuf:"bytes,3,opt,name=fare_type,json=fareType,proto3" json:"fare_type,omitempty"`
+	// Amounts are in minor units of currency; amount is price_paid less fee.
+	PricePaid uint64 `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
+	Fee       uint64 `protobuf:"varint,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	Amount    uint64 `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency  string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	// The rule of the cancellation policy that applies, e.g. "50% refund
+	// until 2h before departure".
+	Policy        string                 `protobuf:"bytes,8,opt,name=policy,proto3" json:"policy,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Refund) Reset() {
+	*x = Refund{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Refund) ProtoMessage() {}
+
+func (x *Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Refund.ProtoReflect.Descriptor instead.
+func (*Refund) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Refund) GetRefundId() uint64 {
+	if x != nil {
+		return x.RefundId
+	}
+	return 0
+}
+
+func (x *Refund) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *Refund) GetFareType() string {
+	if x != nil {
+		return x.FareType
+	}
+	return ""
+}
+
+func (x *Refund) GetPricePaid() uint64 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+func (x *Refund) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Refund) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Refund) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Refund) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *Refund) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// DomainEvent records a change to a ticket for other systems. The event
+// relay delivers it, as JSON, to every sink in EVENT_SINKS.
+type DomainEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId uint64                 `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// TicketReserved, TicketConfirmed, TicketPaymentFailed, TicketModified,
+	// TicketCheckedIn, PassengerBoarded, TicketConsumed, TicketNoShow,
+	// TicketCancelled, or TicketImported for tickets booked before the ledger
+	// existed.
+	Type     string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	TicketNo uint64 `protobuf:"varint,3,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	// Numbers the events of a ticket from 1. Sinks receive them in this
+	// order, and may receive one more than once.
+	Sequence   uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// The ticket after the change; for TicketCancelled, just before it.
+	Ticket *ReservationResponse `protobuf:"bytes,6,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	// Set on TicketCancelled.
+	Refund        *Refund `protobuf:"bytes,7,opt,name=refund,proto3" json:"refund,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DomainEvent) Reset() {
+	*x = DomainEvent{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainEvent) ProtoMessage() {}
+
+func (x *DomainEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainEvent.ProtoReflect.Descriptor instead.
+func (*DomainEvent) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DomainEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *DomainEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DomainEvent) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *DomainEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DomainEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *DomainEvent) GetTicket() *ReservationResponse {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+func (x *DomainEvent) GetRefund() *Refund {
+	if x != nil {
+		return x.Refund
+	}
+	return nil
+}
+
+type TicketDocument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TicketDocument) Reset() {
+	*x = TicketDocument{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TicketDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TicketDocument) ProtoMessage() {}
+
+func (x *TicketDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TicketDocument.ProtoReflect.Descriptor instead.
+func (*TicketDocument) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TicketDocument) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *TicketDocument) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *TicketDocument) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type ValidateTicketRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The token from the ticket's QR code.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Who inspects the ticket, e.g. a conductor's staff number. Defaults to
+	// the authenticated caller.
+	Inspector string `protobuf:"bytes,2,opt,name=inspector,proto3" json:"inspector,omitempty"`
+	// Marks the passenger's ticket as used, e.g. at a ticket gate. A used
+	// ticket validates as ALREADY_USED afterwards. Only staff may consume
+	// tickets.
+	Consume       bool `protobuf:"varint,3,opt,name=consume,proto3" json:"consume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTicketRequest) Reset() {
+	*x = ValidateTicketRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTicketRequest) ProtoMessage() {}
+
+func (x *ValidateTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTicketRequest.ProtoReflect.Descriptor instead.
+func (*ValidateTicketRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ValidateTicketRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ValidateTicketRequest) GetInspector() string {
+	if x != nil {
+		return x.Inspector
+	}
+	return ""
+}
+
+func (x *ValidateTicketRequest) GetConsume() bool {
+	if x != nil {
+		return x.Consume
+	}
+	return false
+}
+
+type TicketValidation struct {
+	state       protoimpl.MessageState  `protogen:"open.v1"`
+	Result      TicketValidation_Result `protobuf:"varint,1,opt,name=result,proto3,enum=ticket_reservation.TicketValidation_Result" json:"result,omitempty"`
+	Message     string                  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TicketNo    uint64                  `protobuf:"varint,3,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	DepartureId uint64                  `protobuf:"varint,4,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	// Position of the passenger on the ticket; 0 is the lead passenger.
+	Position      uint32                 `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
+	PassengerName string                 `protobuf:"bytes,6,opt,name=passenger_name,json=passengerName,proto3" json:"passenger_name,omitempty"`
+	Section       string                 `protobuf:"bytes,7,opt,name=section,proto3" json:"section,omitempty"`
+	Seat          uint32                 `protobuf:"varint,8,opt,name=seat,proto3" json:"seat,omitempty"`
+	ValidFrom     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidUntil    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	// When the ticket was first used, if it has been.
+	UsedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=used_at,json=usedAt,proto3" json:"used_at,omitempty"`
+	InspectionId  uint64                 `protobuf:"varint,12,opt,name=inspection_id,json=inspectionId,proto3" json:"inspection_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TicketValidation) Reset() {
+	*x = TicketValidation{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TicketValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TicketValidation) ProtoMessage() {}
+
+func (x *TicketValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TicketValidation.ProtoReflect.Descriptor instead.
+func (*TicketValidation) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TicketValidation) GetResult() TicketValidation_Result {
+	if x != nil {
+		return x.Result
+	}
+	return TicketValidation_VALID
+}
+
+func (x *TicketValidation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TicketValidation) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *TicketValidation) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *TicketValidation) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *TicketValidation) GetPassengerName() string {
+	if x != nil {
+		return x.PassengerName
+	}
+	return ""
+}
+
+func (x *TicketValidation) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *TicketValidation) GetSeat() uint32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *TicketValidation) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *TicketValidation) GetValidUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return nil
+}
+
+func (x *TicketValidation) GetUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UsedAt
+	}
+	return nil
+}
+
+func (x *TicketValidation) GetInspectionId() uint64 {
+	if x != nil {
+		return x.InspectionId
+	}
+	return 0
+}
+
+type BoardRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TicketNo uint64                 `protobuf:"varint,1,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	// Passenger to board; 0 is the lead passenger.
+	Position      uint32 `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoardRequest) Reset() {
+	*x = BoardRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardRequest) ProtoMessage() {}
+
+func (x *BoardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardRequest.ProtoReflect.Descriptor instead.
+func (*BoardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BoardRequest) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *BoardRequest) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type ManifestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DepartureId   uint64                 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestRequest) Reset() {
+	*x = ManifestRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestRequest) ProtoMessage() {}
+
+func (x *ManifestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestRequest.ProtoReflect.Descriptor instead.
+func (*ManifestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ManifestRequest) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+type BoardingManifest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Departure *Departure             `protobuf:"bytes,1,opt,name=departure,proto3" json:"departure,omitempty"`
+	// Ordered by section, then seat.
+	Passengers      []*BoardingManifest_Passenger `protobuf:"bytes,2,rep,name=passengers,proto3" json:"passengers,omitempty"`
+	CheckInOpensAt  *timestamppb.Timestamp        `protobuf:"bytes,3,opt,name=check_in_opens_at,json=checkInOpensAt,proto3" json:"check_in_opens_at,omitempty"`
+	CheckInClosesAt *timestamppb.Timestamp        `protobuf:"bytes,4,opt,name=check_in_closes_at,json=checkInClosesAt,proto3" json:"check_in_closes_at,omitempty"`
+	BoardingOpensAt *timestamppb.Timestamp        `protobuf:"bytes,5,opt,name=boarding_opens_at,json=boardingOpensAt,proto3" json:"boarding_opens_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BoardingManifest) Reset() {
+	*x = BoardingManifest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoardingManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardingManifest) ProtoMessage() {}
+
+func (x *BoardingManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardingManifest.ProtoReflect.Descriptor instead.
+func (*BoardingManifest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BoardingManifest) GetDeparture() *Departure {
+	if x != nil {
+		return x.Departure
+	}
+	return nil
+}
+
+func (x *BoardingManifest) GetPassengers() []*BoardingManifest_Passenger {
+	if x != nil {
+		return x.Passengers
+	}
+	return nil
+}
+
+func (x *BoardingManifest) GetCheckInOpensAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckInOpensAt
+	}
+	return nil
+}
+
+func (x *BoardingManifest) GetCheckInClosesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckInClosesAt
+	}
+	return nil
+}
+
+func (x *BoardingManifest) GetBoardingOpensAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BoardingOpensAt
+	}
+	return nil
+}
+
+type JoinWaitlistRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The booking to make once seats are free. It must name a departure or a
+	// route, and cannot pick seats or use a hold.
+	Request *ReservationRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Higher priorities are promoted first; equal priorities in the order
+	// they joined.
+	Priority      int32 `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinWaitlistRequest) Reset() {
+	*x = JoinWaitlistRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinWaitlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinWaitlistRequest) ProtoMessage() {}
+
+func (x *JoinWaitlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinWaitlistRequest.ProtoReflect.Descriptor instead.
+func (*JoinWaitlistRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *JoinWaitlistRequest) GetRequest() *ReservationRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *JoinWaitlistRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type WaitlistQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// For LeaveWaitlist.
+	WaitlistId uint64 `protobuf:"varint,1,opt,name=waitlist_id,json=waitlistId,proto3" json:"waitlist_id,omitempty"`
+	// For ListWaitlist by staff. Zero lists the caller's own entries.
+	DepartureId   uint64 `protobuf:"varint,2,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitlistQuery) Reset() {
+	*x = WaitlistQuery{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitlistQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitlistQuery) ProtoMessage() {}
+
+func (x *WaitlistQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitlistQuery.ProtoReflect.Descriptor instead.
+func (*WaitlistQuery) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WaitlistQuery) GetWaitlistId() uint64 {
+	if x != nil {
+		return x.WaitlistId
+	}
+	return 0
+}
+
+func (x *WaitlistQuery) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+type ClaimWaitlistRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	WaitlistId uint64                 `protobuf:"varint,1,opt,name=waitlist_id,json=waitlistId,proto3" json:"waitlist_id,omitempty"`
+	// Fare the customer agreed to, as for ReserveTicket.
+	PricePaid uint64 `protobuf:"varint,2,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
+	// Pays for the booking, as for ReserveTicket.
+	PaymentToken  string `protobuf:"bytes,3,opt,name=payment_token,json=paymentToken,proto3" json:"payment_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimWaitlistRequest) Reset() {
+	*x = ClaimWaitlistRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimWaitlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimWaitlistRequest) ProtoMessage() {}
+
+func (x *ClaimWaitlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimWaitlistRequest.ProtoReflect.Descriptor instead.
+func (*ClaimWaitlistRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ClaimWaitlistRequest) GetWaitlistId() uint64 {
+	if x != nil {
+		return x.WaitlistId
+	}
+	return 0
+}
+
+func (x *ClaimWaitlistRequest) GetPricePaid() uint64 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+func (x *ClaimWaitlistRequest) GetPaymentToken() string {
+	if x != nil {
+		return x.PaymentToken
+	}
+	return ""
+}
+
+type WaitlistEntry struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WaitlistId     uint64                 `protobuf:"varint,1,opt,name=waitlist_id,json=waitlistId,proto3" json:"waitlist_id,omitempty"`
+	DepartureId    uint64                 `protobuf:"varint,2,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	Status         WaitlistEntry_Status   `protobuf:"varint,3,opt,name=status,proto3,enum=ticket_reservation.WaitlistEntry_Status" json:"status,omitempty"`
+	Priority       int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	PassengerCount uint32                 `protobuf:"varint,5,opt,name=passenger_count,json=passengerCount,proto3" json:"passenger_count,omitempty"`
+	// Place in line among waiting entries of the departure, from 1.
+	Position      uint32                 `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	JoinedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	HoldExpiresAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=hold_expires_at,json=holdExpiresAt,proto3" json:"hold_expires_at,omitempty"`
+	// Set once booked.
+	TicketNo      uint64              `protobuf:"varint,9,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	AccountId     uint64              `protobuf:"varint,10,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Request       *ReservationRequest `protobuf:"bytes,11,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitlistEntry) Reset() {
+	*x = WaitlistEntry{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitlistEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitlistEntry) ProtoMessage() {}
+
+func (x *WaitlistEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitlistEntry.ProtoReflect.Descriptor instead.
+func (*WaitlistEntry) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *WaitlistEntry) GetWaitlistId() uint64 {
+	if x != nil {
+		return x.WaitlistId
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetStatus() WaitlistEntry_Status {
+	if x != nil {
+		return x.Status
+	}
+	return WaitlistEntry_WAITING
+}
+
+func (x *WaitlistEntry) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetPassengerCount() uint32 {
+	if x != nil {
+		return x.PassengerCount
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
+func (x *WaitlistEntry) GetHoldExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HoldExpiresAt
+	}
+	return nil
+}
+
+func (x *WaitlistEntry) GetTicketNo() uint64 {
+	if x != nil {
+		return x.TicketNo
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetRequest() *ReservationRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type WaitlistEntries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*WaitlistEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitlistEntries) Reset() {
+	*x = WaitlistEntries{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitlistEntries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitlistEntries) ProtoMessage() {}
+
+func (x *WaitlistEntries) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitlistEntries.ProtoReflect.Descriptor instead.
+func (*WaitlistEntries) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *WaitlistEntries) GetEntries() []*WaitlistEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type EmptyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyRequest) Reset() {
+	*x = EmptyRequest{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyRequest) ProtoMessage() {}
+
+func (x *EmptyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyRequest.ProtoReflect.Descriptor instead.
+func (*EmptyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{39}
+}
+
+type AllTicketsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
-	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
-	Seat          uint32                 `protobuf:"varint,5,opt,name=seat,proto3" json:"seat,omitempty"`
-	Section       string                 `protobuf:"bytes,6,opt,name=section,proto3" json:"section,omitempty"`
+	Tickets       []*ReservationResponse `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllTicketsResponse) Reset() {
+	*x = AllTicketsResponse{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllTicketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllTicketsResponse) ProtoMessage() {}
+
+func (x *AllTicketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllTicketsResponse.ProtoReflect.Descriptor instead.
+func (*AllTicketsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *AllTicketsResponse) GetTickets() []*ReservationResponse {
+	if x != nil {
+		return x.Tickets
+	}
+	return nil
+}
+
+type SeatMap_Seat struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Section    string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	Seat       uint32                 `protobuf:"varint,2,opt,name=seat,proto3" json:"seat,omitempty"`
+	State      SeatMap_State          `protobuf:"varint,3,opt,name=state,proto3,enum=ticket_reservation.SeatMap_State" json:"state,omitempty"`
+	CoachClass string                 `protobuf:"bytes,4,opt,name=coach_class,json=coachClass,proto3" json:"coach_class,omitempty"`
+	// What the seat offers, as matched against SeatPreferences.
+	Position   SeatPreferences_Position `protobuf:"varint,5,opt,name=position,proto3,enum=ticket_reservation.SeatPreferences_Position" json:"position,omitempty"`
+	Facing     SeatPreferences_Facing   `protobuf:"varint,6,opt,name=facing,proto3,enum=ticket_reservation.SeatPreferences_Facing" json:"facing,omitempty"`
+	QuietCoach bool                     `protobuf:"varint,7,opt,name=quiet_coach,json=quietCoach,proto3" json:"quiet_coach,omitempty"`
+	NearExit   bool                     `protobuf:"varint,8,opt,name=near_exit,json=nearExit,proto3" json:"near_exit,omitempty"`
+	Accessible bool                     `protobuf:"varint,9,opt,name=accessible,proto3" json:"accessible,omitempty"`
+	// Row in the coach, from 1 at the front.
+	Row           uint32 `protobuf:"varint,10,opt,name=row,proto3" json:"row,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserDetails) Reset() {
-	*x = UserDetails{}
-	mi := &file_proto_ticket_reservation_proto_msgTypes[0]
+func (x *SeatMap_Seat) Reset() {
+	*x = SeatMap_Seat{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserDetails) String() string {
+func (x *SeatMap_Seat) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserDetails) ProtoMessage() {}
+func (*SeatMap_Seat) ProtoMessage() {}
 
-func (x *UserDetails) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ticket_reservation_proto_msgTypes[0]
+func (x *SeatMap_Seat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,80 +3593,105 @@ func (x *UserDetails) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserDetails.ProtoReflect.Descriptor instead.
-func (*UserDetails) Descriptor() ([]byte, []int) {
-	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use SeatMap_Seat.ProtoReflect.Descriptor instead.
+func (*SeatMap_Seat) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{10, 0}
 }
 
-func (x *UserDetails) GetFirstName() string {
+func (x *SeatMap_Seat) GetSection() string {
 	if x != nil {
-		return x.FirstName
+		return x.Section
 	}
 	return ""
 }
 
-func (x *UserDetails) GetLastName() string {
+func (x *SeatMap_Seat) GetSeat() uint32 {
 	if x != nil {
-		return x.LastName
+		return x.Seat
 	}
-	return ""
+	return 0
 }
 
-func (x *UserDetails) GetEmail() string {
+func (x *SeatMap_Seat) GetState() SeatMap_State {
 	if x != nil {
-		return x.Email
+		return x.State
 	}
-	return ""
+	return SeatMap_FREE
 }
 
-func (x *UserDetails) GetAddress() string {
+func (x *SeatMap_Seat) GetCoachClass() string {
 	if x != nil {
-		return x.Address
+		return x.CoachClass
 	}
 	return ""
 }
 
-func (x *UserDetails) GetSeat() uint32 {
+func (x *SeatMap_Seat) GetPosition() SeatPreferences_Position {
 	if x != nil {
-		return x.Seat
+		return x.Position
 	}
-	return 0
+	return SeatPreferences_ANY_POSITION
 }
 
-func (x *UserDetails) GetSection() string {
+func (x *SeatMap_Seat) GetFacing() SeatPreferences_Facing {
 	if x != nil {
-		return x.Section
+		return x.Facing
 	}
-	return ""
+	return SeatPreferences_ANY_DIRECTION
 }
 
-type ReservationRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	TicketNo       *uint64                `protobuf:"varint,1,opt,name=ticket_no,json=ticketNo,proto3,oneof" json:"ticket_no,omitempty"`
-	FromCode       string                 `protobuf:"bytes,2,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
-	ToCode         string                 `protobuf:"bytes,3,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
-	PricePaid      uint64                 `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
-	PassengerCount uint64                 `protobuf:"varint,5,opt,name=passenger_count,json=passengerCount,proto3" json:"passenger_count,omitempty"`
-	Passengers     []*UserDetails         `protobuf:"bytes,6,rep,name=passengers,proto3" json:"passengers,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *SeatMap_Seat) GetQuietCoach() bool {
+	if x != nil {
+		return x.QuietCoach
+	}
+	return false
 }
 
-func (x *ReservationRequest) Reset() {
-	*x = ReservationRequest{}
-	mi := &file_proto_ticket_reservation_proto_msgTypes[1]
+func (x *SeatMap_Seat) GetNearExit() bool {
+	if x != nil {
+		return x.NearExit
+	}
+	return false
+}
+
+func (x *SeatMap_Seat) GetAccessible() bool {
+	if x != nil {
+		return x.Accessible
+	}
+	return false
+}
+
+func (x *SeatMap_Seat) GetRow() uint32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+type JourneyReservationRequest_Leg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DepartureId   uint64                 `protobuf:"varint,1,opt,name=departure_id,json=departureId,proto3" json:"departure_id,omitempty"`
+	FromCode      string                 `protobuf:"bytes,2,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string                 `protobuf:"bytes,3,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JourneyReservationRequest_Leg) Reset() {
+	*x = JourneyReservationRequest_Leg{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReservationRequest) String() string {
+func (x *JourneyReservationRequest_Leg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReservationRequest) ProtoMessage() {}
+func (*JourneyReservationRequest_Leg) ProtoMessage() {}
 
-func (x *ReservationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ticket_reservation_proto_msgTypes[1]
+func (x *JourneyReservationRequest_Leg) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -142,81 +3702,116 @@ func (x *ReservationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReservationRequest.ProtoReflect.Descriptor instead.
-func (*ReservationRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use JourneyReservationRequest_Leg.ProtoReflect.Descriptor instead.
+func (*JourneyReservationRequest_Leg) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{18, 0}
 }
 
-func (x *ReservationRequest) GetTicketNo() uint64 {
-	if x != nil && x.TicketNo != nil {
-		return *x.TicketNo
+func (x *JourneyReservationRequest_Leg) GetDepartureId() uint64 {
+	if x != nil {
+		return x.DepartureId
 	}
 	return 0
 }
 
-func (x *ReservationRequest) GetFromCode() string {
+func (x *JourneyReservationRequest_Leg) GetFromCode() string {
 	if x != nil {
 		return x.FromCode
 	}
 	return ""
 }
 
-func (x *ReservationRequest) GetToCode() string {
+func (x *JourneyReservationRequest_Leg) GetToCode() string {
 	if x != nil {
 		return x.ToCode
 	}
 	return ""
 }
 
-func (x *ReservationRequest) GetPricePaid() uint64 {
+type Fare_Line struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Amount        uint64                 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fare_Line) Reset() {
+	*x = Fare_Line{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fare_Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fare_Line) ProtoMessage() {}
+
+func (x *Fare_Line) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[43]
 	if x != nil {
-		return x.PricePaid
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ReservationRequest) GetPassengerCount() uint64 {
+// Deprecated: Use Fare_Line.ProtoReflect.Descriptor instead.
+func (*Fare_Line) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{25, 0}
+}
+
+func (x *Fare_Line) GetDescription() string {
 	if x != nil {
-		return x.PassengerCount
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-func (x *ReservationRequest) GetPassengers() []*UserDetails {
+func (x *Fare_Line) GetAmount() uint64 {
 	if x != nil {
-		return x.Passengers
+		return x.Amount
 	}
-	return nil
+	return 0
 }
 
-type ReservationResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	TicketNo       uint64                 `protobuf:"varint,1,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
-	FromCode       string                 `protobuf:"bytes,2,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
-	ToCode         string                 `protobuf:"bytes,3,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
-	PricePaid      uint64                 `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"`
-	PassengerCount uint64                 `protobuf:"varint,5,opt,name=passenger_count,json=passengerCount,proto3" json:"passenger_count,omitempty"`
-	Passengers     []*UserDetails         `protobuf:"bytes,6,rep,name=passengers,proto3" json:"passengers,omitempty"`
-	Status         string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type BoardingManifest_Passenger struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Section     string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	Seat        uint32                 `protobuf:"varint,2,opt,name=seat,proto3" json:"seat,omitempty"`
+	TicketNo    uint64                 `protobuf:"varint,3,opt,name=ticket_no,json=ticketNo,proto3" json:"ticket_no,omitempty"`
+	Position    uint32                 `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	Name        string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	State       BoardingManifest_State `protobuf:"varint,6,opt,name=state,proto3,enum=ticket_reservation.BoardingManifest_State" json:"state,omitempty"`
+	CheckedInAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=checked_in_at,json=checkedInAt,proto3" json:"checked_in_at,omitempty"`
+	BoardedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=boarded_at,json=boardedAt,proto3" json:"boarded_at,omitempty"`
+	// Where the passenger boards and alights.
+	FromCode      string `protobuf:"bytes,9,opt,name=from_code,json=fromCode,proto3" json:"from_code,omitempty"`
+	ToCode        string `protobuf:"bytes,10,opt,name=to_code,json=toCode,proto3" json:"to_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReservationResponse) Reset() {
-	*x = ReservationResponse{}
-	mi := &file_proto_ticket_reservation_proto_msgTypes[2]
+func (x *BoardingManifest_Passenger) Reset() {
+	*x = BoardingManifest_Passenger{}
+	mi := &file_proto_ticket_reservation_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReservationResponse) String() string {
+func (x *BoardingManifest_Passenger) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReservationResponse) ProtoMessage() {}
+func (*BoardingManifest_Passenger) ProtoMessage() {}
 
-func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ticket_reservation_proto_msgTypes[2]
+func (x *BoardingManifest_Passenger) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ticket_reservation_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,145 +3822,86 @@ func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReservationResponse.ProtoReflect.Descriptor instead.
-func (*ReservationResponse) Descriptor() ([]byte, []int) {
-	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *ReservationResponse) GetTicketNo() uint64 {
-	if x != nil {
-		return x.TicketNo
-	}
-	return 0
+// Deprecated: Use BoardingManifest_Passenger.ProtoReflect.Descriptor instead.
+func (*BoardingManifest_Passenger) Descriptor() ([]byte, []int) {
+	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{33, 0}
 }
 
-func (x *ReservationResponse) GetFromCode() string {
+func (x *BoardingManifest_Passenger) GetSection() string {
 	if x != nil {
-		return x.FromCode
+		return x.Section
 	}
 	return ""
 }
 
-func (x *ReservationResponse) GetToCode() string {
+func (x *BoardingManifest_Passenger) GetSeat() uint32 {
 	if x != nil {
-		return x.ToCode
+		return x.Seat
 	}
-	return ""
+	return 0
 }
 
-func (x *ReservationResponse) GetPricePaid() uint64 {
+func (x *BoardingManifest_Passenger) GetTicketNo() uint64 {
 	if x != nil {
-		return x.PricePaid
+		return x.TicketNo
 	}
 	return 0
 }
 
-func (x *ReservationResponse) GetPassengerCount() uint64 {
+func (x *BoardingManifest_Passenger) GetPosition() uint32 {
 	if x != nil {
-		return x.PassengerCount
+		return x.Position
 	}
 	return 0
 }
 
-func (x *ReservationResponse) GetPassengers() []*UserDetails {
+func (x *BoardingManifest_Passenger) GetName() string {
 	if x != nil {
-		return x.Passengers
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *ReservationResponse) GetStatus() string {
+func (x *BoardingManifest_Passenger) GetState() BoardingManifest_State {
 	if x != nil {
-		return x.Status
+		return x.State
 	}
-	return ""
-}
-
-type EmptyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *EmptyRequest) Reset() {
-	*x = EmptyRequest{}
-	mi := &file_proto_ticket_reservation_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *EmptyRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return BoardingManifest_BOOKED
 }
 
-func (*EmptyRequest) ProtoMessage() {}
-
-func (x *EmptyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ticket_reservation_proto_msgTypes[3]
+func (x *BoardingManifest_Passenger) GetCheckedInAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.CheckedInAt
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use EmptyRequest.ProtoReflect.Descriptor instead.
-func (*EmptyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{3}
-}
-
-type AllTicketsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tickets       []*ReservationResponse `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *AllTicketsResponse) Reset() {
-	*x = AllTicketsResponse{}
-	mi := &file_proto_ticket_reservation_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *AllTicketsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return nil
 }
 
-func (*AllTicketsResponse) ProtoMessage() {}
-
-func (x *AllTicketsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ticket_reservation_proto_msgTypes[4]
+func (x *BoardingManifest_Passenger) GetBoardedAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.BoardedAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use AllTicketsResponse.ProtoReflect.Descriptor instead.
-func (*AllTicketsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_ticket_reservation_proto_rawDescGZIP(), []int{4}
+func (x *BoardingManifest_Passenger) GetFromCode() string {
+	if x != nil {
+		return x.FromCode
+	}
+	return ""
 }
 
-func (x *AllTicketsResponse) GetTickets() []*ReservationResponse {
+func (x *BoardingManifest_Passenger) GetToCode() string {
 	if x != nil {
-		return x.Tickets
+		return x.ToCode
 	}
-	return nil
+	return ""
 }
 
 var File_proto_ticket_reservation_proto protoreflect.FileDescriptor
 
 const file_proto_ticket_reservation_proto_rawDesc = "" +
 	"\n" +
-	"\x1eproto/ticket_reservation.proto\x12\x12ticket_reservation\"\xa8\x01\n" +
+	"\x1eproto/ticket_reservation.proto\x12\x12ticket_reservation\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x02\n" +
 	"\fuser_details\x12\x1d\n" +
 	"\n" +
 	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
@@ -373,7 +3909,33 @@ const file_proto_ticket_reservation_proto_rawDesc = "" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x18\n" +
 	"\aaddress\x18\x04 \x01(\tR\aaddress\x12\x12\n" +
 	"\x04seat\x18\x05 \x01(\rR\x04seat\x12\x18\n" +
-	"\asection\x18\x06 \x01(\tR\asection\"\x84\x02\n" +
+	"\asection\x18\x06 \x01(\tR\asection\x12E\n" +
+	"\vpreferences\x18\a \x01(\v2#.ticket_reservation.SeatPreferencesR\vpreferences\x12\x14\n" +
+	"\x05party\x18\b \x01(\tR\x05party\"\xea\x02\n" +
+	"\x0fSeatPreferences\x12H\n" +
+	"\bposition\x18\x01 \x01(\x0e2,.ticket_reservation.SeatPreferences.PositionR\bposition\x12B\n" +
+	"\x06facing\x18\x02 \x01(\x0e2*.ticket_reservation.SeatPreferences.FacingR\x06facing\x12\x1f\n" +
+	"\vquiet_coach\x18\x03 \x01(\bR\n" +
+	"quietCoach\x12\x1b\n" +
+	"\tnear_exit\x18\x04 \x01(\bR\bnearExit\x12\x1e\n" +
+	"\n" +
+	"accessible\x18\x05 \x01(\bR\n" +
+	"accessible\"3\n" +
+	"\bPosition\x12\x10\n" +
+	"\fANY_POSITION\x10\x00\x12\n" +
+	"\n" +
+	"\x06WINDOW\x10\x01\x12\t\n" +
+	"\x05AISLE\x10\x02\"6\n" +
+	"\x06Facing\x12\x11\n" +
+	"\rANY_DIRECTION\x10\x00\x12\v\n" +
+	"\aFORWARD\x10\x01\x12\f\n" +
+	"\bBACKWARD\x10\x02\"g\n" +
+	"\x0fUnmetPreference\x12\x1c\n" +
+	"\tpassenger\x18\x01 \x01(\rR\tpassenger\x12\x1e\n" +
+	"\n" +
+	"preference\x18\x02 \x01(\tR\n" +
+	"preference\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x82\x03\n" +
 	"\x12ReservationRequest\x12 \n" +
 	"\tticket_no\x18\x01 \x01(\x04H\x00R\bticketNo\x88\x01\x01\x12\x1b\n" +
 	"\tfrom_code\x18\x02 \x01(\tR\bfromCode\x12\x17\n" +
@@ -383,9 +3945,14 @@ const file_proto_ticket_reservation_proto_rawDesc = "" +
 	"\x0fpassenger_count\x18\x05 \x01(\x04R\x0epassengerCount\x12@\n" +
 	"\n" +
 	"passengers\x18\x06 \x03(\v2 .ticket_reservation.user_detailsR\n" +
-	"passengersB\f\n" +
+	"passengers\x12\x17\n" +
+	"\ahold_id\x18\a \x01(\tR\x06holdId\x12!\n" +
+	"\fdeparture_id\x18\b \x01(\x04R\vdepartureId\x12\x1b\n" +
+	"\tfare_type\x18\t \x01(\tR\bfareType\x12#\n" +
+	"\rpayment_token\x18\n" +
+	" \x01(\tR\fpaymentTokenB\f\n" +
 	"\n" +
-	"_ticket_no\"\x8a\x02\n" +
+	"_ticket_no\"\xdd\x04\n" +
 	"\x13ReservationResponse\x12\x1b\n" +
 	"\tticket_no\x18\x01 \x01(\x04R\bticketNo\x12\x1b\n" +
 	"\tfrom_code\x18\x02 \x01(\tR\bfromCode\x12\x17\n" +
@@ -396,15 +3963,341 @@ const file_proto_ticket_reservation_proto_rawDesc = "" +
 	"\n" +
 	"passengers\x18\x06 \x03(\v2 .ticket_reservation.user_detailsR\n" +
 	"passengers\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\"\x0e\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12!\n" +
+	"\fdeparture_id\x18\b \x01(\x04R\vdepartureId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\t \x01(\x04R\taccountId\x12\x16\n" +
+	"\x06tokens\x18\n" +
+	" \x03(\tR\x06tokens\x12\x1b\n" +
+	"\tfare_type\x18\v \x01(\tR\bfareType\x122\n" +
+	"\x06refund\x18\f \x01(\v2\x1a.ticket_reservation.RefundR\x06refund\x125\n" +
+	"\apayment\x18\r \x01(\v2\x1b.ticket_reservation.PaymentR\apayment\x12P\n" +
+	"\x11unmet_preferences\x18\x0e \x03(\v2#.ticket_reservation.UnmetPreferenceR\x10unmetPreferences\x12\x1d\n" +
+	"\n" +
+	"journey_id\x18\x0f \x01(\x04R\tjourneyId\"\xc3\x01\n" +
+	"\aPayment\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x1c\n" +
+	"\treference\x18\x02 \x01(\tR\treference\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x04R\x06amount\x12\x1a\n" +
+	"\brefunded\x18\x05 \x01(\x04R\brefunded\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\"\x94\x01\n" +
+	"\vHoldRequest\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x02 \x01(\rR\x04seat\x12!\n" +
+	"\fdeparture_id\x18\x03 \x01(\x04R\vdepartureId\x12\x1b\n" +
+	"\tfrom_code\x18\x04 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x05 \x01(\tR\x06toCode\"\xe1\x01\n" +
+	"\x04Hold\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\tR\x06holdId\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x03 \x01(\rR\x04seat\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12!\n" +
+	"\fdeparture_id\x18\x05 \x01(\x04R\vdepartureId\x12\x1b\n" +
+	"\tfrom_code\x18\x06 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\a \x01(\tR\x06toCode\"\xad\x01\n" +
+	"\rSearchRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x18\n" +
+	"\asection\x18\x03 \x01(\tR\asection\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12!\n" +
+	"\fdeparture_id\x18\x05 \x01(\x04R\vdepartureId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x06 \x01(\x04R\taccountId\"\x83\x01\n" +
+	"\x0eSeatMapRequest\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12!\n" +
+	"\fdeparture_id\x18\x02 \x01(\x04R\vdepartureId\x12\x1b\n" +
+	"\tfrom_code\x18\x03 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x04 \x01(\tR\x06toCode\"\x9e\x05\n" +
+	"\aSeatMap\x126\n" +
+	"\x05seats\x18\x01 \x03(\v2 .ticket_reservation.SeatMap.SeatR\x05seats\x12!\n" +
+	"\fdeparture_id\x18\x02 \x01(\x04R\vdepartureId\x12\x16\n" +
+	"\x06layout\x18\x03 \x01(\tR\x06layout\x12%\n" +
+	"\x0elayout_version\x18\x04 \x01(\rR\rlayoutVersion\x12\x1b\n" +
+	"\tfrom_code\x18\x05 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x06 \x01(\tR\x06toCode\x1a\x8c\x03\n" +
+	"\x04Seat\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x02 \x01(\rR\x04seat\x127\n" +
+	"\x05state\x18\x03 \x01(\x0e2!.ticket_reservation.SeatMap.StateR\x05state\x12\x1f\n" +
+	"\vcoach_class\x18\x04 \x01(\tR\n" +
+	"coachClass\x12H\n" +
+	"\bposition\x18\x05 \x01(\x0e2,.ticket_reservation.SeatPreferences.PositionR\bposition\x12B\n" +
+	"\x06facing\x18\x06 \x01(\x0e2*.ticket_reservation.SeatPreferences.FacingR\x06facing\x12\x1f\n" +
+	"\vquiet_coach\x18\a \x01(\bR\n" +
+	"quietCoach\x12\x1b\n" +
+	"\tnear_exit\x18\b \x01(\bR\bnearExit\x12\x1e\n" +
+	"\n" +
+	"accessible\x18\t \x01(\bR\n" +
+	"accessible\x12\x10\n" +
+	"\x03row\x18\n" +
+	" \x01(\rR\x03row\"4\n" +
+	"\x05State\x12\b\n" +
+	"\x04FREE\x10\x00\x12\b\n" +
+	"\x04HELD\x10\x01\x12\n" +
+	"\n" +
+	"\x06BOOKED\x10\x02\x12\v\n" +
+	"\aBLOCKED\x10\x03\"]\n" +
+	"\x11DeparturesRequest\x12\x1b\n" +
+	"\tfrom_code\x18\x01 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x02 \x01(\tR\x06toCode\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\"\xdb\x02\n" +
+	"\tDeparture\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\x12\x14\n" +
+	"\x05train\x18\x02 \x01(\tR\x05train\x12\x1b\n" +
+	"\tfrom_code\x18\x03 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x04 \x01(\tR\x06toCode\x129\n" +
+	"\n" +
+	"departs_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tdepartsAt\x129\n" +
+	"\n" +
+	"arrives_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tarrivesAt\x12\x1d\n" +
+	"\n" +
+	"seats_free\x18\a \x01(\rR\tseatsFree\x12\x1a\n" +
+	"\bcapacity\x18\b \x01(\rR\bcapacity\x12.\n" +
+	"\x05stops\x18\t \x03(\v2\x18.ticket_reservation.StopR\x05stops\"\x90\x01\n" +
+	"\x04Stop\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x129\n" +
+	"\n" +
+	"arrives_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tarrivesAt\x129\n" +
+	"\n" +
+	"departs_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tdepartsAt\"N\n" +
+	"\rDepartureList\x12=\n" +
+	"\n" +
+	"departures\x18\x01 \x03(\v2\x1d.ticket_reservation.DepartureR\n" +
+	"departures\"\xe8\x02\n" +
+	"\x0eJourneyRequest\x12\x1b\n" +
+	"\tfrom_code\x18\x01 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x02 \x01(\tR\x06toCode\x12?\n" +
+	"\rdeparts_after\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fdepartsAfter\x12(\n" +
+	"\rmax_transfers\x18\x04 \x01(\rH\x00R\fmaxTransfers\x88\x01\x01\x12;\n" +
+	"\x04sort\x18\x05 \x01(\x0e2'.ticket_reservation.JourneyRequest.SortR\x04sort\x12\x1e\n" +
+	"\n" +
+	"passengers\x18\x06 \x01(\rR\n" +
+	"passengers\x12\x14\n" +
+	"\x05limit\x18\a \x01(\rR\x05limit\"0\n" +
+	"\x04Sort\x12\x14\n" +
+	"\x10EARLIEST_ARRIVAL\x10\x00\x12\x12\n" +
+	"\x0eFEWEST_CHANGES\x10\x01B\x10\n" +
+	"\x0e_max_transfers\"\xcc\x01\n" +
+	"\aJourney\x121\n" +
+	"\x04legs\x18\x01 \x03(\v2\x1d.ticket_reservation.DepartureR\x04legs\x129\n" +
+	"\n" +
+	"departs_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tdepartsAt\x129\n" +
+	"\n" +
+	"arrives_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tarrivesAt\x12\x18\n" +
+	"\achanges\x18\x04 \x01(\rR\achanges\"F\n" +
+	"\vJourneyList\x127\n" +
+	"\bjourneys\x18\x01 \x03(\v2\x1b.ticket_reservation.JourneyR\bjourneys\"\xe5\x02\n" +
+	"\x19JourneyReservationRequest\x12E\n" +
+	"\x04legs\x18\x01 \x03(\v21.ticket_reservation.JourneyReservationRequest.LegR\x04legs\x12@\n" +
+	"\n" +
+	"passengers\x18\x02 \x03(\v2 .ticket_reservation.user_detailsR\n" +
+	"passengers\x12\x1b\n" +
+	"\tfare_type\x18\x03 \x01(\tR\bfareType\x12\x1d\n" +
+	"\n" +
+	"price_paid\x18\x04 \x01(\x04R\tpricePaid\x12#\n" +
+	"\rpayment_token\x18\x05 \x01(\tR\fpaymentToken\x1a^\n" +
+	"\x03Leg\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\x12\x1b\n" +
+	"\tfrom_code\x18\x02 \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\x03 \x01(\tR\x06toCode\"\x95\x01\n" +
+	"\x12JourneyReservation\x12\x1d\n" +
+	"\n" +
+	"journey_id\x18\x01 \x01(\x04R\tjourneyId\x12A\n" +
+	"\atickets\x18\x02 \x03(\v2'.ticket_reservation.ReservationResponseR\atickets\x12\x1d\n" +
+	"\n" +
+	"price_paid\x18\x03 \x01(\x04R\tpricePaid\"1\n" +
+	"\fWatchRequest\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\"\xd6\x01\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12!\n" +
+	"\fdeparture_id\x18\x02 \x01(\x04R\vdepartureId\x12\x1b\n" +
+	"\tticket_no\x18\x03 \x01(\x04R\bticketNo\x12\x18\n" +
+	"\asection\x18\x04 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x05 \x01(\rR\x04seat\x12*\n" +
+	"\x02at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x1f\n" +
+	"\vwaitlist_id\x18\a \x01(\x04R\n" +
+	"waitlistId\"\\\n" +
+	"\x14CreateAccountRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"A\n" +
+	"\rSignInRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"f\n" +
+	"\aAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x04R\taccountId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\"\xef\x01\n" +
+	"\x04Fare\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x04R\x05total\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x123\n" +
+	"\x05lines\x18\x04 \x03(\v2\x1d.ticket_reservation.Fare.LineR\x05lines\x12\x1b\n" +
+	"\tfare_type\x18\x05 \x01(\tR\bfareType\x1a@\n" +
+	"\x04Line\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x04R\x06amount\"\x97\x02\n" +
+	"\x06Refund\x12\x1b\n" +
+	"\trefund_id\x18\x01 \x01(\x04R\brefundId\x12\x1b\n" +
+	"\tticket_no\x18\x02 \x01(\x04R\bticketNo\x12\x1b\n" +
+	"\tfare_type\x18\x03 \x01(\tR\bfareType\x12\x1d\n" +
+	"\n" +
+	"price_paid\x18\x04 \x01(\x04R\tpricePaid\x12\x10\n" +
+	"\x03fee\x18\x05 \x01(\x04R\x03fee\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x04R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06policy\x18\b \x01(\tR\x06policy\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa7\x02\n" +
+	"\vDomainEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1b\n" +
+	"\tticket_no\x18\x03 \x01(\x04R\bticketNo\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12?\n" +
+	"\x06ticket\x18\x06 \x01(\v2'.ticket_reservation.ReservationResponseR\x06ticket\x122\n" +
+	"\x06refund\x18\a \x01(\v2\x1a.ticket_reservation.RefundR\x06refund\"i\n" +
+	"\x0eTicketDocument\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\"e\n" +
+	"\x15ValidateTicketRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1c\n" +
+	"\tinspector\x18\x02 \x01(\tR\tinspector\x12\x18\n" +
+	"\aconsume\x18\x03 \x01(\bR\aconsume\"\xff\x04\n" +
+	"\x10TicketValidation\x12C\n" +
+	"\x06result\x18\x01 \x01(\x0e2+.ticket_reservation.TicketValidation.ResultR\x06result\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
+	"\tticket_no\x18\x03 \x01(\x04R\bticketNo\x12!\n" +
+	"\fdeparture_id\x18\x04 \x01(\x04R\vdepartureId\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\rR\bposition\x12%\n" +
+	"\x0epassenger_name\x18\x06 \x01(\tR\rpassengerName\x12\x18\n" +
+	"\asection\x18\a \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\b \x01(\rR\x04seat\x129\n" +
+	"\n" +
+	"valid_from\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x12;\n" +
+	"\vvalid_until\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"validUntil\x123\n" +
+	"\aused_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x06usedAt\x12#\n" +
+	"\rinspection_id\x18\f \x01(\x04R\finspectionId\"\x88\x01\n" +
+	"\x06Result\x12\t\n" +
+	"\x05VALID\x10\x00\x12\r\n" +
+	"\tMALFORMED\x10\x01\x12\x11\n" +
+	"\rBAD_SIGNATURE\x10\x02\x12\x11\n" +
+	"\rNOT_YET_VALID\x10\x03\x12\v\n" +
+	"\aEXPIRED\x10\x04\x12\r\n" +
+	"\tCANCELLED\x10\x05\x12\x10\n" +
+	"\fSEAT_CHANGED\x10\x06\x12\x10\n" +
+	"\fALREADY_USED\x10\a\"G\n" +
+	"\fBoardRequest\x12\x1b\n" +
+	"\tticket_no\x18\x01 \x01(\x04R\bticketNo\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\rR\bposition\"4\n" +
+	"\x0fManifestRequest\x12!\n" +
+	"\fdeparture_id\x18\x01 \x01(\x04R\vdepartureId\"\xb2\x06\n" +
+	"\x10BoardingManifest\x12;\n" +
+	"\tdeparture\x18\x01 \x01(\v2\x1d.ticket_reservation.DepartureR\tdeparture\x12N\n" +
+	"\n" +
+	"passengers\x18\x02 \x03(\v2..ticket_reservation.BoardingManifest.PassengerR\n" +
+	"passengers\x12E\n" +
+	"\x11check_in_opens_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0echeckInOpensAt\x12G\n" +
+	"\x12check_in_closes_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0fcheckInClosesAt\x12F\n" +
+	"\x11boarding_opens_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0fboardingOpensAt\x1a\xf9\x02\n" +
+	"\tPassenger\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x12\n" +
+	"\x04seat\x18\x02 \x01(\rR\x04seat\x12\x1b\n" +
+	"\tticket_no\x18\x03 \x01(\x04R\bticketNo\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\rR\bposition\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12@\n" +
+	"\x05state\x18\x06 \x01(\x0e2*.ticket_reservation.BoardingManifest.StateR\x05state\x12>\n" +
+	"\rchecked_in_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcheckedInAt\x129\n" +
+	"\n" +
+	"boarded_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tboardedAt\x12\x1b\n" +
+	"\tfrom_code\x18\t \x01(\tR\bfromCode\x12\x17\n" +
+	"\ato_code\x18\n" +
+	" \x01(\tR\x06toCode\"=\n" +
+	"\x05State\x12\n" +
+	"\n" +
+	"\x06BOOKED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"CHECKED_IN\x10\x01\x12\v\n" +
+	"\aBOARDED\x10\x02\x12\v\n" +
+	"\aNO_SHOW\x10\x03\"s\n" +
+	"\x13JoinWaitlistRequest\x12@\n" +
+	"\arequest\x18\x01 \x01(\v2&.ticket_reservation.ReservationRequestR\arequest\x12\x1a\n" +
+	"\bpriority\x18\x02 \x01(\x05R\bpriority\"S\n" +
+	"\rWaitlistQuery\x12\x1f\n" +
+	"\vwaitlist_id\x18\x01 \x01(\x04R\n" +
+	"waitlistId\x12!\n" +
+	"\fdeparture_id\x18\x02 \x01(\x04R\vdepartureId\"{\n" +
+	"\x14ClaimWaitlistRequest\x12\x1f\n" +
+	"\vwaitlist_id\x18\x01 \x01(\x04R\n" +
+	"waitlistId\x12\x1d\n" +
+	"\n" +
+	"price_paid\x18\x02 \x01(\x04R\tpricePaid\x12#\n" +
+	"\rpayment_token\x18\x03 \x01(\tR\fpaymentToken\"\xb9\x04\n" +
+	"\rWaitlistEntry\x12\x1f\n" +
+	"\vwaitlist_id\x18\x01 \x01(\x04R\n" +
+	"waitlistId\x12!\n" +
+	"\fdeparture_id\x18\x02 \x01(\x04R\vdepartureId\x12@\n" +
+	"\x06status\x18\x03 \x01(\x0e2(.ticket_reservation.WaitlistEntry.StatusR\x06status\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12'\n" +
+	"\x0fpassenger_count\x18\x05 \x01(\rR\x0epassengerCount\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\rR\bposition\x127\n" +
+	"\tjoined_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt\x12B\n" +
+	"\x0fhold_expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rholdExpiresAt\x12\x1b\n" +
+	"\tticket_no\x18\t \x01(\x04R\bticketNo\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\n" +
+	" \x01(\x04R\taccountId\x12@\n" +
+	"\arequest\x18\v \x01(\v2&.ticket_reservation.ReservationRequestR\arequest\"F\n" +
+	"\x06Status\x12\v\n" +
+	"\aWAITING\x10\x00\x12\f\n" +
+	"\bPROMOTED\x10\x01\x12\n" +
+	"\n" +
+	"\x06BOOKED\x10\x02\x12\v\n" +
+	"\aEXPIRED\x10\x03\x12\b\n" +
+	"\x04LEFT\x10\x04\"N\n" +
+	"\x0fWaitlistEntries\x12;\n" +
+	"\aentries\x18\x01 \x03(\v2!.ticket_reservation.WaitlistEntryR\aentries\"\x0e\n" +
 	"\fEmptyRequest\"W\n" +
 	"\x12AllTicketsResponse\x12A\n" +
-	"\atickets\x18\x01 \x03(\v2'.ticket_reservation.ReservationResponseR\atickets2\x9a\x03\n" +
+	"\atickets\x18\x01 \x03(\v2'.ticket_reservation.ReservationResponseR\atickets2\xda\x12\n" +
 	"\x11TicketReservation\x12b\n" +
 	"\rReserveTicket\x12&.ticket_reservation.ReservationRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12a\n" +
 	"\fModifyTicket\x12&.ticket_reservation.ReservationRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12a\n" +
 	"\fCancelTicket\x12&.ticket_reservation.ReservationRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12[\n" +
-	"\rGetAllTickets\x12 .ticket_reservation.EmptyRequest\x1a&.ticket_reservation.AllTicketsResponse\"\x00B5Z3github.com/Akash-private/Cloudbees_code/proto;protob\x06proto3"
+	"\rGetAllTickets\x12 .ticket_reservation.EmptyRequest\x1a&.ticket_reservation.AllTicketsResponse\"\x00\x12^\n" +
+	"\tGetTicket\x12&.ticket_reservation.ReservationRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12G\n" +
+	"\bHoldSeat\x12\x1f.ticket_reservation.HoldRequest\x1a\x18.ticket_reservation.Hold\"\x00\x12\\\n" +
+	"\rSearchTickets\x12!.ticket_reservation.SearchRequest\x1a&.ticket_reservation.AllTicketsResponse\"\x00\x12O\n" +
+	"\n" +
+	"GetSeatMap\x12\".ticket_reservation.SeatMapRequest\x1a\x1b.ticket_reservation.SeatMap\"\x00\x12\\\n" +
+	"\x0eListDepartures\x12%.ticket_reservation.DeparturesRequest\x1a!.ticket_reservation.DepartureList\"\x00\x12N\n" +
+	"\vWatchEvents\x12 .ticket_reservation.WatchRequest\x1a\x19.ticket_reservation.Event\"\x000\x01\x12X\n" +
+	"\rCreateAccount\x12(.ticket_reservation.CreateAccountRequest\x1a\x1b.ticket_reservation.Account\"\x00\x12J\n" +
+	"\x06SignIn\x12!.ticket_reservation.SignInRequest\x1a\x1b.ticket_reservation.Account\"\x00\x12M\n" +
+	"\n" +
+	"GetAccount\x12 .ticket_reservation.EmptyRequest\x1a\x1b.ticket_reservation.Account\"\x00\x12O\n" +
+	"\tQuoteFare\x12&.ticket_reservation.ReservationRequest\x1a\x18.ticket_reservation.Fare\"\x00\x12a\n" +
+	"\x11GetTicketDocument\x12&.ticket_reservation.ReservationRequest\x1a\".ticket_reservation.TicketDocument\"\x00\x12c\n" +
+	"\x0eValidateTicket\x12).ticket_reservation.ValidateTicketRequest\x1a$.ticket_reservation.TicketValidation\"\x00\x12\\\n" +
+	"\aCheckIn\x12&.ticket_reservation.ReservationRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12T\n" +
+	"\x05Board\x12 .ticket_reservation.BoardRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12b\n" +
+	"\x13GetBoardingManifest\x12#.ticket_reservation.ManifestRequest\x1a$.ticket_reservation.BoardingManifest\"\x00\x12\\\n" +
+	"\fJoinWaitlist\x12'.ticket_reservation.JoinWaitlistRequest\x1a!.ticket_reservation.WaitlistEntry\"\x00\x12X\n" +
+	"\fListWaitlist\x12!.ticket_reservation.WaitlistQuery\x1a#.ticket_reservation.WaitlistEntries\"\x00\x12d\n" +
+	"\rClaimWaitlist\x12(.ticket_reservation.ClaimWaitlistRequest\x1a'.ticket_reservation.ReservationResponse\"\x00\x12W\n" +
+	"\rLeaveWaitlist\x12!.ticket_reservation.WaitlistQuery\x1a!.ticket_reservation.WaitlistEntry\"\x00\x12Y\n" +
+	"\x11QuoteCancellation\x12&.ticket_reservation.ReservationRequest\x1a\x1a.ticket_reservation.Refund\"\x00\x12U\n" +
+	"\fPlanJourneys\x12\".ticket_reservation.JourneyRequest\x1a\x1f.ticket_reservation.JourneyList\"\x00\x12i\n" +
+	"\x0eReserveJourney\x12-.ticket_reservation.JourneyReservationRequest\x1a&.ticket_reservation.JourneyReservation\"\x00B5Z3github.com/Akash-private/Cloudbees_code/proto;protob\x06proto3"
 
 var (
 	file_proto_ticket_reservation_proto_rawDescOnce sync.Once
@@ -418,31 +4311,174 @@ func file_proto_ticket_reservation_proto_rawDescGZIP() []byte {
 	return file_proto_ticket_reservation_proto_rawDescData
 }
 
-var file_proto_ticket_reservation_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_ticket_reservation_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_proto_ticket_reservation_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_proto_ticket_reservation_proto_goTypes = []any{
-	(*UserDetails)(nil),         // 0: ticket_reservation.user_details
-	(*ReservationRequest)(nil),  // 1: ticket_reservation.ReservationRequest
-	(*ReservationResponse)(nil), // 2: ticket_reservation.ReservationResponse
-	(*EmptyRequest)(nil),        // 3: ticket_reservation.EmptyRequest
-	(*AllTicketsResponse)(nil),  // 4: ticket_reservation.AllTicketsResponse
+	(SeatPreferences_Position)(0),         // 0: ticket_reservation.SeatPreferences.Position
+	(SeatPreferences_Facing)(0),           // 1: ticket_reservation.SeatPreferences.Facing
+	(SeatMap_State)(0),                    // 2: ticket_reservation.SeatMap.State
+	(JourneyRequest_Sort)(0),              // 3: ticket_reservation.JourneyRequest.Sort
+	(TicketValidation_Result)(0),          // 4: ticket_reservation.TicketValidation.Result
+	(BoardingManifest_State)(0),           // 5: ticket_reservation.BoardingManifest.State
+	(WaitlistEntry_Status)(0),             // 6: ticket_reservation.WaitlistEntry.Status
+	(*UserDetails)(nil),                   // 7: ticket_reservation.user_details
+	(*SeatPreferences)(nil),               // 8: ticket_reservation.SeatPreferences
+	(*UnmetPreference)(nil),               // 9: ticket_reservation.UnmetPreference
+	(*ReservationRequest)(nil),            // 10: ticket_reservation.ReservationRequest
+	(*ReservationResponse)(nil),           // 11: ticket_reservation.ReservationResponse
+	(*Payment)(nil),                       // 12: ticket_reservation.Payment
+	(*HoldRequest)(nil),                   // 13: ticket_reservation.HoldRequest
+	(*Hold)(nil),                          // 14: ticket_reservation.Hold
+	(*SearchRequest)(nil),                 // 15: ticket_reservation.SearchRequest
+	(*SeatMapRequest)(nil),                // 16: ticket_reservation.SeatMapRequest
+	(*SeatMap)(nil),                       // 17: ticket_reservation.SeatMap
+	(*DeparturesRequest)(nil),             // 18: ticket_reservation.DeparturesRequest
+	(*Departure)(nil),                     // 19: ticket_reservation.Departure
+	(*Stop)(nil),                          // 20: ticket_reservation.Stop
+	(*DepartureList)(nil),                 // 21: ticket_reservation.DepartureList
+	(*JourneyRequest)(nil),                // 22: ticket_reservation.JourneyRequest
+	(*Journey)(nil),                       // 23: ticket_reservation.Journey
+	(*JourneyList)(nil),                   // 24: ticket_reservation.JourneyList
+	(*JourneyReservationRequest)(nil),     // 25: ticket_reservation.JourneyReservationRequest
+	(*JourneyReservation)(nil),            // 26: ticket_reservation.JourneyReservation
+	(*WatchRequest)(nil),                  // 27: ticket_reservation.WatchRequest
+	(*Event)(nil),                         // 28: ticket_reservation.Event
+	(*CreateAccountRequest)(nil),          // 29: ticket_reservation.CreateAccountRequest
+	(*SignInRequest)(nil),                 // 30: ticket_reservation.SignInRequest
+	(*Account)(nil),                       // 31: ticket_reservation.Account
+	(*Fare)(nil),                          // 32: ticket_reservation.Fare
+	(*Refund)(nil),                        // 33: ticket_reservation.Refund
+	(*DomainEvent)(nil),                   // 34: ticket_reservation.DomainEvent
+	(*TicketDocument)(nil),                // 35: ticket_reservation.TicketDocument
+	(*ValidateTicketRequest)(nil),         // 36: ticket_reservation.ValidateTicketRequest
+	(*TicketValidation)(nil),              // 37: ticket_reservation.TicketValidation
+	(*BoardRequest)(nil),                  // 38: ticket_reservation.BoardRequest
+	(*ManifestRequest)(nil),               // 39: ticket_reservation.ManifestRequest
+	(*BoardingManifest)(nil),              // 40: ticket_reservation.BoardingManifest
+	(*JoinWaitlistRequest)(nil),           // 41: ticket_reservation.JoinWaitlistRequest
+	(*WaitlistQuery)(nil),                 // 42: ticket_reservation.WaitlistQuery
+	(*ClaimWaitlistRequest)(nil),          // 43: ticket_reservation.ClaimWaitlistRequest
+	(*WaitlistEntry)(nil),                 // 44: ticket_reservation.WaitlistEntry
+	(*WaitlistEntries)(nil),               // 45: ticket_reservation.WaitlistEntries
+	(*EmptyRequest)(nil),                  // 46: ticket_reservation.EmptyRequest
+	(*AllTicketsResponse)(nil),            // 47: ticket_reservation.AllTicketsResponse
+	(*SeatMap_Seat)(nil),                  // 48: ticket_reservation.SeatMap.Seat
+	(*JourneyReservationRequest_Leg)(nil), // 49: ticket_reservation.JourneyReservationRequest.Leg
+	(*Fare_Line)(nil),                     // 50: ticket_reservation.Fare.Line
+	(*BoardingManifest_Passenger)(nil),    // 51: ticket_reservation.BoardingManifest.Passenger
+	(*timestamppb.Timestamp)(nil),         // 52: google.protobuf.Timestamp
 }
 var file_proto_ticket_reservation_proto_depIdxs = []int32{
-	0, // 0: ticket_reservation.ReservationRequest.passengers:type_name -> ticket_reservation.user_details
-	0, // 1: ticket_reservation.ReservationResponse.passengers:type_name -> ticket_reservation.user_details
-	2, // 2: ticket_reservation.AllTicketsResponse.tickets:type_name -> ticket_reservation.ReservationResponse
-	1, // 3: ticket_reservation.TicketReservation.ReserveTicket:input_type -> ticket_reservation.ReservationRequest
-	1, // 4: ticket_reservation.TicketReservation.ModifyTicket:input_type -> ticket_reservation.ReservationRequest
-	1, // 5: ticket_reservation.TicketReservation.CancelTicket:input_type -> ticket_reservation.ReservationRequest
-	3, // 6: ticket_reservation.TicketReservation.GetAllTickets:input_type -> ticket_reservation.EmptyRequest
-	2, // 7: ticket_reservation.TicketReservation.ReserveTicket:output_type -> ticket_reservation.ReservationResponse
-	2, // 8: ticket_reservation.TicketReservation.ModifyTicket:output_type -> ticket_reservation.ReservationResponse
-	2, // 9: ticket_reservation.TicketReservation.CancelTicket:output_type -> ticket_reservation.ReservationResponse
-	4, // 10: ticket_reservation.TicketReservation.GetAllTickets:output_type -> ticket_reservation.AllTicketsResponse
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	8,  // 0: ticket_reservation.user_details.preferences:type_name -> ticket_reservation.SeatPreferences
+	0,  // 1: ticket_reservation.SeatPreferences.position:type_name -> ticket_reservation.SeatPreferences.Position
+	1,  // 2: ticket_reservation.SeatPreferences.facing:type_name -> ticket_reservation.SeatPreferences.Facing
+	7,  // 3: ticket_reservation.ReservationRequest.passengers:type_name -> ticket_reservation.user_details
+	7,  // 4: ticket_reservation.ReservationResponse.passengers:type_name -> ticket_reservation.user_details
+	33, // 5: ticket_reservation.ReservationResponse.refund:type_name -> ticket_reservation.Refund
+	12, // 6: ticket_reservation.ReservationResponse.payment:type_name -> ticket_reservation.Payment
+	9,  // 7: ticket_reservation.ReservationResponse.unmet_preferences:type_name -> ticket_reservation.UnmetPreference
+	52, // 8: ticket_reservation.Hold.expires_at:type_name -> google.protobuf.Timestamp
+	48, // 9: ticket_reservation.SeatMap.seats:type_name -> ticket_reservation.SeatMap.Seat
+	52, // 10: ticket_reservation.Departure.departs_at:type_name -> google.protobuf.Timestamp
+	52, // 11: ticket_reservation.Departure.arrives_at:type_name -> google.protobuf.Timestamp
+	20, // 12: ticket_reservation.Departure.stops:type_name -> ticket_reservation.Stop
+	52, // 13: ticket_reservation.Stop.arrives_at:type_name -> google.protobuf.Timestamp
+	52, // 14: ticket_reservation.Stop.departs_at:type_name -> google.protobuf.Timestamp
+	19, // 15: ticket_reservation.DepartureList.departures:type_name -> ticket_reservation.Departure
+	52, // 16: ticket_reservation.JourneyRequest.departs_after:type_name -> google.protobuf.Timestamp
+	3,  // 17: ticket_reservation.JourneyRequest.sort:type_name -> ticket_reservation.JourneyRequest.Sort
+	19, // 18: ticket_reservation.Journey.legs:type_name -> ticket_reservation.Departure
+	52, // 19: ticket_reservation.Journey.departs_at:type_name -> google.protobuf.Timestamp
+	52, // 20: ticket_reservation.Journey.arrives_at:type_name -> google.protobuf.Timestamp
+	23, // 21: ticket_reservation.JourneyList.journeys:type_name -> ticket_reservation.Journey
+	49, // 22: ticket_reservation.JourneyReservationRequest.legs:type_name -> ticket_reservation.JourneyReservationRequest.Leg
+	7,  // 23: ticket_reservation.JourneyReservationRequest.passengers:type_name -> ticket_reservation.user_details
+	11, // 24: ticket_reservation.JourneyReservation.tickets:type_name -> ticket_reservation.ReservationResponse
+	52, // 25: ticket_reservation.Event.at:type_name -> google.protobuf.Timestamp
+	50, // 26: ticket_reservation.Fare.lines:type_name -> ticket_reservation.Fare.Line
+	52, // 27: ticket_reservation.Refund.created_at:type_name -> google.protobuf.Timestamp
+	52, // 28: ticket_reservation.DomainEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	11, // 29: ticket_reservation.DomainEvent.ticket:type_name -> ticket_reservation.ReservationResponse
+	33, // 30: ticket_reservation.DomainEvent.refund:type_name -> ticket_reservation.Refund
+	4,  // 31: ticket_reservation.TicketValidation.result:type_name -> ticket_reservation.TicketValidation.Result
+	52, // 32: ticket_reservation.TicketValidation.valid_from:type_name -> google.protobuf.Timestamp
+	52, // 33: ticket_reservation.TicketValidation.valid_until:type_name -> google.protobuf.Timestamp
+	52, // 34: ticket_reservation.TicketValidation.used_at:type_name -> google.protobuf.Timestamp
+	19, // 35: ticket_reservation.BoardingManifest.departure:type_name -> ticket_reservation.Departure
+	51, // 36: ticket_reservation.BoardingManifest.passengers:type_name -> ticket_reservation.BoardingManifest.Passenger
+	52, // 37: ticket_reservation.BoardingManifest.check_in_opens_at:type_name -> google.protobuf.Timestamp
+	52, // 38: ticket_reservation.BoardingManifest.check_in_closes_at:type_name -> google.protobuf.Timestamp
+	52, // 39: ticket_reservation.BoardingManifest.boarding_opens_at:type_name -> google.protobuf.Timestamp
+	10, // 40: ticket_reservation.JoinWaitlistRequest.request:type_name -> ticket_reservation.ReservationRequest
+	6,  // 41: ticket_reservation.WaitlistEntry.status:type_name -> ticket_reservation.WaitlistEntry.Status
+	52, // 42: ticket_reservation.WaitlistEntry.joined_at:type_name -> google.protobuf.Timestamp
+	52, // 43: ticket_reservation.WaitlistEntry.hold_expires_at:type_name -> google.protobuf.Timestamp
+	10, // 44: ticket_reservation.WaitlistEntry.request:type_name -> ticket_reservation.ReservationRequest
+	44, // 45: ticket_reservation.WaitlistEntries.entries:type_name -> ticket_reservation.WaitlistEntry
+	11, // 46: ticket_reservation.AllTicketsResponse.tickets:type_name -> ticket_reservation.ReservationResponse
+	2,  // 47: ticket_reservation.SeatMap.Seat.state:type_name -> ticket_reservation.SeatMap.State
+	0,  // 48: ticket_reservation.SeatMap.Seat.position:type_name -> ticket_reservation.SeatPreferences.Position
+	1,  // 49: ticket_reservation.SeatMap.Seat.facing:type_name -> ticket_reservation.SeatPreferences.Facing
+	5,  // 50: ticket_reservation.BoardingManifest.Passenger.state:type_name -> ticket_reservation.BoardingManifest.State
+	52, // 51: ticket_reservation.BoardingManifest.Passenger.checked_in_at:type_name -> google.protobuf.Timestamp
+	52, // 52: ticket_reservation.BoardingManifest.Passenger.boarded_at:type_name -> google.protobuf.Timestamp
+	10, // 53: ticket_reservation.TicketReservation.ReserveTicket:input_type -> ticket_reservation.ReservationRequest
+	10, // 54: ticket_reservation.TicketReservation.ModifyTicket:input_type -> ticket_reservation.ReservationRequest
+	10, // 55: ticket_reservation.TicketReservation.CancelTicket:input_type -> ticket_reservation.ReservationRequest
+	46, // 56: ticket_reservation.TicketReservation.GetAllTickets:input_type -> ticket_reservation.EmptyRequest
+	10, // 57: ticket_reservation.TicketReservation.GetTicket:input_type -> ticket_reservation.ReservationRequest
+	13, // 58: ticket_reservation.TicketReservation.HoldSeat:input_type -> ticket_reservation.HoldRequest
+	15, // 59: ticket_reservation.TicketReservation.SearchTickets:input_type -> ticket_reservation.SearchRequest
+	16, // 60: ticket_reservation.TicketReservation.GetSeatMap:input_type -> ticket_reservation.SeatMapRequest
+	18, // 61: ticket_reservation.TicketReservation.ListDepartures:input_type -> ticket_reservation.DeparturesRequest
+	27, // 62: ticket_reservation.TicketReservation.WatchEvents:input_type -> ticket_reservation.WatchRequest
+	29, // 63: ticket_reservation.TicketReservation.CreateAccount:input_type -> ticket_reservation.CreateAccountRequest
+	30, // 64: ticket_reservation.TicketReservation.SignIn:input_type -> ticket_reservation.SignInRequest
+	46, // 65: ticket_reservation.TicketReservation.GetAccount:input_type -> ticket_reservation.EmptyRequest
+	10, // 66: ticket_reservation.TicketReservation.QuoteFare:input_type -> ticket_reservation.ReservationRequest
+	10, // 67: ticket_reservation.TicketReservation.GetTicketDocument:input_type -> ticket_reservation.ReservationRequest
+	36, // 68: ticket_reservation.TicketReservation.ValidateTicket:input_type -> ticket_reservation.ValidateTicketRequest
+	10, // 69: ticket_reservation.TicketReservation.CheckIn:input_type -> ticket_reservation.ReservationRequest
+	38, // 70: ticket_reservation.TicketReservation.Board:input_type -> ticket_reservation.BoardRequest
+	39, // 71: ticket_reservation.TicketReservation.GetBoardingManifest:input_type -> ticket_reservation.ManifestRequest
+	41, // 72: ticket_reservation.TicketReservation.JoinWaitlist:input_type -> ticket_reservation.JoinWaitlistRequest
+	42, // 73: ticket_reservation.TicketReservation.ListWaitlist:input_type -> ticket_reservation.WaitlistQuery
+	43, // 74: ticket_reservation.TicketReservation.ClaimWaitlist:input_type -> ticket_reservation.ClaimWaitlistRequest
+	42, // 75: ticket_reservation.TicketReservation.LeaveWaitlist:input_type -> ticket_reservation.WaitlistQuery
+	10, // 76: ticket_reservation.TicketReservation.QuoteCancellation:input_type -> ticket_reservation.ReservationRequest
+	22, // 77: ticket_reservation.TicketReservation.PlanJourneys:input_type -> ticket_reservation.JourneyRequest
+	25, // 78: ticket_reservation.TicketReservation.ReserveJourney:input_type -> ticket_reservation.JourneyReservationRequest
+	11, // 79: ticket_reservation.TicketReservation.ReserveTicket:output_type -> ticket_reservation.ReservationResponse
+	11, // 80: ticket_reservation.TicketReservation.ModifyTicket:output_type -> ticket_reservation.ReservationResponse
+	11, // 81: ticket_reservation.TicketReservation.CancelTicket:output_type -> ticket_reservation.ReservationResponse
+	47, // 82: ticket_reservation.TicketReservation.GetAllTickets:output_type -> ticket_reservation.AllTicketsResponse
+	11, // 83: ticket_reservation.TicketReservation.GetTicket:output_type -> ticket_reservation.ReservationResponse
+	14, // 84: ticket_reservation.TicketReservation.HoldSeat:output_type -> ticket_reservation.Hold
+	47, // 85: ticket_reservation.TicketReservation.SearchTickets:output_type -> ticket_reservation.AllTicketsResponse
+	17, // 86: ticket_reservation.TicketReservation.GetSeatMap:output_type -> ticket_reservation.SeatMap
+	21, // 87: ticket_reservation.TicketReservation.ListDepartures:output_type -> ticket_reservation.DepartureList
+	28, // 88: ticket_reservation.TicketReservation.WatchEvents:output_type -> ticket_reservation.Event
+	31, // 89: ticket_reservation.TicketReservation.CreateAccount:output_type -> ticket_reservation.Account
+	31, // 90: ticket_reservation.TicketReservation.SignIn:output_type -> ticket_reservation.Account
+	31, // 91: ticket_reservation.TicketReservation.GetAccount:output_type -> ticket_reservation.Account
+	32, // 92: ticket_reservation.TicketReservation.QuoteFare:output_type -> ticket_reservation.Fare
+	35, // 93: ticket_reservation.TicketReservation.GetTicketDocument:output_type -> ticket_reservation.TicketDocument
+	37, // 94: ticket_reservation.TicketReservation.ValidateTicket:output_type -> ticket_reservation.TicketValidation
+	11, // 95: ticket_reservation.TicketReservation.CheckIn:output_type -> ticket_reservation.ReservationResponse
+	11, // 96: ticket_reservation.TicketReservation.Board:output_type -> ticket_reservation.ReservationResponse
+	40, // 97: ticket_reservation.TicketReservation.GetBoardingManifest:output_type -> ticket_reservation.BoardingManifest
+	44, // 98: ticket_reservation.TicketReservation.JoinWaitlist:output_type -> ticket_reservation.WaitlistEntry
+	45, // 99: ticket_reservation.TicketReservation.ListWaitlist:output_type -> ticket_reservation.WaitlistEntries
+	11, // 100: ticket_reservation.TicketReservation.ClaimWaitlist:output_type -> ticket_reservation.ReservationResponse
+	44, // 101: ticket_reservation.TicketReservation.LeaveWaitlist:output_type -> ticket_reservation.WaitlistEntry
+	33, // 102: ticket_reservation.TicketReservation.QuoteCancellation:output_type -> ticket_reservation.Refund
+	24, // 103: ticket_reservation.TicketReservation.PlanJourneys:output_type -> ticket_reservation.JourneyList
+	26, // 104: ticket_reservation.TicketReservation.ReserveJourney:output_type -> ticket_reservation.JourneyReservation
+	79, // [79:105] is the sub-list for method output_type
+	53, // [53:79] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
 }
 
 func init() { file_proto_ticket_reservation_proto_init() }
@@ -450,19 +4486,21 @@ func file_proto_ticket_reservation_proto_init() {
 	if File_proto_ticket_reservation_proto != nil {
 		return
 	}
-	file_proto_ticket_reservation_proto_msgTypes[1].OneofWrappers = []any{}
+	file_proto_ticket_reservation_proto_msgTypes[3].OneofWrappers = []any{}
+	file_proto_ticket_reservation_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_ticket_reservation_proto_rawDesc), len(file_proto_ticket_reservation_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      7,
+			NumMessages:   45,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_ticket_reservation_proto_goTypes,
 		DependencyIndexes: file_proto_ticket_reservation_proto_depIdxs,
+		EnumInfos:         file_proto_ticket_reservation_proto_enumTypes,
 		MessageInfos:      file_proto_ticket_reservation_proto_msgTypes,
 	}.Build()
 	File_proto_ticket_reservation_proto = out.File
@@ -19,10 +19,32 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TicketReservation_ReserveTicket_FullMethodName = "/ticket_reservation.TicketReservation/ReserveTicket"
-	TicketReservation_ModifyTicket_FullMethodName  = "/ticket_reservation.TicketReservation/ModifyTicket"
-	TicketReservation_CancelTicket_FullMethodName  = "/ticket_reservation.TicketReservation/CancelTicket"
-	TicketReservation_GetAllTickets_FullMethodName = "/ticket_reservation.TicketReservation/GetAllTickets"
+	TicketReservation_ReserveTicket_FullMethodName       = "/ticket_reservation.TicketReservation/ReserveTicket"
+	TicketReservation_ModifyTicket_FullMethodName        = "/ticket_reservation.TicketReservation/ModifyTicket"
+	TicketReservation_CancelTicket_FullMethodName        = "/ticket_reservation.TicketReservation/CancelTicket"
+	TicketReservation_GetAllTickets_FullMethodName       = "/ticket_reservation.TicketReservation/GetAllTickets"
+	TicketReservation_GetTicket_FullMethodName           = "/ticket_reservation.TicketReservation/GetTicket"
+	TicketReservation_HoldSeat_FullMethodName            = "/ticket_reservation.TicketReservation/HoldSeat"
+	TicketReservation_SearchTickets_FullMethodName       = "/ticket_reservation.TicketReservation/SearchTickets"
+	TicketReservation_GetSeatMap_FullMethodName          = "/ticket_reservation.TicketReservation/GetSeatMap"
+	TicketReservation_ListDepartures_FullMethodName      = "/ticket_reservation.TicketReservation/ListDepartures"
+	TicketReservation_WatchEvents_FullMethodName         = "/ticket_reservation.TicketReservation/WatchEvents"
+	TicketReservation_CreateAccount_FullMethodName       = "/ticket_reservation.TicketReservation/CreateAccount"
+	TicketReservation_SignIn_FullMethodName              = "/ticket_reservation.TicketReservation/SignIn"
+	TicketReservation_GetAccount_FullMethodName          = "/ticket_reservation.TicketReservation/GetAccount"
+	TicketReservation_QuoteFare_FullMethodName           = "/ticket_reservation.TicketReservation/QuoteFare"
+	TicketReservation_GetTicketDocument_FullMethodName   = "/ticket_reservation.TicketReservation/GetTicketDocument"
+	TicketReservation_ValidateTicket_FullMethodName      = "/ticket_reservation.TicketReservation/ValidateTicket"
+	TicketReservation_CheckIn_FullMethodName             = "/ticket_reservation.TicketReservation/CheckIn"
+	TicketReservation_Board_FullMethodName               = "/ticket_reservation.TicketReservation/Board"
+	TicketReservation_GetBoardingManifest_FullMethodName = "/ticket_reservation.TicketReservation/GetBoardingManifest"
+	TicketReservation_JoinWaitlist_FullMethodName        = "/ticket_reservation.TicketReservation/JoinWaitlist"
+	TicketReservation_ListWaitlist_FullMethodName        = "/ticket_reservation.TicketReservation/ListWaitlist"
+	TicketReservation_ClaimWaitlist_FullMethodName       = "/ticket_reservation.TicketReservation/ClaimWaitlist"
+	TicketReservation_LeaveWaitlist_FullMethodName       = "/ticket_reservation.TicketReservation/LeaveWaitlist"
+	TicketReservation_QuoteCancellation_FullMethodName   = "/ticket_reservation.TicketReservation/QuoteCancellation"
+	TicketReservation_PlanJourneys_FullMethodName        = "/ticket_reservation.TicketReservation/PlanJourneys"
+	TicketReservation_ReserveJourney_FullMethodName      = "/ticket_reservation.TicketReservation/ReserveJourney"
 )
 
 // TicketReservationClient is the client API for TicketReservation service.
@@ -36,6 +58,71 @@ type TicketReservationClient interface {
 	ModifyTicket(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
 	CancelTicket(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
 	GetAllTickets(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*AllTicketsResponse, error)
+	// Returns a single ticket by ticket_no. NOT_FOUND if it does not exist.
+	GetTicket(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	// Holds a seat for a short time so it can be booked with ReserveTicket by
+	// passing the returned hold_id. Picks the first free seat if none is given.
+	HoldSeat(ctx context.Context, in *HoldRequest, opts ...grpc.CallOption) (*Hold, error)
+	// Returns tickets matching every non-empty field of the request.
+	SearchTickets(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*AllTicketsResponse, error)
+	// Returns the state of every seat, optionally limited to one section.
+	GetSeatMap(ctx context.Context, in *SeatMapRequest, opts ...grpc.CallOption) (*SeatMap, error)
+	// Lists upcoming departures, optionally limited to one route.
+	ListDepartures(ctx context.Context, in *DeparturesRequest, opts ...grpc.CallOption) (*DepartureList, error)
+	// Streams booking and seat events as they happen until the client hangs up.
+	WatchEvents(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// Registers a customer account. ALREADY_EXISTS if the email is taken.
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// Checks an email and password. UNAUTHENTICATED if they do not match.
+	SignIn(ctx context.Context, in *SignInRequest, opts ...grpc.CallOption) (*Account, error)
+	// Returns the caller's account with its current role. UNAUTHENTICATED for
+	// callers without an account.
+	GetAccount(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*Account, error)
+	// Prices a booking without making it. The fare depends only on the
+	// departure, the number of passengers and the sections they chose, so the
+	// total can be passed back as price_paid to ReserveTicket to confirm it.
+	QuoteFare(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Fare, error)
+	// Renders a printable PDF ticket with one page per passenger. Each page
+	// carries a QR code with the passenger's signed ticket token.
+	GetTicketDocument(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*TicketDocument, error)
+	// Checks a ticket token: signature, validity window, whether the ticket
+	// still exists with the same seat and whether it was already used. Every
+	// call is recorded as an inspection. Invalid tickets are reported in the
+	// result, not as errors.
+	ValidateTicket(ctx context.Context, in *ValidateTicketRequest, opts ...grpc.CallOption) (*TicketValidation, error)
+	// Checks in every passenger of a ticket. Check-in opens CHECKIN_OPENS and
+	// closes CHECKIN_CLOSES before departure; outside that window the call
+	// fails with FAILED_PRECONDITION. Customers can only check in their own
+	// tickets.
+	CheckIn(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	// Boards one checked-in passenger, from BOARDING_OPENS before departure
+	// until departure. Staff only.
+	Board(ctx context.Context, in *BoardRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	// Lists the passengers of a departure by coach and seat with their
+	// check-in and boarding state. Staff only.
+	GetBoardingManifest(ctx context.Context, in *ManifestRequest, opts ...grpc.CallOption) (*BoardingManifest, error)
+	// Queues a booking for a departure that has too few free seats. When
+	// seats free up, the first entries in line that fit are promoted: their
+	// seats are held for WAITLIST_CLAIM_TTL and they are notified. Only staff
+	// can set a priority.
+	JoinWaitlist(ctx context.Context, in *JoinWaitlistRequest, opts ...grpc.CallOption) (*WaitlistEntry, error)
+	// Lists the caller's waitlist entries, or for staff those of a departure.
+	ListWaitlist(ctx context.Context, in *WaitlistQuery, opts ...grpc.CallOption) (*WaitlistEntries, error)
+	// Books the seats held for a promoted entry. FAILED_PRECONDITION if the
+	// entry has not been promoted or the promotion has expired.
+	ClaimWaitlist(ctx context.Context, in *ClaimWaitlistRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	// Takes an entry off the waitlist, releasing any seats held for it.
+	LeaveWaitlist(ctx context.Context, in *WaitlistQuery, opts ...grpc.CallOption) (*WaitlistEntry, error)
+	// Shows what cancelling a ticket now would refund under the cancellation
+	// policy of its fare type. CancelTicket refunds the same amount.
+	QuoteCancellation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Refund, error)
+	// Finds itineraries from one station to another, changing trains where
+	// no departure runs all the way. Changes allow at least the station's
+	// transfer time (TRANSFER_TIMES, else MIN_TRANSFER).
+	PlanJourneys(ctx context.Context, in *JourneyRequest, opts ...grpc.CallOption) (*JourneyList, error)
+	// Books every leg of an itinerary as one ticket per leg, all of them or
+	// none. Seats are allocated on each leg; passengers cannot name seats.
+	ReserveJourney(ctx context.Context, in *JourneyReservationRequest, opts ...grpc.CallOption) (*JourneyReservation, error)
 }
 
 type ticketReservationClient struct {
@@ -86,6 +173,235 @@ func (c *ticketReservationClient) GetAllTickets(ctx context.Context, in *EmptyRe
 	return out, nil
 }
 
+func (c *ticketReservationClient) GetTicket(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, TicketReservation_GetTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) HoldSeat(ctx context.Context, in *HoldRequest, opts ...grpc.CallOption) (*Hold, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Hold)
+	err := c.cc.Invoke(ctx, TicketReservation_HoldSeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) SearchTickets(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*AllTicketsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AllTicketsResponse)
+	err := c.cc.Invoke(ctx, TicketReservation_SearchTickets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) GetSeatMap(ctx context.Context, in *SeatMapRequest, opts ...grpc.CallOption) (*SeatMap, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeatMap)
+	err := c.cc.Invoke(ctx, TicketReservation_GetSeatMap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) ListDepartures(ctx context.Context, in *DeparturesRequest, opts ...grpc.CallOption) (*DepartureList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DepartureList)
+	err := c.cc.Invoke(ctx, TicketReservation_ListDepartures_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) WatchEvents(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TicketReservation_ServiceDesc.Streams[0], TicketReservation_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketReservation_WatchEventsClient = grpc.ServerStreamingClient[Event]
+
+func (c *ticketReservationClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TicketReservation_CreateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) SignIn(ctx context.Context, in *SignInRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TicketReservation_SignIn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) GetAccount(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, TicketReservation_GetAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) QuoteFare(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Fare, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Fare)
+	err := c.cc.Invoke(ctx, TicketReservation_QuoteFare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) GetTicketDocument(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*TicketDocument, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TicketDocument)
+	err := c.cc.Invoke(ctx, TicketReservation_GetTicketDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) ValidateTicket(ctx context.Context, in *ValidateTicketRequest, opts ...grpc.CallOption) (*TicketValidation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TicketValidation)
+	err := c.cc.Invoke(ctx, TicketReservation_ValidateTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) CheckIn(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, TicketReservation_CheckIn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) Board(ctx context.Context, in *BoardRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, TicketReservation_Board_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) GetBoardingManifest(ctx context.Context, in *ManifestRequest, opts ...grpc.CallOption) (*BoardingManifest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BoardingManifest)
+	err := c.cc.Invoke(ctx, TicketReservation_GetBoardingManifest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) JoinWaitlist(ctx context.Context, in *JoinWaitlistRequest, opts ...grpc.CallOption) (*WaitlistEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WaitlistEntry)
+	err := c.cc.Invoke(ctx, TicketReservation_JoinWaitlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) ListWaitlist(ctx context.Context, in *WaitlistQuery, opts ...grpc.CallOption) (*WaitlistEntries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WaitlistEntries)
+	err := c.cc.Invoke(ctx, TicketReservation_ListWaitlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) ClaimWaitlist(ctx context.Context, in *ClaimWaitlistRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, TicketReservation_ClaimWaitlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) LeaveWaitlist(ctx context.Context, in *WaitlistQuery, opts ...grpc.CallOption) (*WaitlistEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WaitlistEntry)
+	err := c.cc.Invoke(ctx, TicketReservation_LeaveWaitlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) QuoteCancellation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Refund, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Refund)
+	err := c.cc.Invoke(ctx, TicketReservation_QuoteCancellation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) PlanJourneys(ctx context.Context, in *JourneyRequest, opts ...grpc.CallOption) (*JourneyList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JourneyList)
+	err := c.cc.Invoke(ctx, TicketReservation_PlanJourneys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketReservationClient) ReserveJourney(ctx context.Context, in *JourneyReservationRequest, opts ...grpc.CallOption) (*JourneyReservation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JourneyReservation)
+	err := c.cc.Invoke(ctx, TicketReservation_ReserveJourney_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TicketReservationServer is the server API for TicketReservation service.
 // All implementations must embed UnimplementedTicketReservationServer
 // for forward compatibility.
@@ -97,6 +413,71 @@ type TicketReservationServer interface {
 	ModifyTicket(context.Context, *ReservationRequest) (*ReservationResponse, error)
 	CancelTicket(context.Context, *ReservationRequest) (*ReservationResponse, error)
 	GetAllTickets(context.Context, *EmptyRequest) (*AllTicketsResponse, error)
+	// Returns a single ticket by ticket_no. NOT_FOUND if it does not exist.
+	GetTicket(context.Context, *ReservationRequest) (*ReservationResponse, error)
+	// Holds a seat for a short time so it can be booked with ReserveTicket by
+	// passing the returned hold_id. Picks the first free seat if none is given.
+	HoldSeat(context.Context, *HoldRequest) (*Hold, error)
+	// Returns tickets matching every non-empty field of the request.
+	SearchTickets(context.Context, *SearchRequest) (*AllTicketsResponse, error)
+	// Returns the state of every seat, optionally limited to one section.
+	GetSeatMap(context.Context, *SeatMapRequest) (*SeatMap, error)
+	// Lists upcoming departures, optionally limited to one route.
+	ListDepartures(context.Context, *DeparturesRequest) (*DepartureList, error)
+	// Streams booking and seat events as they happen until the client hangs up.
+	WatchEvents(*WatchRequest, grpc.ServerStreamingServer[Event]) error
+	// Registers a customer account. ALREADY_EXISTS if the email is taken.
+	CreateAccount(context.Context, *CreateAccountRequest) (*Account, error)
+	// Checks an email and password. UNAUTHENTICATED if they do not match.
+	SignIn(context.Context, *SignInRequest) (*Account, error)
+	// Returns the caller's account with its current role. UNAUTHENTICATED for
+	// callers without an account.
+	GetAccount(context.Context, *EmptyRequest) (*Account, error)
+	// Prices a booking without making it. The fare depends only on the
+	// departure, the number of passengers and the sections they chose, so the
+	// total can be passed back as price_paid to ReserveTicket to confirm it.
+	QuoteFare(context.Context, *ReservationRequest) (*Fare, error)
+	// Renders a printable PDF ticket with one page per passenger. Each page
+	// carries a QR code with the passenger's signed ticket token.
+	GetTicketDocument(context.Context, *ReservationRequest) (*TicketDocument, error)
+	// Checks a ticket token: signature, validity window, whether the ticket
+	// still exists with the same seat and whether it was already used. Every
+	// call is recorded as an inspection. Invalid tickets are reported in the
+	// result, not as errors.
+	ValidateTicket(context.Context, *ValidateTicketRequest) (*TicketValidation, error)
+	// Checks in every passenger of a ticket. Check-in opens CHECKIN_OPENS and
+	// closes CHECKIN_CLOSES before departure; outside that window the call
+	// fails with FAILED_PRECONDITION. Customers can only check in their own
+	// tickets.
+	CheckIn(context.Context, *ReservationRequest) (*ReservationResponse, error)
+	// Boards one checked-in passenger, from BOARDING_OPENS before departure
+	// until departure. Staff only.
+	Board(context.Context, *BoardRequest) (*ReservationResponse, error)
+	// Lists the passengers of a departure by coach and seat with their
+	// check-in and boarding state. Staff only.
+	GetBoardingManifest(context.Context, *ManifestRequest) (*BoardingManifest, error)
+	// Queues a booking for a departure that has too few free seats. When
+	// seats free up, the first entries in line that fit are promoted: their
+	// seats are held for WAITLIST_CLAIM_TTL and they are notified. Only staff
+	// can set a priority.
+	JoinWaitlist(context.Context, *JoinWaitlistRequest) (*WaitlistEntry, error)
+	// Lists the caller's waitlist entries, or for staff those of a departure.
+	ListWaitlist(context.Context, *WaitlistQuery) (*WaitlistEntries, error)
+	// Books the seats held for a promoted entry. FAILED_PRECONDITION if the
+	// entry has not been promoted or the promotion has expired.
+	ClaimWaitlist(context.Context, *ClaimWaitlistRequest) (*ReservationResponse, error)
+	// Takes an entry off the waitlist, releasing any seats held for it.
+	LeaveWaitlist(context.Context, *WaitlistQuery) (*WaitlistEntry, error)
+	// Shows what cancelling a ticket now would refund under the cancellation
+	// policy of its fare type. CancelTicket refunds the same amount.
+	QuoteCancellation(context.Context, *ReservationRequest) (*Refund, error)
+	// Finds itineraries from one station to another, changing trains where
+	// no departure runs all the way. Changes allow at least the station's
+	// transfer time (TRANSFER_TIMES, else MIN_TRANSFER).
+	PlanJourneys(context.Context, *JourneyRequest) (*JourneyList, error)
+	// Books every leg of an itinerary as one ticket per leg, all of them or
+	// none. Seats are allocated on each leg; passengers cannot name seats.
+	ReserveJourney(context.Context, *JourneyReservationRequest) (*JourneyReservation, error)
 	mustEmbedUnimplementedTicketReservationServer()
 }
 
@@ -119,6 +500,72 @@ func (UnimplementedTicketReservationServer) CancelTicket(context.Context, *Reser
 func (UnimplementedTicketReservationServer) GetAllTickets(context.Context, *EmptyRequest) (*AllTicketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllTickets not implemented")
 }
+func (UnimplementedTicketReservationServer) GetTicket(context.Context, *ReservationRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicket not implemented")
+}
+func (UnimplementedTicketReservationServer) HoldSeat(context.Context, *HoldRequest) (*Hold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HoldSeat not implemented")
+}
+func (UnimplementedTicketReservationServer) SearchTickets(context.Context, *SearchRequest) (*AllTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTickets not implemented")
+}
+func (UnimplementedTicketReservationServer) GetSeatMap(context.Context, *SeatMapRequest) (*SeatMap, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeatMap not implemented")
+}
+func (UnimplementedTicketReservationServer) ListDepartures(context.Context, *DeparturesRequest) (*DepartureList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDepartures not implemented")
+}
+func (UnimplementedTicketReservationServer) WatchEvents(*WatchRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedTicketReservationServer) CreateAccount(context.Context, *CreateAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedTicketReservationServer) SignIn(context.Context, *SignInRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignIn not implemented")
+}
+func (UnimplementedTicketReservationServer) GetAccount(context.Context, *EmptyRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedTicketReservationServer) QuoteFare(context.Context, *ReservationRequest) (*Fare, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteFare not implemented")
+}
+func (UnimplementedTicketReservationServer) GetTicketDocument(context.Context, *ReservationRequest) (*TicketDocument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicketDocument not implemented")
+}
+func (UnimplementedTicketReservationServer) ValidateTicket(context.Context, *ValidateTicketRequest) (*TicketValidation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateTicket not implemented")
+}
+func (UnimplementedTicketReservationServer) CheckIn(context.Context, *ReservationRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckIn not implemented")
+}
+func (UnimplementedTicketReservationServer) Board(context.Context, *BoardRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Board not implemented")
+}
+func (UnimplementedTicketReservationServer) GetBoardingManifest(context.Context, *ManifestRequest) (*BoardingManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBoardingManifest not implemented")
+}
+func (UnimplementedTicketReservationServer) JoinWaitlist(context.Context, *JoinWaitlistRequest) (*WaitlistEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinWaitlist not implemented")
+}
+func (UnimplementedTicketReservationServer) ListWaitlist(context.Context, *WaitlistQuery) (*WaitlistEntries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWaitlist not implemented")
+}
+func (UnimplementedTicketReservationServer) ClaimWaitlist(context.Context, *ClaimWaitlistRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimWaitlist not implemented")
+}
+func (UnimplementedTicketReservationServer) LeaveWaitlist(context.Context, *WaitlistQuery) (*WaitlistEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveWaitlist not implemented")
+}
+func (UnimplementedTicketReservationServer) QuoteCancellation(context.Context, *ReservationRequest) (*Refund, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteCancellation not implemented")
+}
+func (UnimplementedTicketReservationServer) PlanJourneys(context.Context, *JourneyRequest) (*JourneyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanJourneys not implemented")
+}
+func (UnimplementedTicketReservationServer) ReserveJourney(context.Context, *JourneyReservationRequest) (*JourneyReservation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveJourney not implemented")
+}
 func (UnimplementedTicketReservationServer) mustEmbedUnimplementedTicketReservationServer() {}
 func (UnimplementedTicketReservationServer) testEmbeddedByValue()                           {}
 
@@ -212,30 +659,509 @@ func _TicketReservation_GetAllTickets_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
-// TicketReservation_ServiceDesc is the grpc.ServiceDesc for TicketReservation service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var TicketReservation_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "ticket_reservation.TicketReservation",
-	HandlerType: (*TicketReservationServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "ReserveTicket",
-			Handler:    _TicketReservation_ReserveTicket_Handler,
-		},
-		{
-			MethodName: "ModifyTicket",
-			Handler:    _TicketReservation_ModifyTicket_Handler,
-		},
-		{
-			MethodName: "CancelTicket",
-			Handler:    _TicketReservation_CancelTicket_Handler,
-		},
-		{
-			MethodName: "GetAllTickets",
-			Handler:    _TicketReservation_GetAllTickets_Handler,
+func _TicketReservation_GetTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).GetTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_GetTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).GetTicket(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_HoldSeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).HoldSeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_HoldSeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).HoldSeat(ctx, req.(*HoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_SearchTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).SearchTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_SearchTickets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).SearchTickets(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_GetSeatMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeatMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).GetSeatMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_GetSeatMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).GetSeatMap(ctx, req.(*SeatMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_ListDepartures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeparturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).ListDepartures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_ListDepartures_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).ListDepartures(ctx, req.(*DeparturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TicketReservationServer).WatchEvents(m, &grpc.GenericServerStream[WatchRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketReservation_WatchEventsServer = grpc.ServerStreamingServer[Event]
+
+func _TicketReservation_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_SignIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignInRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).SignIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_SignIn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).SignIn(ctx, req.(*SignInRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).GetAccount(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_QuoteFare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).QuoteFare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_QuoteFare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).QuoteFare(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_GetTicketDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).GetTicketDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_GetTicketDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).GetTicketDocument(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_ValidateTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).ValidateTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_ValidateTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).ValidateTicket(ctx, req.(*ValidateTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_CheckIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).CheckIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_CheckIn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).CheckIn(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_Board_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).Board(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_Board_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).Board(ctx, req.(*BoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_GetBoardingManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).GetBoardingManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_GetBoardingManifest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).GetBoardingManifest(ctx, req.(*ManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_JoinWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinWaitlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).JoinWaitlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_JoinWaitlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).JoinWaitlist(ctx, req.(*JoinWaitlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_ListWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitlistQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).ListWaitlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_ListWaitlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).ListWaitlist(ctx, req.(*WaitlistQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_ClaimWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimWaitlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).ClaimWaitlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_ClaimWaitlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).ClaimWaitlist(ctx, req.(*ClaimWaitlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_LeaveWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitlistQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).LeaveWaitlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_LeaveWaitlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).LeaveWaitlist(ctx, req.(*WaitlistQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_QuoteCancellation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).QuoteCancellation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_QuoteCancellation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).QuoteCancellation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_PlanJourneys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JourneyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).PlanJourneys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_PlanJourneys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).PlanJourneys(ctx, req.(*JourneyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketReservation_ReserveJourney_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JourneyReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketReservationServer).ReserveJourney(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketReservation_ReserveJourney_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketReservationServer).ReserveJourney(ctx, req.(*JourneyReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TicketReservation_ServiceDesc is the grpc.ServiceDesc for TicketReservation service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TicketReservation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ticket_reservation.TicketReservation",
+	HandlerType: (*TicketReservationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReserveTicket",
+			Handler:    _TicketReservation_ReserveTicket_Handler,
+		},
+		{
+			MethodName: "ModifyTicket",
+			Handler:    _TicketReservation_ModifyTicket_Handler,
+		},
+		{
+			MethodName: "CancelTicket",
+			Handler:    _TicketReservation_CancelTicket_Handler,
+		},
+		{
+			MethodName: "GetAllTickets",
+			Handler:    _TicketReservation_GetAllTickets_Handler,
+		},
+		{
+			MethodName: "GetTicket",
+			Handler:    _TicketReservation_GetTicket_Handler,
+		},
+		{
+			MethodName: "HoldSeat",
+			Handler:    _TicketReservation_HoldSeat_Handler,
+		},
+		{
+			MethodName: "SearchTickets",
+			Handler:    _TicketReservation_SearchTickets_Handler,
+		},
+		{
+			MethodName: "GetSeatMap",
+			Handler:    _TicketReservation_GetSeatMap_Handler,
+		},
+		{
+			MethodName: "ListDepartures",
+			Handler:    _TicketReservation_ListDepartures_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _TicketReservation_CreateAccount_Handler,
+		},
+		{
+			MethodName: "SignIn",
+			Handler:    _TicketReservation_SignIn_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _TicketReservation_GetAccount_Handler,
+		},
+		{
+			MethodName: "QuoteFare",
+			Handler:    _TicketReservation_QuoteFare_Handler,
+		},
+		{
+			MethodName: "GetTicketDocument",
+			Handler:    _TicketReservation_GetTicketDocument_Handler,
+		},
+		{
+			MethodName: "ValidateTicket",
+			Handler:    _TicketReservation_ValidateTicket_Handler,
+		},
+		{
+			MethodName: "CheckIn",
+			Handler:    _TicketReservation_CheckIn_Handler,
+		},
+		{
+			MethodName: "Board",
+			Handler:    _TicketReservation_Board_Handler,
+		},
+		{
+			MethodName: "GetBoardingManifest",
+			Handler:    _TicketReservation_GetBoardingManifest_Handler,
+		},
+		{
+			MethodName: "JoinWaitlist",
+			Handler:    _TicketReservation_JoinWaitlist_Handler,
+		},
+		{
+			MethodName: "ListWaitlist",
+			Handler:    _TicketReservation_ListWaitlist_Handler,
+		},
+		{
+			MethodName: "ClaimWaitlist",
+			Handler:    _TicketReservation_ClaimWaitlist_Handler,
+		},
+		{
+			MethodName: "LeaveWaitlist",
+			Handler:    _TicketReservation_LeaveWaitlist_Handler,
+		},
+		{
+			MethodName: "QuoteCancellation",
+			Handler:    _TicketReservation_QuoteCancellation_Handler,
+		},
+		{
+			MethodName: "PlanJourneys",
+			Handler:    _TicketReservation_PlanJourneys_Handler,
+		},
+		{
+			MethodName: "ReserveJourney",
+			Handler:    _TicketReservation_ReserveJourney_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _TicketReservation_WatchEvents_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/ticket_reservation.proto",
 }
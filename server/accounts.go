@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	roleCustomer = "customer"
+	roleStaff    = "staff"
+	roleAdmin    = "admin"
+
+	minPasswordLen = 8
+	// principalPrefix marks x-principal values that name a customer account,
+	// e.g. "account:42". The web bridge sends it for signed-in users.
+	principalPrefix = "account:"
+	// principalBridge and principalStaff are what an API key can stand for
+	// besides an account; see parseAPIKeys.
+	principalBridge = "bridge"
+	principalStaff  = "staff"
+
+	reservationServicePrefix = "/ticket_reservation.TicketReservation/"
+)
+
+// dummyHash is compared against when an unknown email signs in so the
+// response time does not reveal which emails have accounts.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+func (s *TicketReservationServer) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.Account, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if _, err := mail.ParseAddress(email); err != nil || email == "" {
+		return nil, status.Error(codes.InvalidArgument, "a valid email is required")
+	}
+	if len(req.Password) < minPasswordLen {
+		return nil, status.Errorf(codes.InvalidArgument, "password must be at least %d characters", minPasswordLen)
+	}
+	// bcrypt ignores everything past 72 bytes; refuse rather than silently
+	// truncate.
+	if len(req.Password) > 72 {
+		return nil, status.Error(codes.InvalidArgument, "password must be at most 72 bytes")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Hash Error: %v", err)
+	}
+
+	a := &pb.Account{Email: email, Name: strings.TrimSpace(req.Name), Role: roleCustomer}
+	err = s.db.QueryRowContext(ctx,
+		"INSERT INTO accounts (email, name, password_hash, role) VALUES ($1, $2, $3, $4) RETURNING id",
+		a.Email, a.Name, string(hash), a.Role,
+	).Scan(&a.AccountId)
+	if e, ok := err.(*pq.Error); ok && e.Code == "23505" {
+		return nil, status.Error(codes.AlreadyExists, "an account with this email already exists")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "account created", "account_id", a.AccountId, logging.Email("email", a.Email))
+	return a, nil
+}
+
+func (s *TicketReservationServer) SignIn(ctx context.Context, req *pb.SignInRequest) (*pb.Account, error) {
+	var a pb.Account
+	var hash string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id, email, name, role, password_hash FROM accounts WHERE email = $1",
+		strings.ToLower(strings.TrimSpace(req.Email)),
+	).Scan(&a.AccountId, &a.Email, &a.Name, &a.Role, &hash)
+	if err == sql.ErrNoRows {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(req.Password))
+		return nil, status.Error(codes.Unauthenticated, "wrong email or password")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)) != nil {
+		return nil, status.Error(codes.Unauthenticated, "wrong email or password")
+	}
+	return &a, nil
+}
+
+// GetAccount returns the caller's account as it is now. The web bridge keeps
+// the role in its session cookie and asks again before acting on it.
+func (s *TicketReservationServer) GetAccount(ctx context.Context, req *pb.EmptyRequest) (*pb.Account, error) {
+	id, role, err := s.principalRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, status.Error(codes.Unauthenticated, "sign in first")
+	}
+	a := pb.Account{AccountId: id, Role: role}
+	if err := s.db.QueryRowContext(ctx, "SELECT email, name FROM accounts WHERE id = $1", id).Scan(&a.Email, &a.Name); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return &a, nil
+}
+
+// apiKey is who a caller holding an API key acts as: the web bridge, which
+// names the signed-in account in x-principal, a staff tool, or one account,
+// e.g. a partner's.
+type apiKey struct {
+	Bridge  bool
+	Staff   bool
+	Account uint64
+}
+
+// parseAPIKeys parses API_KEYS: "KEY=PRINCIPAL" entries separated by ","
+// where PRINCIPAL is "bridge", "staff" or "account:N".
+func parseAPIKeys(s string) (map[string]apiKey, error) {
+	keys := map[string]apiKey{}
+	for _, e := range strings.Split(s, ",") {
+		if strings.TrimSpace(e) == "" {
+			continue
+		}
+		key, who, ok := strings.Cut(e, "=")
+		key, who = strings.TrimSpace(key), strings.TrimSpace(who)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("API_KEYS: want KEY=PRINCIPAL entries")
+		}
+		var k apiKey
+		switch who {
+		case principalBridge:
+			k.Bridge = true
+		case principalStaff:
+			k.Staff = true
+		default:
+			v, ok := strings.CutPrefix(who, principalPrefix)
+			id, err := strconv.ParseUint(v, 10, 64)
+			if !ok || err != nil || id == 0 {
+				return nil, fmt.Errorf("API_KEYS: principal %q must be %q, %q or %sN", who, principalBridge, principalStaff, principalPrefix)
+			}
+			k.Account = id
+		}
+		keys[key] = k
+	}
+	return keys, nil
+}
+
+// caller is who an RPC is made by, as authenticate established it.
+type caller struct {
+	// Account is the account the call is made on behalf of; zero for
+	// guests of the web bridge and for staff tools.
+	Account uint64
+	// Staff marks staff tools, which may act on any ticket.
+	Staff bool
+}
+
+type callerKey struct{}
+
+// name is how inspections and logs refer to the caller: "staff",
+// "account:N" or "guest".
+func (c caller) name() string {
+	switch {
+	case c.Staff:
+		return principalStaff
+	case c.Account != 0:
+		return principalPrefix + strconv.FormatUint(c.Account, 10)
+	}
+	return "guest"
+}
+
+// authenticate resolves the caller of a TicketReservation call from its API
+// key. Only the bridge's key may name an account in x-principal; every other
+// key stands for a fixed principal, whatever metadata comes with it.
+func authenticate(ctx context.Context, keys map[string]apiKey) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	got := md.Get(logging.APIKeyKey)
+	if len(got) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "API key required")
+	}
+	var k apiKey
+	found := false
+	for key, v := range keys {
+		if subtle.ConstantTimeCompare([]byte(got[0]), []byte(key)) == 1 {
+			k, found = v, true
+		}
+	}
+	if !found {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	c := caller{Account: k.Account, Staff: k.Staff}
+	if k.Bridge {
+		for _, p := range md.Get(logging.PrincipalKey) {
+			if v, ok := strings.CutPrefix(p, principalPrefix); ok {
+				c.Account, _ = strconv.ParseUint(v, 10, 64)
+				break
+			}
+		}
+	}
+	logging.SetPrincipal(ctx, c.name())
+	return context.WithValue(ctx, callerKey{}, c), nil
+}
+
+// authInterceptor rejects TicketReservation calls without a valid API key
+// and records their caller. Other services pass through untouched.
+func authInterceptor(keys map[string]apiKey) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, reservationServicePrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is authInterceptor for streaming calls.
+func authStreamInterceptor(keys map[string]apiKey) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, reservationServicePrefix) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), keys)
+		if err != nil {
+			return err
+		}
+		return handler(srv, authedStream{ss, ctx})
+	}
+}
+
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authedStream) Context() context.Context { return s.ctx }
+
+// principalAccount returns the account the call is made on behalf of, or
+// zero if it is not made on behalf of an account.
+func principalAccount(ctx context.Context) uint64 {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c.Account
+}
+
+// principalRole returns the caller's account and its role. Staff tools have
+// no account and count as admins; guests have neither account nor role.
+func (s *TicketReservationServer) principalRole(ctx context.Context) (uint64, string, error) {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	if c.Staff {
+		return 0, roleAdmin, nil
+	}
+	if c.Account == 0 {
+		return 0, "", nil
+	}
+	var role string
+	err := s.db.QueryRowContext(ctx, "SELECT role FROM accounts WHERE id = $1", c.Account).Scan(&role)
+	if err == sql.ErrNoRows {
+		return 0, "", status.Error(codes.PermissionDenied, "unknown account")
+	}
+	if err != nil {
+		return 0, "", status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return c.Account, role, nil
+}
+
+// ticketOwner returns the account whose tickets the caller may change, or
+// zero if it may change any ticket, as admins and staff tools may. Guests
+// may change none.
+func (s *TicketReservationServer) ticketOwner(ctx context.Context) (uint64, error) {
+	id, role, err := s.principalRole(ctx)
+	if err != nil || role == roleAdmin {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, status.Error(codes.PermissionDenied, "sign in to manage tickets")
+	}
+	return id, nil
+}
+
+// requireStaff fails with PERMISSION_DENIED unless the caller is a staff or
+// admin account, or a staff tool.
+func (s *TicketReservationServer) requireStaff(ctx context.Context) error {
+	_, role, err := s.principalRole(ctx)
+	if err != nil {
+		return err
+	}
+	if role != roleStaff && role != roleAdmin {
+		return status.Error(codes.PermissionDenied, "only station staff can do this")
+	}
+	return nil
+}
+
+func (a *TicketAdminServer) SetAccountRole(ctx context.Context, req *pb.SetAccountRoleRequest) (*pb.Account, error) {
+	if req.Role != roleCustomer && req.Role != roleStaff && req.Role != roleAdmin {
+		return nil, status.Errorf(codes.InvalidArgument, "role must be %q, %q or %q", roleCustomer, roleStaff, roleAdmin)
+	}
+	var acc pb.Account
+	err := a.srv.db.QueryRowContext(ctx,
+		"UPDATE accounts SET role = $1 WHERE email = $2 RETURNING id, email, name, role",
+		req.Role, strings.ToLower(strings.TrimSpace(req.Email)),
+	).Scan(&acc.AccountId, &acc.Email, &acc.Name, &acc.Role)
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "account role changed", "account_id", acc.AccountId, "role", acc.Role)
+	return &acc, nil
+}
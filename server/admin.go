@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TicketAdminServer implements the operational TicketAdmin service on top of
+// the same database and lock as the public service.
+type TicketAdminServer struct {
+	pb.UnimplementedTicketAdminServer
+	srv *TicketReservationServer
+}
+
+const adminServicePrefix = "/ticket_reservation.TicketAdmin/"
+
+// adminAuthInterceptor rejects TicketAdmin calls that do not carry
+// "authorization: Bearer <token>". Other services pass through untouched.
+func adminAuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, adminServicePrefix) {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		auth := md.Get("authorization")
+		if token == "" || len(auth) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "admin token required")
+		}
+		got, _ := strings.CutPrefix(auth[0], "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return nil, status.Error(codes.PermissionDenied, "invalid admin token")
+		}
+		logging.SetPrincipal(ctx, "admin")
+		return handler(ctx, req)
+	}
+}
+
+func (a *TicketAdminServer) ListHolds(ctx context.Context, req *pb.EmptyRequest) (*pb.HoldList, error) {
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	rows, err := a.srv.db.QueryContext(ctx, `SELECT h.hold_id, h.departure_id, h.section, h.seat, a.code, b.code, h.held_until
+		FROM (SELECT hold_id, departure_id, section, seat, min(leg) AS first, max(leg) + 1 AS last, max(held_until) AS held_until
+			FROM seats WHERE hold_id IS NOT NULL AND held_until > now() GROUP BY hold_id, departure_id, section, seat) h
+		JOIN departure_stops a ON a.departure_id = h.departure_id AND a.stop = h.first
+		JOIN departure_stops b ON b.departure_id = h.departure_id AND b.stop = h.last
+		ORDER BY h.held_until`)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.HoldList{}
+	for rows.Next() {
+		var h pb.Hold
+		var until time.Time
+		if err := rows.Scan(&h.HoldId, &h.DepartureId, &h.Section, &h.Seat, &h.FromCode, &h.ToCode, &until); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		h.ExpiresAt = timestamppb.New(until)
+		resp.Holds = append(resp.Holds, &h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return resp, nil
+}
+
+func (a *TicketAdminServer) ExpireHolds(ctx context.Context, req *pb.ExpireHoldsRequest) (*pb.ExpireHoldsResponse, error) {
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	if !req.All && len(req.HoldIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "hold_ids or all required")
+	}
+
+	// A hold has a row for each leg it covers; it is counted once.
+	query := "UPDATE seats SET hold_id = NULL, held_until = NULL WHERE hold_id IS NOT NULL"
+	var args []any
+	if !req.All {
+		query += " AND hold_id = ANY($1)"
+		args = append(args, pq.Array(req.HoldIds))
+	}
+	var n int64
+	err := a.srv.db.QueryRowContext(ctx, "WITH expired AS ("+query+" RETURNING hold_id) SELECT count(DISTINCT hold_id) FROM expired", args...).Scan(&n)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "holds expired by admin", "count", n)
+	a.srv.events.publish("HoldsExpired", 0, seatRef{})
+	return &pb.ExpireHoldsResponse{Expired: uint32(n)}, nil
+}
+
+// ReindexSeats rebuilds seats.ticket_id from the passengers of every ticket.
+// Where two tickets claim the same seat on the same leg the older ticket
+// keeps it and the newer one is reported as conflicting.
+func (a *TicketAdminServer) ReindexSeats(ctx context.Context, req *pb.EmptyRequest) (*pb.ReindexSeatsResponse, error) {
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	if err := seedSeats(a.srv.db, a.srv.cfg); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+
+	tx, err := a.srv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	resp, err := reindexSeats(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "seat index rebuilt",
+		"seats", resp.Seats, "occupied", resp.Occupied, "conflicts", len(resp.ConflictingTickets))
+	a.srv.events.publish("SeatsReindexed", 0, seatRef{})
+	return resp, nil
+}
+
+// reindexSeats rebuilds the seat index within tx. Tickets whose payment
+// failed gave their seats up and are left out.
+func reindexSeats(ctx context.Context, tx *sql.Tx) (*pb.ReindexSeatsResponse, error) {
+	if _, err := tx.ExecContext(ctx, "UPDATE seats SET ticket_id = NULL"); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE seats s SET ticket_id = t.id, hold_id = NULL, held_until = NULL
+		FROM (SELECT DISTINCT ON (t.departure_id, p.section, p.seat, leg) t.id, t.departure_id, p.section, p.seat, leg
+			FROM tickets t JOIN ticket_passengers p ON p.ticket_id = t.id, generate_series(t.from_stop, t.to_stop - 1) leg
+			WHERE t.status IS DISTINCT FROM $1
+			ORDER BY t.departure_id, p.section, p.seat, leg, t.id) t
+		WHERE s.departure_id = t.departure_id AND s.section = t.section AND s.seat = t.seat AND s.leg = t.leg`, statusPaymentFailed)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+
+	resp := &pb.ReindexSeatsResponse{}
+	err = tx.QueryRowContext(ctx, `SELECT count(*),
+		count(ticket_id),
+		count(*) FILTER (WHERE hold_id IS NOT NULL AND held_until > now())
+		FROM seats`).Scan(&resp.Seats, &resp.Occupied, &resp.Held)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT t.id FROM tickets t
+		WHERE t.status IS DISTINCT FROM $1
+			AND (SELECT count(*) FROM seats s WHERE s.ticket_id = t.id)
+				< (SELECT count(*) FROM ticket_passengers p WHERE p.ticket_id = t.id) * (t.to_stop - t.from_stop)
+		ORDER BY t.id`, statusPaymentFailed)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		resp.ConflictingTickets = append(resp.ConflictingTickets, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return resp, nil
+}
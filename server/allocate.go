@@ -0,0 +1,318 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// distance is how far apart two seats of a coach are. Rows count for more
+// than seats along a row, so a pair shares a row before it shares a table.
+func distance(a, b seatPlace) int {
+	return 3*abs(a.Row-b.Row) + abs(a.Col-b.Col)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// penalty is how badly a seat in coach l at place misses what p asked for.
+func penalty(p *pb.UserDetails, l *coachLayout, place seatPlace) int {
+	pref := p.GetPreferences()
+	cost := 0
+	if p.Section != "" && p.Section != l.Section {
+		cost += 6
+	}
+	if pref.GetPosition() != pb.SeatPreferences_ANY_POSITION && pref.GetPosition() != place.Position {
+		cost += 3
+	}
+	if pref.GetFacing() != pb.SeatPreferences_ANY_DIRECTION && pref.GetFacing() != place.Facing {
+		cost += 2
+	}
+	if pref.GetQuietCoach() && !l.Quiet {
+		cost += 4
+	}
+	if pref.GetNearExit() && !place.NearExit {
+		cost += 2
+	}
+	switch {
+	case pref.GetAccessible() && !place.Accessible:
+		cost += 8
+	case !pref.GetAccessible() && place.Accessible:
+		// Priority seats are kept for those who need them.
+		cost += 3
+	}
+	return cost
+}
+
+// candidate is a free seat the allocator may give out.
+type candidate struct {
+	ref   seatRef
+	coach *coachLayout
+	place seatPlace
+}
+
+// allocateSeats locks and returns a seat free for the whole of the ride for
+// each of passengers, none of them in taken.
+func (s *TicketReservationServer) allocateSeats(ctx context.Context, tx *sql.Tx, ride seatRef, passengers []*pb.UserDetails, taken []seatRef) ([]seatRef, error) {
+	layout, err := s.departureLayout(ctx, tx, ride.DepartureID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, freeSeats, ride.DepartureID, ride.From, ride.To)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	var free [][]candidate
+	for rows.Next() {
+		ref := ride
+		if err := rows.Scan(&ref.Section, &ref.Seat); err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		if slices.Contains(taken, ref) {
+			continue
+		}
+		if len(free) == 0 || free[len(free)-1][0].ref.Section != ref.Section {
+			free = append(free, nil)
+		}
+		l := layout.coach(ref.Section)
+		free[len(free)-1] = append(free[len(free)-1], candidate{ref: ref, coach: l, place: l.place(ref.Seat)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	seats, ok := allocate(free, passengers)
+	if !ok {
+		return nil, status.Errorf(codes.ResourceExhausted, "departure %d is sold out", ride.DepartureID)
+	}
+	return seats, nil
+}
+
+// allocate gives each passenger a seat from free, the free seats of each
+// coach. The booking is kept in one coach and as close together as the
+// free seats allow; when no coach has room for everyone, each party is kept
+// together instead, and a party too big for any coach fills the roomiest
+// ones. Within that, seats go by the passengers' preferences. It reports
+// false when there are fewer free seats than passengers.
+func allocate(free [][]candidate, passengers []*pb.UserDetails) ([]seatRef, bool) {
+	n := 0
+	for _, c := range free {
+		n += len(c)
+	}
+	if n < len(passengers) {
+		return nil, false
+	}
+	room := func() int {
+		n := 0
+		for _, c := range free {
+			n = max(n, len(c))
+		}
+		return n
+	}
+	groups := [][]int{make([]int, len(passengers))}
+	for i := range passengers {
+		groups[0][i] = i
+	}
+	if room() < len(passengers) {
+		groups = parties(passengers)
+		slices.SortStableFunc(groups, func(a, b []int) int { return cmp.Compare(len(b), len(a)) })
+	}
+
+	out := make([]seatRef, len(passengers))
+	for _, g := range groups {
+		for len(g) > 0 {
+			part := g[:min(len(g), room())]
+			coach, seats := bestCluster(free, part, passengers)
+			for i, p := range part {
+				out[p] = free[coach][seats[i]].ref
+			}
+			slices.Sort(seats)
+			for i := len(seats) - 1; i >= 0; i-- {
+				free[coach] = slices.Delete(free[coach], seats[i], seats[i]+1)
+			}
+			g = g[len(part):]
+		}
+	}
+	return out, true
+}
+
+// parties splits the passengers by party, in order of first appearance.
+// Passengers without a party make up one party of their own.
+func parties(passengers []*pb.UserDetails) [][]int {
+	var groups [][]int
+	index := map[string]int{}
+	for i, p := range passengers {
+		g, ok := index[p.Party]
+		if !ok {
+			g = len(groups)
+			index[p.Party] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// bestCluster finds the coach and seats for part that cost least: for
+// every free seat, the len(part) free seats closest to it, handed out by
+// preference. It returns the coach and, for each passenger of part, the
+// index of their seat in free[coach].
+func bestCluster(free [][]candidate, part []int, passengers []*pb.UserDetails) (int, []int) {
+	bestCoach, bestCost := -1, 0
+	var best []int
+	for ci, seats := range free {
+		if len(seats) < len(part) {
+			continue
+		}
+		order := make([]int, len(seats))
+		for _, anchor := range seats {
+			for i := range order {
+				order[i] = i
+			}
+			slices.SortStableFunc(order, func(a, b int) int {
+				return cmp.Compare(distance(anchor.place, seats[a].place), distance(anchor.place, seats[b].place))
+			})
+			cluster := order[:len(part)]
+			cost := 0
+			for _, i := range cluster {
+				cost += distance(anchor.place, seats[i].place)
+			}
+			assigned, prefCost := assignSeats(seats, cluster, part, passengers)
+			if cost += prefCost; bestCoach < 0 || cost < bestCost {
+				bestCoach, bestCost, best = ci, cost, assigned
+			}
+		}
+	}
+	return bestCoach, best
+}
+
+// assignSeats hands the seats of cluster to the passengers of part, those
+// with the most demanding preferences choosing first, and returns each
+// passenger's seat and the total penalty.
+func assignSeats(seats []candidate, cluster, part []int, passengers []*pb.UserDetails) ([]int, int) {
+	byDemand := slices.Clone(part)
+	slices.SortStableFunc(byDemand, func(a, b int) int { return cmp.Compare(demand(passengers[b]), demand(passengers[a])) })
+	left := slices.Clone(cluster)
+	seatOf := map[int]int{}
+	total := 0
+	for _, p := range byDemand {
+		bestAt, bestCost := 0, -1
+		for at, i := range left {
+			if c := penalty(passengers[p], seats[i].coach, seats[i].place); bestCost < 0 || c < bestCost {
+				bestAt, bestCost = at, c
+			}
+		}
+		seatOf[p] = left[bestAt]
+		left = slices.Delete(left, bestAt, bestAt+1)
+		total += bestCost
+	}
+	assigned := make([]int, len(part))
+	for i, p := range part {
+		assigned[i] = seatOf[p]
+	}
+	return assigned, total
+}
+
+// demand weighs how hard a passenger's preferences are to meet.
+func demand(p *pb.UserDetails) int {
+	pref := p.GetPreferences()
+	n := 0
+	if pref.GetAccessible() {
+		n += 8
+	}
+	if pref.GetQuietCoach() {
+		n += 4
+	}
+	if pref.GetPosition() != pb.SeatPreferences_ANY_POSITION {
+		n += 3
+	}
+	if pref.GetFacing() != pb.SeatPreferences_ANY_DIRECTION {
+		n += 2
+	}
+	if pref.GetNearExit() {
+		n += 2
+	}
+	return n
+}
+
+// unmetPreferences lists what the seats the passengers got do not give
+// them of what they asked for, including being seated with their party or,
+// without one, the rest of the booking.
+func (s *TicketReservationServer) unmetPreferences(ctx context.Context, tx *sql.Tx, passengers []*pb.UserDetails, seats []seatRef) ([]*pb.UnmetPreference, error) {
+	layout, err := s.departureLayout(ctx, tx, seats[0].DepartureID)
+	if err != nil {
+		return nil, err
+	}
+	return layout.unmetPreferences(passengers, seats), nil
+}
+
+func (layout *seatLayout) unmetPreferences(passengers []*pb.UserDetails, seats []seatRef) []*pb.UnmetPreference {
+	var unmet []*pb.UnmetPreference
+	miss := func(i int, pref, reason string, args ...any) {
+		unmet = append(unmet, &pb.UnmetPreference{Passenger: uint32(i), Preference: pref, Reason: fmt.Sprintf(reason, args...)})
+	}
+	for i, p := range passengers {
+		ref := seats[i]
+		l := layout.coach(ref.Section)
+		place := l.place(ref.Seat)
+		pref := p.GetPreferences()
+		seat := fmt.Sprintf("%s-%d", ref.Section, ref.Seat)
+
+		if p.Section != "" && p.Seat == 0 && p.Section != ref.Section {
+			miss(i, "section", "no seat was free in coach %s, seated in coach %s", p.Section, ref.Section)
+		}
+		if want := pref.GetPosition(); want != pb.SeatPreferences_ANY_POSITION && want != place.Position {
+			where := "in the middle"
+			switch place.Position {
+			case pb.SeatPreferences_WINDOW:
+				where = "by the window"
+			case pb.SeatPreferences_AISLE:
+				where = "on the aisle"
+			}
+			miss(i, strings.ToLower(want.String()), "seat %s is %s", seat, where)
+		}
+		if want := pref.GetFacing(); want != pb.SeatPreferences_ANY_DIRECTION && want != place.Facing {
+			miss(i, strings.ToLower(want.String()), "seat %s faces %s", seat, strings.ToLower(place.Facing.String()))
+		}
+		if pref.GetQuietCoach() && !l.Quiet {
+			miss(i, "quiet_coach", "coach %s is not a quiet coach", ref.Section)
+		}
+		if pref.GetNearExit() && !place.NearExit {
+			miss(i, "near_exit", "seat %s is not near the doors", seat)
+		}
+		if pref.GetAccessible() && !place.Accessible {
+			miss(i, "accessible", "no priority seat was free, seated in %s", seat)
+		}
+
+		together, others := false, false
+		for j, q := range passengers {
+			if j == i || q.Party != p.Party {
+				continue
+			}
+			others = true
+			if seats[j].Section == ref.Section && abs(l.place(seats[j].Seat).Row-place.Row) <= 1 {
+				together = true
+			}
+		}
+		if others && !together {
+			if p.Party != "" {
+				miss(i, "together", "seated in %s, away from the rest of party %s", seat, p.Party)
+			} else {
+				miss(i, "together", "seated in %s, away from the rest of the booking", seat)
+			}
+		}
+	}
+	return unmet
+}
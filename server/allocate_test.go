@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+// testLayout is two coaches of two WA_AW rows, the front row facing forward
+// and the back row backward. A is a quiet coach with priority seats 1 and 2.
+func testLayout() *seatLayout {
+	l := &pb.Layout{Coaches: []*pb.Coach{
+		{Section: "A", Rows: 2, Quiet: true, AccessibleSeats: []uint32{1, 2}},
+		{Section: "B", Rows: 2},
+	}}
+	if err := checkLayout(l, false); err != nil {
+		panic(err)
+	}
+	return expandLayout(l, 0)
+}
+
+// candidates lists the free seats written in spec, e.g. "A-1 A-2 B", where
+// a bare section stands for every seat of the coach, as allocateSeats
+// reads them: by coach, in seat order.
+func candidates(sl *seatLayout, spec string) [][]candidate {
+	bySection := map[string][]uint32{}
+	for _, f := range strings.Fields(spec) {
+		section, seat, ok := strings.Cut(f, "-")
+		if !ok {
+			bySection[section] = append(bySection[section], sl.coach(section).Seats...)
+			continue
+		}
+		n, err := strconv.ParseUint(seat, 10, 32)
+		if err != nil {
+			panic(err)
+		}
+		bySection[section] = append(bySection[section], uint32(n))
+	}
+	var sections []string
+	for s := range bySection {
+		sections = append(sections, s)
+	}
+	sort.Strings(sections)
+	var free [][]candidate
+	for _, s := range sections {
+		l := sl.coach(s)
+		seats := bySection[s]
+		slices.Sort(seats)
+		var c []candidate
+		for _, n := range seats {
+			c = append(c, candidate{ref: seatRef{DepartureID: 1, Section: s, Seat: n}, coach: l, place: l.place(n)})
+		}
+		free = append(free, c)
+	}
+	return free
+}
+
+func refs(spec string) []seatRef {
+	var out []seatRef
+	for _, f := range strings.Fields(spec) {
+		section, seat, _ := strings.Cut(f, "-")
+		n, _ := strconv.ParseUint(seat, 10, 32)
+		out = append(out, seatRef{DepartureID: 1, Section: section, Seat: uint32(n)})
+	}
+	return out
+}
+
+func seatNames(seats []seatRef) string {
+	names := make([]string, len(seats))
+	for i, s := range seats {
+		names[i] = fmt.Sprintf("%s-%d", s.Section, s.Seat)
+	}
+	return strings.Join(names, " ")
+}
+
+func passenger(party string, pref *pb.SeatPreferences) *pb.UserDetails {
+	return &pb.UserDetails{FirstName: "P", Party: party, Preferences: pref}
+}
+
+func passengers(n int) []*pb.UserDetails {
+	ps := make([]*pb.UserDetails, n)
+	for i := range ps {
+		ps[i] = passenger("", nil)
+	}
+	return ps
+}
+
+func TestAllocate(t *testing.T) {
+	sl := testLayout()
+	needsPriority := &pb.SeatPreferences{Accessible: true}
+	tests := []struct {
+		name       string
+		free       string
+		passengers []*pb.UserDetails
+		// want is the seat of each passenger, "" when allocation fails.
+		want string
+	}{
+		{
+			name:       "a booking sits together away from the priority seats",
+			free:       "A B",
+			passengers: passengers(3),
+			want:       "A-6 A-5 A-7",
+		},
+		{
+			name:       "a quiet coach is chosen for who asks for one",
+			free:       "A B",
+			passengers: []*pb.UserDetails{passenger("", &pb.SeatPreferences{QuietCoach: true})},
+			want:       "A-3",
+		},
+		{
+			name:       "priority seats go to passengers who need them",
+			free:       "A",
+			passengers: []*pb.UserDetails{passenger("", nil), passenger("", needsPriority), passenger("", nil)},
+			want:       "A-3 A-2 A-4",
+		},
+		{
+			name:       "a booking too big for any coach splits by party",
+			free:       "A-5 A-6 A-7 B-1 B-2 B-3",
+			passengers: []*pb.UserDetails{passenger("f", nil), passenger("g", nil), passenger("f", nil), passenger("g", nil)},
+			want:       "A-5 B-1 A-6 B-2",
+		},
+		{
+			name:       "a party too big for any coach fills the roomiest first",
+			free:       "A-5 A-6 A-7 B-1 B-2",
+			passengers: passengers(5),
+			want:       "A-6 A-5 A-7 B-1 B-2",
+		},
+		{
+			name:       "more passengers than free seats",
+			free:       "A-1 B-1",
+			passengers: passengers(3),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := allocate(candidates(sl, tt.free), tt.passengers)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("allocate = %s, want it to fail", seatNames(got))
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("allocate failed, want %s", tt.want)
+			}
+			if seatNames(got) != tt.want {
+				t.Errorf("allocate = %s, want %s", seatNames(got), tt.want)
+			}
+		})
+	}
+}
+
+// TestAllocateSplitParty checks that a party the free seats cannot keep
+// together is told so.
+func TestAllocateSplitParty(t *testing.T) {
+	sl := testLayout()
+	ps := []*pb.UserDetails{passenger("f", nil), passenger("f", nil)}
+	got, ok := allocate(candidates(sl, "A-1 B-8"), ps)
+	if !ok {
+		t.Fatal("allocate failed")
+	}
+	// The priority seat is left to the last passenger placed.
+	if seatNames(got) != "B-8 A-1" {
+		t.Fatalf("allocate = %s, want B-8 A-1", seatNames(got))
+	}
+	want := []string{
+		"0 together: seated in B-8, away from the rest of party f",
+		"1 together: seated in A-1, away from the rest of party f",
+	}
+	if got := describeUnmet(sl.unmetPreferences(ps, got)); !slices.Equal(got, want) {
+		t.Errorf("unmetPreferences = %q, want %q", got, want)
+	}
+}
+
+func describeUnmet(unmet []*pb.UnmetPreference) []string {
+	var out []string
+	for _, u := range unmet {
+		out = append(out, fmt.Sprintf("%d %s: %s", u.Passenger, u.Preference, u.Reason))
+	}
+	return out
+}
+
+func TestUnmetPreferences(t *testing.T) {
+	sl := testLayout()
+	tests := []struct {
+		name       string
+		passengers []*pb.UserDetails
+		seats      string
+		want       []string
+	}{
+		{"all met", []*pb.UserDetails{passenger("", &pb.SeatPreferences{Position: pb.SeatPreferences_WINDOW, QuietCoach: true, Accessible: true})}, "A-1", nil},
+		{"window", []*pb.UserDetails{passenger("", &pb.SeatPreferences{Position: pb.SeatPreferences_WINDOW})}, "A-2",
+			[]string{"0 window: seat A-2 is on the aisle"}},
+		{"aisle", []*pb.UserDetails{passenger("", &pb.SeatPreferences{Position: pb.SeatPreferences_AISLE})}, "B-4",
+			[]string{"0 aisle: seat B-4 is by the window"}},
+		{"facing", []*pb.UserDetails{passenger("", &pb.SeatPreferences{Facing: pb.SeatPreferences_FORWARD})}, "B-5",
+			[]string{"0 forward: seat B-5 faces backward"}},
+		{"quiet coach", []*pb.UserDetails{passenger("", &pb.SeatPreferences{QuietCoach: true})}, "B-1",
+			[]string{"0 quiet_coach: coach B is not a quiet coach"}},
+		{"near exit", []*pb.UserDetails{passenger("", &pb.SeatPreferences{NearExit: true})}, "A-3",
+			[]string{"0 near_exit: seat A-3 is not near the doors"}},
+		{"accessible", []*pb.UserDetails{passenger("", &pb.SeatPreferences{Accessible: true})}, "A-3",
+			[]string{"0 accessible: no priority seat was free, seated in A-3"}},
+		{"section", []*pb.UserDetails{{FirstName: "P", Section: "B"}}, "A-3",
+			[]string{"0 section: no seat was free in coach B, seated in coach A"}},
+		{"together in neighbouring rows", passengers(2), "A-3 A-7", nil},
+		{"booking apart", passengers(2), "A-3 B-3",
+			[]string{"0 together: seated in A-3, away from the rest of the booking", "1 together: seated in B-3, away from the rest of the booking"}},
+		{"parties apart from each other", []*pb.UserDetails{passenger("f", nil), passenger("g", nil)}, "A-3 B-3", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describeUnmet(sl.unmetPreferences(tt.passengers, refs(tt.seats)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("unmetPreferences = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParties(t *testing.T) {
+	tests := []struct {
+		parties []string
+		want    [][]int
+	}{
+		{[]string{"", "", ""}, [][]int{{0, 1, 2}}},
+		{[]string{"f", "g", "f", ""}, [][]int{{0, 2}, {1}, {3}}},
+		{[]string{"", "f", "", "f"}, [][]int{{0, 2}, {1, 3}}},
+	}
+	for _, tt := range tests {
+		var ps []*pb.UserDetails
+		for _, p := range tt.parties {
+			ps = append(ps, passenger(p, nil))
+		}
+		got := parties(ps)
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("parties(%q) = %v, want %v", tt.parties, got, tt.want)
+		}
+	}
+}
+
+func TestBestCluster(t *testing.T) {
+	sl := testLayout()
+	// A-4 and A-8 are a row apart; B-1 and B-2 sit side by side.
+	free := candidates(sl, "A-1 A-4 A-8 B-1 B-2")
+	coach, seats := bestCluster(free, []int{0, 1}, passengers(2))
+	var got []seatRef
+	for _, i := range seats {
+		got = append(got, free[coach][i].ref)
+	}
+	if seatNames(got) != "B-1 B-2" {
+		t.Errorf("bestCluster = %s, want B-1 B-2", seatNames(got))
+	}
+}
+
+func TestAssignSeats(t *testing.T) {
+	sl := testLayout()
+	seats := candidates(sl, "A-1 A-2")[0]
+	// The passenger who needs a priority window seat chooses first, even
+	// though listed second.
+	ps := []*pb.UserDetails{
+		passenger("", &pb.SeatPreferences{Position: pb.SeatPreferences_WINDOW}),
+		passenger("", &pb.SeatPreferences{Position: pb.SeatPreferences_WINDOW, Accessible: true}),
+	}
+	assigned, cost := assignSeats(seats, []int{0, 1}, []int{0, 1}, ps)
+	if !slices.Equal(assigned, []int{1, 0}) {
+		t.Errorf("assignSeats = %v, want [1 0]", assigned)
+	}
+	// The first passenger misses the window and takes a priority seat.
+	if cost != 6 {
+		t.Errorf("assignSeats cost = %d, want 6", cost)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Ticket statuses after booking. A ticket is CheckedIn once its passengers
+// have checked in and Boarded once any of them has boarded. Tickets nobody
+// boarded become NoShow when the train leaves.
+const (
+	statusCheckedIn = "CheckedIn"
+	statusBoarded   = "Boarded"
+	statusNoShow    = "NoShow"
+)
+
+// windowTime is how opening and closing times appear in errors.
+const windowTime = "Mon 2 Jan 15:04 MST"
+
+// travelled fails for tickets that can no longer be changed because the
+// journey has started or was missed.
+func travelled(ticketNo uint64, st string) error {
+	if st == statusBoarded || st == statusNoShow {
+		return status.Errorf(codes.FailedPrecondition, "ticket %d is %s and can no longer be changed", ticketNo, st)
+	}
+	return nil
+}
+
+// checkInOpen fails unless check-in for a train leaving at departs is open
+// at now.
+func (c config) checkInOpen(departs, now time.Time) error {
+	opens, closes := departs.Add(-c.CheckInOpens), departs.Add(-c.CheckInCloses)
+	switch {
+	case now.Before(opens):
+		return status.Errorf(codes.FailedPrecondition, "check-in opens at %s", opens.UTC().Format(windowTime))
+	case !now.Before(closes):
+		return status.Errorf(codes.FailedPrecondition, "check-in closed at %s", closes.UTC().Format(windowTime))
+	}
+	return nil
+}
+
+// boardingOpen fails unless boarding for a train leaving at departs is open
+// at now.
+func (c config) boardingOpen(departs, now time.Time) error {
+	opens := departs.Add(-c.BoardingOpens)
+	switch {
+	case now.Before(opens):
+		return status.Errorf(codes.FailedPrecondition, "boarding opens at %s", opens.UTC().Format(windowTime))
+	case !now.Before(departs):
+		return status.Errorf(codes.FailedPrecondition, "the train left at %s", departs.UTC().Format(windowTime))
+	}
+	return nil
+}
+
+func (s *TicketReservationServer) CheckIn(ctx context.Context, req *pb.ReservationRequest) (*pb.ReservationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.TicketNo == nil {
+		return nil, status.Error(codes.InvalidArgument, "ID required")
+	}
+	// Customers check in their own tickets; staff check in anyone's.
+	owner, role, err := s.principalRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if role == roleStaff || role == roleAdmin {
+		owner = 0
+	} else if owner == 0 {
+		return nil, status.Error(codes.PermissionDenied, "sign in to check in")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	var st string
+	var departure uint64
+	var departs time.Time
+	err = tx.QueryRowContext(ctx, `SELECT t.status, d.departure_id, d.departs_at
+		FROM tickets t JOIN departure_stops d ON d.departure_id = t.departure_id AND d.stop = t.from_stop
+		WHERE t.id = $1 AND ($2 = 0 OR t.account_id = $2) FOR UPDATE OF t`,
+		*req.TicketNo, owner).Scan(&st, &departure, &departs)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "ticket %d not found", *req.TicketNo)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if err := travelled(*req.TicketNo, st); err != nil {
+		return nil, err
+	}
+	if err := unpaid(*req.TicketNo, st); err != nil {
+		return nil, err
+	}
+	if err := s.cfg.checkInOpen(departs, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE ticket_passengers SET checked_in_at = COALESCE(checked_in_at, now()) WHERE ticket_id = $1",
+		*req.TicketNo); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE tickets SET status = $1 WHERE id = $2", statusCheckedIn, *req.TicketNo); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	if err := s.recordEvent(ctx, tx, "TicketCheckedIn", *req.TicketNo, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "ticket checked in", "ticket_no", *req.TicketNo, "departure_id", departure)
+	s.events.publish("TicketCheckedIn", *req.TicketNo, seatRef{DepartureID: departure})
+	return s.loadTicket(ctx, *req.TicketNo)
+}
+
+func (s *TicketReservationServer) Board(ctx context.Context, req *pb.BoardRequest) (*pb.ReservationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.TicketNo == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ticket_no required")
+	}
+	if err := s.requireStaff(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	var st string
+	var seat seatRef
+	var departs time.Time
+	var checkedIn, boarded sql.NullTime
+	err = tx.QueryRowContext(ctx, `SELECT t.status, d.departure_id, d.departs_at, p.section, p.seat, p.checked_in_at, p.boarded_at
+		FROM ticket_passengers p JOIN tickets t ON t.id = p.ticket_id
+			JOIN departure_stops d ON d.departure_id = t.departure_id AND d.stop = t.from_stop
+		WHERE p.ticket_id = $1 AND p.position = $2 FOR UPDATE OF p, t`,
+		req.TicketNo, req.Position).Scan(&st, &seat.DepartureID, &departs, &seat.Section, &seat.Seat, &checkedIn, &boarded)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "ticket %d has no passenger %d", req.TicketNo, req.Position)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	// Boarding the same passenger twice, e.g. after a retry, is not an
+	// error.
+	if !boarded.Valid {
+		if st == statusNoShow {
+			return nil, travelled(req.TicketNo, st)
+		}
+		if err := s.cfg.boardingOpen(departs, time.Now()); err != nil {
+			return nil, err
+		}
+		if !checkedIn.Valid {
+			return nil, status.Errorf(codes.FailedPrecondition, "passenger %d of ticket %d has not checked in", req.Position, req.TicketNo)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE ticket_passengers SET boarded_at = now() WHERE ticket_id = $1 AND position = $2",
+			req.TicketNo, req.Position); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tickets SET status = $1 WHERE id = $2", statusBoarded, req.TicketNo); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+		if err := s.recordEvent(ctx, tx, "PassengerBoarded", req.TicketNo, nil); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+		}
+		logging.FromContext(ctx).InfoContext(ctx, "passenger boarded",
+			"ticket_no", req.TicketNo, "position", req.Position, "departure_id", seat.DepartureID)
+		s.events.publish("PassengerBoarded", req.TicketNo, seat)
+	}
+	return s.loadTicket(ctx, req.TicketNo)
+}
+
+func (s *TicketReservationServer) GetBoardingManifest(ctx context.Context, req *pb.ManifestRequest) (*pb.BoardingManifest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.DepartureId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "departure_id required")
+	}
+	if err := s.requireStaff(ctx); err != nil {
+		return nil, err
+	}
+
+	d := &pb.Departure{}
+	var departs, arrives time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT d.id, d.train, d.from_code, d.to_code, d.departs_at, d.arrives_at, `+freeBetween+`,
+		(SELECT count(*) FROM seats WHERE departure_id = d.id AND leg = 0 AND NOT blocked)
+		FROM departures d JOIN departure_stops a ON a.departure_id = d.id AND a.stop = 0
+			JOIN departure_stops b ON b.departure_id = d.id AND b.stop = (SELECT max(stop) FROM departure_stops WHERE departure_id = d.id)
+		WHERE d.id = $1`, req.DepartureId,
+	).Scan(&d.DepartureId, &d.Train, &d.FromCode, &d.ToCode, &departs, &arrives, &d.SeatsFree, &d.Capacity)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "departure %d not found", req.DepartureId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	d.DepartsAt, d.ArrivesAt = timestamppb.New(departs), timestamppb.New(arrives)
+	if err := addStops(ctx, s.db, []*pb.Departure{d}); err != nil {
+		return nil, err
+	}
+	m := &pb.BoardingManifest{
+		Departure:       d,
+		CheckInOpensAt:  timestamppb.New(departs.Add(-s.cfg.CheckInOpens)),
+		CheckInClosesAt: timestamppb.New(departs.Add(-s.cfg.CheckInCloses)),
+		BoardingOpensAt: timestamppb.New(departs.Add(-s.cfg.BoardingOpens)),
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT p.section, p.seat, p.ticket_id, p.position, p.first_name, p.last_name,
+		p.checked_in_at, p.boarded_at, a.code, b.code, a.departs_at
+		FROM ticket_passengers p JOIN tickets t ON t.id = p.ticket_id
+			JOIN departure_stops a ON a.departure_id = t.departure_id AND a.stop = t.from_stop
+			JOIN departure_stops b ON b.departure_id = t.departure_id AND b.stop = t.to_stop
+		WHERE t.departure_id = $1 AND t.status NOT IN ($2, $3) ORDER BY p.section, p.seat, t.from_stop`,
+		req.DepartureId, statusPaymentPending, statusPaymentFailed)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p pb.BoardingManifest_Passenger
+		var last string
+		var checkedIn, boarded sql.NullTime
+		var boards time.Time
+		if err := rows.Scan(&p.Section, &p.Seat, &p.TicketNo, &p.Position, &p.Name, &last, &checkedIn, &boarded,
+			&p.FromCode, &p.ToCode, &boards); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		if last != "" {
+			p.Name += " " + last
+		}
+		if checkedIn.Valid {
+			p.CheckedInAt = timestamppb.New(checkedIn.Time)
+		}
+		switch {
+		case boarded.Valid:
+			p.State, p.BoardedAt = pb.BoardingManifest_BOARDED, timestamppb.New(boarded.Time)
+		case !time.Now().Before(boards):
+			p.State = pb.BoardingManifest_NO_SHOW
+		case checkedIn.Valid:
+			p.State = pb.BoardingManifest_CHECKED_IN
+		}
+		m.Passengers = append(m.Passengers, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return m, nil
+}
+
+// markNoShows marks the tickets of departed trains as NoShow every minute
+// while the server runs.
+func (s *TicketReservationServer) markNoShows(logger *slog.Logger) {
+	for range time.Tick(time.Minute) {
+		s.mu.Lock()
+		err := s.sweepNoShows(context.Background(), logger)
+		s.mu.Unlock()
+		if err != nil {
+			logger.Error("marking no-shows failed", "error", err)
+		}
+	}
+}
+
+// sweepNoShows marks every ticket whose train has left the stop its
+// passengers board at without any of them as NoShow.
+func (s *TicketReservationServer) sweepNoShows(ctx context.Context, logger *slog.Logger) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `UPDATE tickets t SET status = $1 FROM departure_stops d
+		WHERE d.departure_id = t.departure_id AND d.stop = t.from_stop AND d.departs_at <= now()
+			AND t.status IN ('Confirmed', 'Modified', $2)
+		RETURNING t.id, d.departure_id`, statusNoShow, statusCheckedIn)
+	if err != nil {
+		return err
+	}
+	var marked []seatRef
+	var tickets []uint64
+	for rows.Next() {
+		var id uint64
+		var ref seatRef
+		if err := rows.Scan(&id, &ref.DepartureID); err != nil {
+			rows.Close()
+			return err
+		}
+		tickets, marked = append(tickets, id), append(marked, ref)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range tickets {
+		if err := s.recordEvent(ctx, tx, "TicketNoShow", id, nil); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if len(tickets) > 0 {
+		logger.Info("tickets marked as no-show", "count", len(tickets))
+	}
+	for i, id := range tickets {
+		s.events.publish("TicketNoShow", id, marked[i])
+	}
+	return nil
+}
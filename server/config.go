@@ -0,0 +1,222 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/tickettoken"
+	"google.golang.org/grpc/status"
+)
+
+// config holds the server settings. Every field is read from an environment
+// variable so the server can be configured from docker-compose.yaml.
+type config struct {
+	DatabaseURL string
+	GatewayAddr string
+
+	// Reflection registers the gRPC reflection service (GRPC_REFLECTION=true)
+	// so grpcurl and similar tools can discover the API.
+	Reflection bool
+	// AdminToken guards the TicketAdmin service. The service is not
+	// registered when it is empty.
+	AdminToken string
+	// APIKeys authenticate the callers of the TicketReservation service,
+	// mapping each key to who it acts as (API_KEYS, see parseAPIKeys).
+	// Calls without a listed key are rejected.
+	APIKeys map[string]apiKey
+
+	// Sections, SeatsPerSection and QuietSections make up the layout of
+	// departures that have none attached; see defaultLayout.
+	Sections        []string
+	SeatsPerSection int
+	QuietSections   []string
+	HoldTTL         time.Duration
+
+	// Timetable lists the daily services as "TRAIN FROM TO HH:MM DURATION"
+	// entries separated by ";", with any stops on the way between FROM and
+	// TO as "STOP+ARRIVAL" or "STOP+ARRIVAL/DEPARTURE", offsets from the
+	// departure. Departures are kept ScheduleDays ahead.
+	Timetable    []timetableEntry
+	ScheduleDays int
+
+	// MinTransfer is the least time PlanJourneys and ReserveJourney allow
+	// for changing trains, unless TransferTimes has the station's own.
+	// MaxTransfers bounds the changes of a journey.
+	MinTransfer   time.Duration
+	TransferTimes map[string]time.Duration
+	MaxTransfers  int
+
+	// FareBase is the price per passenger and FareSeatSelection the
+	// surcharge per passenger who picks a seat, by section, both in minor
+	// units of Currency.
+	FareBase          uint64
+	FareSeatSelection map[string]uint64
+	Currency          string
+	// FareTypes are the fare types that can be booked, by name. The
+	// standard fare costs FareBase; each type has its cancellation policy.
+	FareTypes map[string]fareType
+
+	// Check-in is open from CheckInOpens until CheckInCloses before
+	// departure, boarding from BoardingOpens before departure until it
+	// leaves.
+	CheckInOpens  time.Duration
+	CheckInCloses time.Duration
+	BoardingOpens time.Duration
+
+	// WaitlistClaimTTL is how long seats held for a promoted waitlist entry
+	// stay held.
+	WaitlistClaimTTL time.Duration
+
+	// PaymentProvider names the payment provider; only the local "fake"
+	// is built in. Calls to it time out after PaymentTimeout, and bookings
+	// whose payment is still pending after PaymentPendingTTL fail.
+	// FakePaymentDelay is how long the fake waits before its webhooks.
+	PaymentProvider   string
+	PaymentTimeout    time.Duration
+	PaymentPendingTTL time.Duration
+	FakePaymentDelay  time.Duration
+
+	// SMTPAddr is the host:port of the SMTP server that notification emails
+	// go through; no emails are sent when it is empty. SMTPUsername and
+	// SMTPPassword are for servers that require authentication.
+	SMTPAddr     string
+	SMTPFrom     string
+	SMTPUsername string
+	SMTPPassword string
+	// Emails that cannot be sent are retried after MailRetryBase, doubling
+	// each time, until MailMaxAttempts attempts have failed.
+	MailRetryBase   time.Duration
+	MailMaxAttempts int
+	// ReminderBefore is how long before departure travellers are reminded
+	// of their journey.
+	ReminderBefore time.Duration
+
+	// EventSinks are where the relay delivers domain events; see
+	// newEventSinks for the forms an entry takes.
+	EventSinks []string
+	// Partner webhook deliveries that fail are retried after
+	// WebhookRetryBase, doubling each time, until WebhookMaxAttempts
+	// attempts have failed and the delivery is dead.
+	WebhookRetryBase   time.Duration
+	WebhookMaxAttempts int
+
+	// TicketKey signs ticket tokens (TICKET_SIGNING_KEY, a base64 Ed25519
+	// seed). A random key is used when it is unset.
+	TicketKey ed25519.PrivateKey
+}
+
+const defaultTimetable = "T101 London Paris 08:00 2h20m; T103 London Paris 14:00 2h20m; T102 Paris London 10:00 2h20m; T104 Paris London 17:00 2h20m"
+
+func loadConfig() (config, error) {
+	timetable, err := parseTimetable(getenv("TIMETABLE", defaultTimetable))
+	if err != nil {
+		return config{}, err
+	}
+	var ticketKey ed25519.PrivateKey
+	if v := os.Getenv("TICKET_SIGNING_KEY"); v != "" {
+		if ticketKey, err = tickettoken.ParsePrivateKey(v); err != nil {
+			return config{}, err
+		}
+	}
+	apiKeys, err := parseAPIKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		return config{}, err
+	}
+	transferTimes, err := parseTransferTimes(os.Getenv("TRANSFER_TIMES"))
+	if err != nil {
+		return config{}, err
+	}
+	surcharges, err := parseSurcharges(getenv("FARE_SEAT_SELECTION", "A=1500"))
+	if err != nil {
+		return config{}, err
+	}
+	fareBase := uint64(getenvInt("FARE_BASE", 4500))
+	fareTypes, err := parseFareTypes(fareBase, getenv("FARE_TYPES", defaultFareTypes), getenv("CANCELLATION_POLICY", defaultCancellationPolicy))
+	if err != nil {
+		return config{}, err
+	}
+	checkInOpens := getenvDuration("CHECKIN_OPENS", 24*time.Hour)
+	checkInCloses := getenvDuration("CHECKIN_CLOSES", 15*time.Minute)
+	if checkInCloses >= checkInOpens {
+		return config{}, fmt.Errorf("CHECKIN_CLOSES (%v) must be shorter than CHECKIN_OPENS (%v)", checkInCloses, checkInOpens)
+	}
+	cfg := config{
+		DatabaseURL:     os.Getenv("DATABASE_URL"),
+		GatewayAddr:     getenv("GATEWAY_ADDR", ":8090"),
+		Reflection:      getenv("GRPC_REFLECTION", "false") == "true",
+		AdminToken:      os.Getenv("ADMIN_TOKEN"),
+		APIKeys:         apiKeys,
+		Sections:        strings.Split(getenv("SECTIONS", "A,B"), ","),
+		SeatsPerSection: getenvInt("SEATS_PER_SECTION", 20),
+		QuietSections:   strings.Split(os.Getenv("QUIET_SECTIONS"), ","),
+		HoldTTL:         getenvDuration("HOLD_TTL", 10*time.Minute),
+		Timetable:       timetable,
+		ScheduleDays:    getenvInt("SCHEDULE_DAYS", 14),
+
+		MinTransfer:   getenvDuration("MIN_TRANSFER", 10*time.Minute),
+		TransferTimes: transferTimes,
+		MaxTransfers:  getenvInt("MAX_TRANSFERS", 2),
+
+		FareBase:          fareBase,
+		FareSeatSelection: surcharges,
+		Currency:          getenv("FARE_CURRENCY", "GBP"),
+		FareTypes:         fareTypes,
+
+		CheckInOpens:  checkInOpens,
+		CheckInCloses: checkInCloses,
+		BoardingOpens: getenvDuration("BOARDING_OPENS", 30*time.Minute),
+
+		WaitlistClaimTTL: getenvDuration("WAITLIST_CLAIM_TTL", 30*time.Minute),
+
+		PaymentProvider:   getenv("PAYMENT_PROVIDER", "fake"),
+		PaymentTimeout:    getenvDuration("PAYMENT_TIMEOUT", 5*time.Second),
+		PaymentPendingTTL: getenvDuration("PAYMENT_PENDING_TTL", 15*time.Minute),
+		FakePaymentDelay:  getenvDuration("FAKE_PAYMENT_WEBHOOK_DELAY", 5*time.Second),
+
+		SMTPAddr:        os.Getenv("SMTP_ADDR"),
+		SMTPFrom:        getenv("SMTP_FROM", "Train Booking <tickets@localhost>"),
+		SMTPUsername:    os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:    os.Getenv("SMTP_PASSWORD"),
+		MailRetryBase:   getenvDuration("MAIL_RETRY_BASE", 30*time.Second),
+		MailMaxAttempts: getenvInt("MAIL_MAX_ATTEMPTS", 10),
+		ReminderBefore:  getenvDuration("REMINDER_BEFORE", 24*time.Hour),
+
+		EventSinks:         strings.Split(os.Getenv("EVENT_SINKS"), ","),
+		WebhookRetryBase:   getenvDuration("WEBHOOK_RETRY_BASE", 30*time.Second),
+		WebhookMaxAttempts: getenvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+
+		TicketKey: ticketKey,
+	}
+	if err := checkLayout(cfg.defaultLayout(), false); err != nil {
+		return config{}, fmt.Errorf("SECTIONS and SEATS_PER_SECTION: %s", status.Convert(err).Message())
+	}
+	return cfg, nil
+}
+
+// getenv returns the value of the environment variable key, or def if unset.
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getenvInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func getenvDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timetableEntry is one daily service, e.g. "T101 London Paris 08:00 2h20m".
+// Stops on the way come between the ends with their times after departure:
+// "T201 London Brussels+1h55m/2h Amsterdam 07:00 3h50m" reaches Brussels
+// 1h55m after leaving London and leaves it again at 2h.
+type timetableEntry struct {
+	Train    string
+	From, To string
+	Departs  time.Duration // offset from midnight UTC
+	Duration time.Duration
+	Calls    []timetableCall
+}
+
+// timetableCall is a stop between the ends of a service. Its times are
+// offsets from the service's departure.
+type timetableCall struct {
+	Code             string
+	Arrives, Departs time.Duration
+}
+
+func parseTimetable(spec string) ([]timetableEntry, error) {
+	var entries []timetableEntry
+	for _, line := range strings.Split(spec, ";") {
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		if len(f) < 5 {
+			return nil, fmt.Errorf("timetable entry %q: want \"TRAIN FROM [STOP+ARRIVAL[/DEPARTURE]...] TO HH:MM DURATION\"", line)
+		}
+		n := len(f)
+		at, err := time.Parse("15:04", f[n-2])
+		if err != nil {
+			return nil, fmt.Errorf("timetable entry %q: %v", line, err)
+		}
+		d, err := time.ParseDuration(f[n-1])
+		if err != nil {
+			return nil, fmt.Errorf("timetable entry %q: %v", line, err)
+		}
+		e := timetableEntry{
+			Train:    f[0],
+			From:     f[1],
+			To:       f[n-3],
+			Departs:  time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute,
+			Duration: d,
+		}
+		var last time.Duration
+		for _, stop := range f[2 : n-3] {
+			c, err := parseCall(stop)
+			if err != nil {
+				return nil, fmt.Errorf("timetable entry %q: %v", line, err)
+			}
+			if c.Arrives <= last {
+				return nil, fmt.Errorf("timetable entry %q: stop %s is not after the one before", line, c.Code)
+			}
+			e.Calls, last = append(e.Calls, c), c.Departs
+		}
+		if d <= last {
+			return nil, fmt.Errorf("timetable entry %q: %s is reached before the last stop on the way", line, e.To)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// parseCall reads a stop on the way, "STOP+ARRIVAL" or, when the train
+// waits there, "STOP+ARRIVAL/DEPARTURE".
+func parseCall(spec string) (timetableCall, error) {
+	code, times, ok := strings.Cut(spec, "+")
+	if !ok || code == "" {
+		return timetableCall{}, fmt.Errorf("stop %q: want STOP+ARRIVAL or STOP+ARRIVAL/DEPARTURE", spec)
+	}
+	arrives, departs, waits := strings.Cut(times, "/")
+	c := timetableCall{Code: code}
+	var err error
+	if c.Arrives, err = time.ParseDuration(arrives); err != nil {
+		return c, fmt.Errorf("stop %q: %v", spec, err)
+	}
+	c.Departs = c.Arrives
+	if waits {
+		if c.Departs, err = time.ParseDuration(departs); err != nil {
+			return c, fmt.Errorf("stop %q: %v", spec, err)
+		}
+	}
+	if c.Departs < c.Arrives {
+		return c, fmt.Errorf("stop %q: leaves before it arrives", spec)
+	}
+	return c, nil
+}
+
+// stopTime is a stop of one departure.
+type stopTime struct {
+	Code             string
+	Arrives, Departs time.Time
+}
+
+// stops lists the stops of the service leaving at departs, ends included.
+func (e timetableEntry) stops(departs time.Time) []stopTime {
+	stops := []stopTime{{Code: e.From, Arrives: departs, Departs: departs}}
+	for _, c := range e.Calls {
+		stops = append(stops, stopTime{Code: c.Code, Arrives: departs.Add(c.Arrives), Departs: departs.Add(c.Departs)})
+	}
+	arrives := departs.Add(e.Duration)
+	return append(stops, stopTime{Code: e.To, Arrives: arrives, Departs: arrives})
+}
+
+// seedDepartures creates the departures of the configured timetable for
+// today and the following ScheduleDays-1 days, plus their stops and seats. A
+// new departure gets the layout attached to its train, if any.
+func seedDepartures(db *sql.DB, cfg config, logger *slog.Logger) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for day := 0; day < cfg.ScheduleDays; day++ {
+		for _, e := range cfg.Timetable {
+			departs := today.AddDate(0, 0, day).Add(e.Departs)
+			_, err := tx.Exec(`INSERT INTO departures (train, from_code, to_code, departs_at, arrives_at, layout_name, layout_version)
+				VALUES ($1, $2, $3, $4, $5, (SELECT layout_name FROM train_layouts WHERE train = $1),
+					(SELECT layout_version FROM train_layouts WHERE train = $1))
+				ON CONFLICT (train, departs_at) DO NOTHING`,
+				e.Train, e.From, e.To, departs, departs.Add(e.Duration))
+			if err != nil {
+				return err
+			}
+			var id uint64
+			if err := tx.QueryRow("SELECT id FROM departures WHERE train = $1 AND departs_at = $2", e.Train, departs).Scan(&id); err != nil {
+				return err
+			}
+			if err := seedStops(tx, id, e, e.stops(departs), logger); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return seedSeats(db, cfg)
+}
+
+// seedStops gives departure id the stops of its timetable entry. When the
+// timetable has changed the stops of an existing departure they are
+// replaced, along with its route and seats, which seedSeats recreates for
+// the new legs. A departure with tickets, waitlist entries or held seats
+// keeps its old stops, since those refer to them by position, and a
+// warning is logged.
+func seedStops(tx *sql.Tx, id uint64, e timetableEntry, stops []stopTime, logger *slog.Logger) error {
+	rows, err := tx.Query("SELECT code, arrives_at, departs_at FROM departure_stops WHERE departure_id = $1 ORDER BY stop", id)
+	if err != nil {
+		return err
+	}
+	var have []stopTime
+	for rows.Next() {
+		var s stopTime
+		if err := rows.Scan(&s.Code, &s.Arrives, &s.Departs); err != nil {
+			rows.Close()
+			return err
+		}
+		have = append(have, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	same := func(a, b stopTime) bool {
+		return a.Code == b.Code && a.Arrives.Equal(b.Arrives) && a.Departs.Equal(b.Departs)
+	}
+	if slices.EqualFunc(have, stops, same) {
+		return nil
+	}
+
+	if len(have) > 0 {
+		var used bool
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tickets WHERE departure_id = $1)
+			OR EXISTS (SELECT 1 FROM waitlist WHERE departure_id = $1)
+			OR EXISTS (SELECT 1 FROM seats WHERE departure_id = $1 AND hold_id IS NOT NULL)`, id).Scan(&used)
+		if err != nil {
+			return err
+		}
+		if used {
+			logger.Warn("timetable changes the stops of a departure with bookings; keeping its old stops",
+				"departure_id", id, "train", e.Train, "departs_at", stops[0].Departs)
+			return nil
+		}
+		if _, err := tx.Exec("DELETE FROM departure_stops WHERE departure_id = $1", id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM seats WHERE departure_id = $1", id); err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE departures SET from_code = $1, to_code = $2, arrives_at = $3 WHERE id = $4",
+			e.From, e.To, stops[len(stops)-1].Arrives, id)
+		if err != nil {
+			return err
+		}
+		logger.Info("departure stops replaced from the timetable", "departure_id", id, "train", e.Train, "stops", len(stops))
+	}
+	for i, stop := range stops {
+		_, err := tx.Exec(`INSERT INTO departure_stops (departure_id, stop, code, arrives_at, departs_at)
+			VALUES ($1, $2, $3, $4, $5)`, id, i, stop.Code, stop.Arrives, stop.Departs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extendTimetable keeps the schedule ScheduleDays ahead while the server runs.
+func (s *TicketReservationServer) extendTimetable(logger *slog.Logger) {
+	for range time.Tick(time.Hour) {
+		s.mu.Lock()
+		err := seedDepartures(s.db, s.cfg, logger)
+		s.mu.Unlock()
+		if err != nil {
+			logger.Error("extending timetable failed", "error", err)
+		}
+	}
+}
+
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// callsAt matches stops a and b of a departure against the codes $1 and $2:
+// the train calls at a and later at b. Codes are compared exactly but for
+// case, so wildcards in them match nothing. An empty code matches the first
+// or the last stop.
+const callsAt = `b.departure_id = a.departure_id AND b.stop > a.stop
+	AND CASE WHEN $1 = '' THEN a.stop = 0 ELSE lower(a.code) = lower($1) END
+	AND CASE WHEN $2 = '' THEN NOT EXISTS (SELECT 1 FROM departure_stops l WHERE l.departure_id = b.departure_id AND l.stop > b.stop)
+		ELSE lower(b.code) = lower($2) END`
+
+// resolveRide returns the departure and the stops a booking from one
+// station to another travels between, as a seatRef without a seat. It
+// checks that departure id calls at both or, when id is zero, finds the
+// next departure that does. Empty codes match the ends of the run.
+func resolveRide(ctx context.Context, q rowQueryer, id uint64, from, to string) (seatRef, error) {
+	ride := seatRef{DepartureID: id}
+	var err error
+	if id != 0 {
+		err = q.QueryRowContext(ctx, `SELECT a.stop, b.stop FROM departure_stops a, departure_stops b
+			WHERE a.departure_id = $3 AND `+callsAt+` ORDER BY a.stop, b.stop LIMIT 1`, from, to, id).Scan(&ride.From, &ride.To)
+		if err == sql.ErrNoRows {
+			err = q.QueryRowContext(ctx, "SELECT id FROM departures WHERE id = $1", id).Scan(&id)
+			if err == nil {
+				return ride, status.Errorf(codes.InvalidArgument, "departure %d does not run from %q to %q", id, from, to)
+			}
+		}
+		if err == sql.ErrNoRows {
+			return ride, status.Errorf(codes.NotFound, "departure %d not found", id)
+		}
+	} else {
+		err = q.QueryRowContext(ctx, `SELECT a.departure_id, a.stop, b.stop FROM departure_stops a, departure_stops b
+			WHERE a.departs_at > now() AND `+callsAt+`
+			ORDER BY a.departs_at, b.stop LIMIT 1`, from, to).Scan(&ride.DepartureID, &ride.From, &ride.To)
+		if err == sql.ErrNoRows {
+			return ride, status.Errorf(codes.NotFound, "no upcoming departure from %q to %q", from, to)
+		}
+	}
+	if err != nil {
+		return ride, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return ride, nil
+}
+
+// stopCodes returns the codes of the stops a ride is between.
+func stopCodes(ctx context.Context, q rowQueryer, ride seatRef) (string, string, error) {
+	var from, to string
+	err := q.QueryRowContext(ctx, `SELECT a.code, b.code FROM departure_stops a, departure_stops b
+		WHERE a.departure_id = $1 AND a.stop = $2 AND b.departure_id = $1 AND b.stop = $3`,
+		ride.DepartureID, ride.From, ride.To).Scan(&from, &to)
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return from, to, nil
+}
+
+// freeBetween counts the seats of departure d that are free on every leg
+// from stop a to stop b.
+const freeBetween = `(SELECT count(*) FROM (SELECT 1 FROM seats
+	WHERE departure_id = d.id AND leg >= a.stop AND leg < b.stop AND ` + seatFree + `
+	GROUP BY section, seat HAVING count(*) = b.stop - a.stop) f)`
+
+func (s *TicketReservationServer) ListDepartures(ctx context.Context, req *pb.DeparturesRequest) (*pb.DepartureList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Date != "" {
+		if _, err := time.Parse(time.DateOnly, req.Date); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "date %q: want YYYY-MM-DD", req.Date)
+		}
+	}
+
+	// Trains are listed by when they leave the stop asked for, which may be
+	// after they left the first one.
+	rows, err := s.db.QueryContext(ctx, `SELECT d.id, d.train, a.code, b.code, a.departs_at, b.arrives_at, `+freeBetween+`,
+		(SELECT count(*) FROM seats WHERE departure_id = d.id AND leg = 0 AND NOT blocked)
+		FROM departures d JOIN departure_stops a ON a.departure_id = d.id, departure_stops b
+		WHERE a.departs_at > now() AND `+callsAt+`
+			AND ($3 = '' OR (a.departs_at AT TIME ZONE 'UTC')::date = $3::date)
+		ORDER BY a.departs_at LIMIT 100`, req.FromCode, req.ToCode, req.Date)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+
+	list := &pb.DepartureList{}
+	for rows.Next() {
+		var d pb.Departure
+		var departs, arrives time.Time
+		if err := rows.Scan(&d.DepartureId, &d.Train, &d.FromCode, &d.ToCode, &departs, &arrives, &d.SeatsFree, &d.Capacity); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		d.DepartsAt, d.ArrivesAt = timestamppb.New(departs), timestamppb.New(arrives)
+		list.Departures = append(list.Departures, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if err := addStops(ctx, s.db, list.Departures); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// addStops fills in the stops of the departures.
+func addStops(ctx context.Context, db *sql.DB, departures []*pb.Departure) error {
+	ids := make([]int64, len(departures))
+	byID := map[uint64][]*pb.Departure{}
+	for i, d := range departures {
+		ids[i] = int64(d.DepartureId)
+		byID[d.DepartureId] = append(byID[d.DepartureId], d)
+	}
+	rows, err := db.QueryContext(ctx, `SELECT departure_id, code, arrives_at, departs_at FROM departure_stops
+		WHERE departure_id = ANY($1) ORDER BY departure_id, stop`, pq.Array(ids))
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uint64
+		var arrives, departs time.Time
+		stop := &pb.Stop{}
+		if err := rows.Scan(&id, &stop.Code, &arrives, &departs); err != nil {
+			return status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		stop.ArrivesAt, stop.DepartsAt = timestamppb.New(arrives), timestamppb.New(departs)
+		for _, d := range byID[id] {
+			d.Stops = append(d.Stops, stop)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"rsc.io/qr"
+)
+
+// departureInfo is what tickets show about their departure. The times are
+// those at the stops the ticket is between.
+type departureInfo struct {
+	Train            string
+	Departs, Arrives time.Time
+}
+
+// departureInfo returns the departure of ticket ticketID; the zero value if
+// either no longer exists.
+func (s *TicketReservationServer) departureInfo(ctx context.Context, q rowQueryer, ticketID uint64) (departureInfo, error) {
+	var dep departureInfo
+	err := q.QueryRowContext(ctx, `SELECT d.train, a.departs_at, b.arrives_at
+		FROM tickets t JOIN departures d ON d.id = t.departure_id
+			JOIN departure_stops a ON a.departure_id = d.id AND a.stop = t.from_stop
+			JOIN departure_stops b ON b.departure_id = d.id AND b.stop = t.to_stop
+		WHERE t.id = $1`, ticketID).Scan(&dep.Train, &dep.Departs, &dep.Arrives)
+	if err != nil && err != sql.ErrNoRows {
+		return dep, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return dep, nil
+}
+
+func (s *TicketReservationServer) GetTicketDocument(ctx context.Context, req *pb.ReservationRequest) (*pb.TicketDocument, error) {
+	if req.TicketNo == nil {
+		return nil, status.Error(codes.InvalidArgument, "ID required")
+	}
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.loadTicket(ctx, *req.TicketNo)
+	if err != nil {
+		return nil, err
+	}
+	if owner != 0 && t.AccountId != owner {
+		return nil, status.Errorf(codes.NotFound, "ticket %d not found", *req.TicketNo)
+	}
+	if err := unpaid(t.TicketNo, t.Status); err != nil {
+		return nil, err
+	}
+	dep, err := s.departureInfo(ctx, s.db, t.TicketNo)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.cfg.renderTicket(t, dep)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "rendering ticket: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "ticket document rendered", "ticket_no", t.TicketNo, "bytes", len(content))
+	return &pb.TicketDocument{
+		Filename:    fmt.Sprintf("ticket-%d.pdf", t.TicketNo),
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
+
+// renderTicket lays out one A4 page per passenger: a ticket card with the
+// journey on the left and the QR code on the right, to be cut out along
+// the dashed line.
+func (c config) renderTicket(t *pb.ReservationResponse, dep departureInfo) ([]byte, error) {
+	var doc pdfDoc
+	for i, p := range t.Passengers {
+		code, err := qr.Encode(t.Tokens[i], qr.M)
+		if err != nil {
+			return nil, err
+		}
+
+		pg := doc.addPage()
+		const left, top, width, height = 40.0, 800.0, 515.0, 330.0
+		bottom := top - height
+		pg.strokeRect(left, bottom, width, height, 0.4)
+		pg.fillRect(left, top-50, width, 50, 0.17)
+		pg.text(left+20, top-32, 20, true, 1, "TRAIN TICKET")
+		pg.text(left+width-170, top-32, 14, false, 1, fmt.Sprintf("Ticket No %d", t.TicketNo))
+
+		name := p.FirstName
+		if p.LastName != "" {
+			name += " " + p.LastName
+		}
+		fields := [][2]string{
+			{"Passenger", name},
+			{"From", t.FromCode},
+			{"To", t.ToCode},
+		}
+		if dep.Train != "" {
+			fields = append(fields,
+				[2]string{"Train", dep.Train},
+				[2]string{"Departs", dep.Departs.UTC().Format("Mon 2 Jan 2006 15:04 MST")},
+				[2]string{"Arrives", dep.Arrives.UTC().Format("Mon 2 Jan 2006 15:04 MST")},
+			)
+		}
+		fields = append(fields,
+			[2]string{"Coach / Seat", fmt.Sprintf("%s / %d", p.Section, p.Seat)},
+			[2]string{"Status", t.Status},
+		)
+		if len(t.Passengers) > 1 {
+			fields = append(fields, [2]string{"Traveller", fmt.Sprintf("%d of %d", i+1, len(t.Passengers))})
+		}
+		y := top - 80
+		for _, f := range fields {
+			pg.text(left+20, y, 9, false, 0.45, f[0])
+			pg.text(left+110, y, 12, true, 0, clip(f[1], 28))
+			y -= 24
+		}
+
+		// The QR code, with the four-module quiet zone the standard asks for.
+		const qrSize = 190.0
+		module := qrSize / float64(code.Size+8)
+		qx, qy := left+width-qrSize-20, bottom+(height-50-qrSize)/2
+		for yy := 0; yy < code.Size; yy++ {
+			for xx := 0; xx < code.Size; xx++ {
+				if code.Black(xx, yy) {
+					pg.fillRect(qx+float64(xx+4)*module, qy+qrSize-float64(yy+5)*module, module, module, 0)
+				}
+			}
+		}
+
+		pg.text(left+20, bottom+16, 8, false, 0.45,
+			"Show this ticket when asked. It is valid only for the train, date and seat above.")
+		pg.dashedLine(20, bottom-20, pdfPageWidth-20, bottom-20)
+	}
+	return doc.bytes(), nil
+}
+
+// clip shortens s to n characters so it stays clear of the QR code.
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
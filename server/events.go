@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// broker fans booking events out to WatchEvents subscribers. It is
+// in-memory and best-effort: a subscriber that falls behind by more than its
+// buffer loses events rather than slowing down bookings.
+type broker struct {
+	mu   sync.Mutex
+	subs map[chan *pb.Event]uint64 // channel → departure filter, 0 for all
+}
+
+func newBroker() *broker {
+	return &broker{subs: map[chan *pb.Event]uint64{}}
+}
+
+func (b *broker) publish(typ string, ticketNo uint64, ref seatRef) {
+	e := &pb.Event{
+		Type:        typ,
+		DepartureId: ref.DepartureID,
+		TicketNo:    ticketNo,
+		Section:     ref.Section,
+		Seat:        ref.Seat,
+		At:          timestamppb.Now(),
+	}
+	b.send(e)
+}
+
+// send delivers e to every subscriber watching its departure.
+func (b *broker) send(e *pb.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, departureID := range b.subs {
+		if departureID != 0 && e.DepartureId != 0 && departureID != e.DepartureId {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func (b *broker) subscribe(departureID uint64) (<-chan *pb.Event, func()) {
+	ch := make(chan *pb.Event, 64)
+	b.mu.Lock()
+	b.subs[ch] = departureID
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+	}
+}
+
+func (s *TicketReservationServer) WatchEvents(req *pb.WatchRequest, stream pb.TicketReservation_WatchEventsServer) error {
+	ctx := stream.Context()
+	events, cancel := s.events.subscribe(req.DepartureId)
+	defer cancel()
+
+	logging.FromContext(ctx).InfoContext(ctx, "event watcher connected", "departure_id", req.DepartureId)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Payment tokens the fake provider understands. Any other token, including
+// none, is authorized straight away.
+const (
+	tokenDecline        = "tok_decline"
+	tokenTimeout        = "tok_timeout"
+	tokenDelayed        = "tok_delayed"
+	tokenDelayedDecline = "tok_delayed_decline"
+)
+
+// fakePayments is a PaymentProvider that runs entirely in the server, for
+// development and testing. The payment token picks the outcome: a decline,
+// a provider that never answers, or a pending authorization settled by a
+// webhook after delay.
+type fakePayments struct {
+	delay time.Duration
+	hook  PaymentWebhook
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	orders   map[string]string // order ID to reference, so retries are idempotent
+	next     int
+}
+
+type fakePayment struct {
+	amount   uint64
+	status   string
+	refunded uint64
+}
+
+func newFakePayments(delay time.Duration, hook PaymentWebhook) *fakePayments {
+	return &fakePayments{
+		delay:    delay,
+		hook:     hook,
+		payments: map[string]*fakePayment{},
+		orders:   map[string]string{},
+	}
+}
+
+func (f *fakePayments) Name() string { return "fake" }
+
+func (f *fakePayments) Authorize(ctx context.Context, req PaymentRequest) (PaymentResult, error) {
+	if req.Token == tokenTimeout {
+		<-ctx.Done()
+		return PaymentResult{}, ctx.Err()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ref, ok := f.orders[req.OrderID]; ok && f.payments[ref].status != paymentVoided {
+		return PaymentResult{Reference: ref, Status: f.payments[ref].status}, nil
+	}
+	f.next++
+	ref := fmt.Sprintf("fake_%06d", f.next)
+	f.orders[req.OrderID] = ref
+	p := &fakePayment{amount: req.Amount, status: paymentAuthorized}
+	f.payments[ref] = p
+
+	switch req.Token {
+	case tokenDecline:
+		p.status = paymentDeclined
+		return PaymentResult{Reference: ref, Status: paymentDeclined, Reason: "card declined"}, nil
+	case tokenDelayed, tokenDelayedDecline:
+		p.status = paymentPending
+		authorized := req.Token == tokenDelayed
+		time.AfterFunc(f.delay, func() { f.settle(ref, authorized) })
+		return PaymentResult{Reference: ref, Status: paymentPending}, nil
+	}
+	return PaymentResult{Reference: ref, Status: paymentAuthorized}, nil
+}
+
+// settle decides a pending authorization and delivers the webhook, unless
+// the payment was voided meanwhile.
+func (f *fakePayments) settle(ref string, authorized bool) {
+	f.mu.Lock()
+	p := f.payments[ref]
+	if p.status != paymentPending {
+		f.mu.Unlock()
+		return
+	}
+	reason := ""
+	if authorized {
+		p.status = paymentAuthorized
+	} else {
+		p.status, reason = paymentDeclined, "card declined"
+	}
+	f.mu.Unlock()
+	f.hook(ref, authorized, reason)
+}
+
+func (f *fakePayments) Capture(ctx context.Context, ref string, amount uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payments[ref]
+	switch {
+	case !ok:
+		return fmt.Errorf("payment %s not found", ref)
+	case p.status == paymentCaptured:
+		return nil
+	case p.status != paymentAuthorized:
+		return fmt.Errorf("payment %s is %s, not authorized", ref, p.status)
+	case amount > p.amount:
+		return fmt.Errorf("payment %s: capture of %d exceeds authorized %d", ref, amount, p.amount)
+	}
+	p.status, p.amount = paymentCaptured, amount
+	return nil
+}
+
+func (f *fakePayments) Void(ctx context.Context, ref string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payments[ref]
+	if !ok {
+		return fmt.Errorf("payment %s not found", ref)
+	}
+	switch p.status {
+	case paymentAuthorized, paymentPending, paymentCaptured:
+		p.status = paymentVoided
+	case paymentDeclined, paymentVoided:
+	default:
+		return fmt.Errorf("payment %s is %s and cannot be voided", ref, p.status)
+	}
+	return nil
+}
+
+func (f *fakePayments) Refund(ctx context.Context, ref string, amount uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payments[ref]
+	switch {
+	case !ok:
+		return fmt.Errorf("payment %s not found", ref)
+	case p.status != paymentCaptured && p.status != paymentRefunded:
+		return fmt.Errorf("payment %s is %s and cannot be refunded", ref, p.status)
+	case p.refunded+amount > p.amount:
+		return fmt.Errorf("payment %s: refund of %d exceeds captured %d", ref, amount, p.amount-p.refunded)
+	}
+	p.refunded += amount
+	p.status = paymentRefunded
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseSurcharges reads FARE_SEAT_SELECTION, e.g. "A=1500,B=500".
+func parseSurcharges(spec string) (map[string]uint64, error) {
+	m := map[string]uint64{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		section, amount, ok := strings.Cut(entry, "=")
+		n, err := strconv.ParseUint(strings.TrimSpace(amount), 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("seat selection fare %q: want SECTION=AMOUNT", entry)
+		}
+		m[strings.TrimSpace(section)] = n
+	}
+	return m, nil
+}
+
+// standardFare is the fare type booked when a request names none.
+const standardFare = "standard"
+
+const (
+	defaultFareTypes          = "flexible=6500,saver=3000"
+	defaultCancellationPolicy = "standard=100%@24h,50%@2h; flexible=100%@0s; saver=none"
+)
+
+// fareType is a kind of fare with its own price per passenger and
+// cancellation policy.
+type fareType struct {
+	Name   string
+	Base   uint64
+	Policy cancellationPolicy
+}
+
+// parseFareTypes reads FARE_TYPES, e.g. "flexible=6500,saver=3000", and
+// CANCELLATION_POLICY. The standard fare costs base and needs no entry in
+// FARE_TYPES, but every fare type needs a policy.
+func parseFareTypes(base uint64, prices, policies string) (map[string]fareType, error) {
+	types := map[string]fareType{standardFare: {Name: standardFare, Base: base}}
+	for _, entry := range strings.Split(prices, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, amount, ok := strings.Cut(entry, "=")
+		n, err := strconv.ParseUint(strings.TrimSpace(amount), 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("fare type %q: want NAME=AMOUNT", entry)
+		}
+		name = strings.TrimSpace(name)
+		types[name] = fareType{Name: name, Base: n}
+	}
+	for _, entry := range strings.Split(policies, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, _ := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		ft, ok := types[name]
+		if !ok {
+			return nil, fmt.Errorf("cancellation policy for unknown fare type %q", name)
+		}
+		policy, err := parsePolicy(spec)
+		if err != nil {
+			return nil, fmt.Errorf("cancellation policy for %s: %w", name, err)
+		}
+		ft.Policy = policy
+		types[name] = ft
+	}
+	for name, ft := range types {
+		if ft.Policy == nil {
+			return nil, fmt.Errorf("fare type %s has no cancellation policy in CANCELLATION_POLICY", name)
+		}
+	}
+	return types, nil
+}
+
+// fareType returns the fare type a request asks for.
+func (c config) fareType(name string) (fareType, error) {
+	if name == "" {
+		name = standardFare
+	}
+	ft, ok := c.FareTypes[name]
+	if !ok {
+		return fareType{}, status.Errorf(codes.InvalidArgument, "unknown fare type %q", name)
+	}
+	return ft, nil
+}
+
+// fare prices a booking of passengers seats of fare type ft on departureID.
+// chosen holds the section of every passenger who picked their own seat.
+func (c config) fare(departureID uint64, ft fareType, passengers int, chosen []string) *pb.Fare {
+	f := &pb.Fare{DepartureId: departureID, Currency: c.Currency, FareType: ft.Name}
+	desc := fmt.Sprintf("%d × %s", passengers, formatAmount(ft.Base, c.Currency))
+	if ft.Name != standardFare {
+		desc += " (" + ft.Name + ")"
+	}
+	f.Lines = append(f.Lines, &pb.Fare_Line{Description: desc, Amount: uint64(passengers) * ft.Base})
+	for _, section := range chosen {
+		if amount := c.FareSeatSelection[section]; amount != 0 {
+			f.Lines = append(f.Lines, &pb.Fare_Line{Description: "Seat selection, section " + section, Amount: amount})
+		}
+	}
+	for _, l := range f.Lines {
+		f.Total += l.Amount
+	}
+	return f
+}
+
+// choosesSeat reports whether passenger i of req picked a seat rather than
+// leaving it to the server. A hold always belongs to the lead passenger.
+func choosesSeat(req *pb.ReservationRequest, i int) bool {
+	p := req.Passengers[i]
+	return (i == 0 && req.HoldId != "") || (p.Section != "" && p.Seat != 0)
+}
+
+// chosenSections returns the sections of the seats passengers picked
+// themselves, given the seats they were allocated.
+func chosenSections(req *pb.ReservationRequest, seats []seatRef) []string {
+	var chosen []string
+	for i := range seats {
+		if choosesSeat(req, i) {
+			chosen = append(chosen, seats[i].Section)
+		}
+	}
+	return chosen
+}
+
+// formatAmount renders an amount in minor units, e.g. "GBP 45.00".
+func formatAmount(amount uint64, currency string) string {
+	return fmt.Sprintf("%s %d.%02d", currency, amount/100, amount%100)
+}
+
+func (s *TicketReservationServer) QuoteFare(ctx context.Context, req *pb.ReservationRequest) (*pb.Fare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(req.Passengers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one passenger required")
+	}
+	if len(req.Passengers) > maxPassengers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d passengers per booking", maxPassengers)
+	}
+
+	ft, err := s.cfg.fareType(req.FareType)
+	if err != nil {
+		return nil, err
+	}
+
+	var departureID uint64
+	var holdSection string
+	if req.HoldId != "" {
+		err = s.db.QueryRowContext(ctx, "SELECT departure_id, section FROM seats WHERE hold_id = $1 AND held_until > now() LIMIT 1",
+			req.HoldId).Scan(&departureID, &holdSection)
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "hold %s is unknown or has expired", req.HoldId)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+		}
+	} else {
+		ride, err := resolveRide(ctx, s.db, req.DepartureId, req.FromCode, req.ToCode)
+		if err != nil {
+			return nil, err
+		}
+		departureID = ride.DepartureID
+	}
+
+	var chosen []string
+	for i, p := range req.Passengers {
+		switch {
+		case i == 0 && req.HoldId != "":
+			chosen = append(chosen, holdSection)
+		case choosesSeat(req, i):
+			chosen = append(chosen, p.Section)
+		}
+	}
+	return s.cfg.fare(departureID, ft, len(req.Passengers), chosen), nil
+}
@@ -0,0 +1,482 @@
+package main
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//go:embed openapi.json
+var openAPIDoc []byte
+
+var (
+	jsonIn  = protojson.UnmarshalOptions{DiscardUnknown: true}
+	jsonOut = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+// gateway maps REST/JSON calls onto the TicketReservation service. It talks
+// to the gRPC server over loopback so REST traffic goes through the same
+// interceptors (logging, request IDs, API keys) as native gRPC clients.
+type gateway struct {
+	client pb.TicketReservationClient
+	keys   map[string]apiKey
+}
+
+func newGateway(grpcAddr string, keys map[string]apiKey) (http.Handler, error) {
+	conn, err := grpc.NewClient(grpcAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(logging.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	g := &gateway{client: pb.NewTicketReservationClient(conn), keys: keys}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/tickets", g.reserve)
+	mux.HandleFunc("GET /v1/tickets", g.list)
+	mux.HandleFunc("GET /v1/tickets/{ticket_no}", g.get)
+	mux.HandleFunc("GET /v1/tickets/{ticket_no}/document", g.document)
+	mux.HandleFunc("PATCH /v1/tickets/{ticket_no}", g.modify)
+	mux.HandleFunc("DELETE /v1/tickets/{ticket_no}", g.cancel)
+	mux.HandleFunc("GET /v1/tickets/{ticket_no}/cancellation", g.quoteCancellation)
+	mux.HandleFunc("GET /v1/seats", g.seatMap)
+	mux.HandleFunc("POST /v1/tickets/{ticket_no}/checkin", g.checkIn)
+	mux.HandleFunc("POST /v1/tickets/{ticket_no}/board", g.board)
+	mux.HandleFunc("GET /v1/departures", g.departures)
+	mux.HandleFunc("GET /v1/departures/{departure_id}/manifest", g.manifest)
+	mux.HandleFunc("GET /v1/journeys", g.planJourneys)
+	mux.HandleFunc("POST /v1/journeys", g.reserveJourney)
+	mux.HandleFunc("POST /v1/fares/quote", g.quoteFare)
+	mux.HandleFunc("POST /v1/tickets/validate", g.validate)
+	mux.HandleFunc("POST /v1/waitlist", g.joinWaitlist)
+	mux.HandleFunc("GET /v1/waitlist", g.listWaitlist)
+	mux.HandleFunc("POST /v1/waitlist/{waitlist_id}/claim", g.claimWaitlist)
+	mux.HandleFunc("DELETE /v1/waitlist/{waitlist_id}", g.leaveWaitlist)
+
+	root := http.NewServeMux()
+	root.Handle("/v1/", g.authenticate(mux))
+	root.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPIDoc)
+	})
+	return root, nil
+}
+
+// authenticate requires the partner's API key as "Authorization: Bearer
+// <key>" and forwards it, so the call is made as the key's principal. The
+// web bridge's key is not accepted here: it could name any account.
+func (g *gateway) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || got == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, r, status.Error(codes.Unauthenticated, "API key required"))
+			return
+		}
+		known := false
+		for key, k := range g.keys {
+			if subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1 && !k.Bridge {
+				known = true
+			}
+		}
+		if !known {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, r, status.Error(codes.Unauthenticated, "invalid API key"))
+			return
+		}
+		ctx := metadata.AppendToOutgoingContext(r.Context(), logging.APIKeyKey, got)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (g *gateway) reserve(w http.ResponseWriter, r *http.Request) {
+	req := &pb.ReservationRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	ctx := r.Context()
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, idempotencyMDKey, key)
+	}
+	resp, err := g.client.ReserveTicket(ctx, req)
+	writeProto(w, r, http.StatusCreated, resp, err)
+}
+
+// list serves GetAllTickets, or SearchTickets when any of the name, email,
+// section or status query parameters is present.
+func (g *gateway) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := &pb.SearchRequest{
+		Name:    q.Get("name"),
+		Email:   q.Get("email"),
+		Section: q.Get("section"),
+		Status:  q.Get("status"),
+	}
+	var (
+		resp *pb.AllTicketsResponse
+		err  error
+	)
+	if proto.Equal(search, &pb.SearchRequest{}) {
+		resp, err = g.client.GetAllTickets(r.Context(), &pb.EmptyRequest{})
+	} else {
+		resp, err = g.client.SearchTickets(r.Context(), search)
+	}
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) seatMap(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &pb.SeatMapRequest{Section: q.Get("section"), FromCode: q.Get("from_code"), ToCode: q.Get("to_code")}
+	if v := q.Get("departure_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeError(w, r, status.Error(codes.InvalidArgument, "departure_id must be a positive integer"))
+			return
+		}
+		req.DepartureId = id
+	}
+	resp, err := g.client.GetSeatMap(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) departures(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	resp, err := g.client.ListDepartures(r.Context(), &pb.DeparturesRequest{FromCode: q.Get("from_code"), ToCode: q.Get("to_code"), Date: q.Get("date")})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+// planJourneys reads departs_after as RFC 3339 and sort by name, e.g.
+// FEWEST_CHANGES.
+func (g *gateway) planJourneys(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &pb.JourneyRequest{FromCode: q.Get("from_code"), ToCode: q.Get("to_code")}
+	if v := q.Get("departs_after"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, r, status.Error(codes.InvalidArgument, "departs_after must be an RFC 3339 time"))
+			return
+		}
+		req.DepartsAfter = timestamppb.New(t)
+	}
+	if v := q.Get("sort"); v != "" {
+		by, ok := pb.JourneyRequest_Sort_value[v]
+		if !ok {
+			writeError(w, r, status.Error(codes.InvalidArgument, "sort must be EARLIEST_ARRIVAL or FEWEST_CHANGES"))
+			return
+		}
+		req.Sort = pb.JourneyRequest_Sort(by)
+	}
+	for _, f := range []struct {
+		name string
+		set  func(uint32)
+	}{
+		{"max_transfers", func(n uint32) { req.MaxTransfers = &n }},
+		{"passengers", func(n uint32) { req.Passengers = n }},
+		{"limit", func(n uint32) { req.Limit = n }},
+	} {
+		if v := q.Get(f.name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				writeError(w, r, status.Errorf(codes.InvalidArgument, "%s must be a non-negative integer", f.name))
+				return
+			}
+			f.set(uint32(n))
+		}
+	}
+	resp, err := g.client.PlanJourneys(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) reserveJourney(w http.ResponseWriter, r *http.Request) {
+	req := &pb.JourneyReservationRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	ctx := r.Context()
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, idempotencyMDKey, key)
+	}
+	resp, err := g.client.ReserveJourney(ctx, req)
+	writeProto(w, r, http.StatusCreated, resp, err)
+}
+
+func (g *gateway) quoteFare(w http.ResponseWriter, r *http.Request) {
+	req := &pb.ReservationRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	resp, err := g.client.QuoteFare(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) validate(w http.ResponseWriter, r *http.Request) {
+	req := &pb.ValidateTicketRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	resp, err := g.client.ValidateTicket(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) get(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	resp, err := g.client.GetTicket(r.Context(), &pb.ReservationRequest{TicketNo: &tNo})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+// document serves the PDF itself rather than its JSON wrapping.
+func (g *gateway) document(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	doc, err := g.client.GetTicketDocument(r.Context(), &pb.ReservationRequest{TicketNo: &tNo})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Filename))
+	w.Write(doc.Content)
+}
+
+func (g *gateway) modify(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	req := &pb.ReservationRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	req.TicketNo = &tNo
+	resp, err := g.client.ModifyTicket(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) cancel(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	resp, err := g.client.CancelTicket(r.Context(), &pb.ReservationRequest{TicketNo: &tNo})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) quoteCancellation(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	resp, err := g.client.QuoteCancellation(r.Context(), &pb.ReservationRequest{TicketNo: &tNo})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) checkIn(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	resp, err := g.client.CheckIn(r.Context(), &pb.ReservationRequest{TicketNo: &tNo})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+// board takes the passenger position from the body; an empty body boards
+// the lead passenger.
+func (g *gateway) board(w http.ResponseWriter, r *http.Request) {
+	tNo, ok := pathTicketNo(w, r)
+	if !ok {
+		return
+	}
+	req := &pb.BoardRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	req.TicketNo = tNo
+	resp, err := g.client.Board(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) manifest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("departure_id"), 10, 64)
+	if err != nil || id == 0 {
+		writeError(w, r, status.Error(codes.InvalidArgument, "departure_id must be a positive integer"))
+		return
+	}
+	resp, err := g.client.GetBoardingManifest(r.Context(), &pb.ManifestRequest{DepartureId: id})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func (g *gateway) joinWaitlist(w http.ResponseWriter, r *http.Request) {
+	req := &pb.JoinWaitlistRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	resp, err := g.client.JoinWaitlist(r.Context(), req)
+	writeProto(w, r, http.StatusCreated, resp, err)
+}
+
+func (g *gateway) listWaitlist(w http.ResponseWriter, r *http.Request) {
+	req := &pb.WaitlistQuery{}
+	if v := r.URL.Query().Get("departure_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeError(w, r, status.Error(codes.InvalidArgument, "departure_id must be a positive integer"))
+			return
+		}
+		req.DepartureId = id
+	}
+	resp, err := g.client.ListWaitlist(r.Context(), req)
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+// claimWaitlist takes the expected fare and payment token from the body;
+// an empty body accepts the current fare.
+func (g *gateway) claimWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathWaitlistID(w, r)
+	if !ok {
+		return
+	}
+	req := &pb.ClaimWaitlistRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+	req.WaitlistId = id
+	resp, err := g.client.ClaimWaitlist(r.Context(), req)
+	writeProto(w, r, http.StatusCreated, resp, err)
+}
+
+func (g *gateway) leaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathWaitlistID(w, r)
+	if !ok {
+		return
+	}
+	resp, err := g.client.LeaveWaitlist(r.Context(), &pb.WaitlistQuery{WaitlistId: id})
+	writeProto(w, r, http.StatusOK, resp, err)
+}
+
+func pathWaitlistID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(r.PathValue("waitlist_id"), 10, 64)
+	if err != nil || id == 0 {
+		writeError(w, r, status.Error(codes.InvalidArgument, "waitlist_id must be a positive integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+func pathTicketNo(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	tNo, err := strconv.ParseUint(r.PathValue("ticket_no"), 10, 64)
+	if err != nil || tNo == 0 {
+		writeError(w, r, status.Error(codes.InvalidArgument, "ticket_no must be a positive integer"))
+		return 0, false
+	}
+	return tNo, true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, m proto.Message) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err == nil && len(body) > 0 {
+		err = jsonIn.Unmarshal(body, m)
+	}
+	if err != nil {
+		writeError(w, r, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeProto(w http.ResponseWriter, r *http.Request, code int, m proto.Message, err error) {
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	b, err := jsonOut.Marshal(m)
+	if err != nil {
+		writeError(w, r, status.Errorf(codes.Internal, "encode response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(b)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	code := httpStatus(st.Code())
+	if code >= 500 {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "gateway call failed", "error", st.Message())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{
+		"code":    int(st.Code()),
+		"status":  st.Code().String(),
+		"message": st.Message(),
+	})
+}
+
+// httpStatus follows the canonical gRPC → HTTP mapping from
+// google/rpc/code.proto.
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func serveGateway(addr, grpcAddr string, keys map[string]apiKey, logger *slog.Logger) {
+	h, err := newGateway(grpcAddr, keys)
+	if err != nil {
+		fatal("failed to create REST gateway", err)
+	}
+	logger.Info("REST gateway listening", "addr", addr)
+	if err := http.ListenAndServe(addr, logging.Middleware(logger, h)); err != nil {
+		fatal("REST gateway stopped", err)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// idempotencyMDKey is the gRPC metadata key (and, via the gateway, the
+// Idempotency-Key HTTP header) that makes ReserveTicket and ReserveJourney
+// safe to retry.
+const idempotencyMDKey = "idempotency-key"
+
+func idempotencyKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(idempotencyMDKey); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// replay returns the ticket previously booked under key, or nil if the key
+// is new.
+func (s *TicketReservationServer) replay(ctx context.Context, key string) (*pb.ReservationResponse, error) {
+	var ticketID, journeyID sql.NullInt64
+	err := s.db.QueryRowContext(ctx, "SELECT ticket_id, journey_id FROM idempotency_keys WHERE key = $1", key).Scan(&ticketID, &journeyID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if journeyID.Valid {
+		return nil, status.Errorf(codes.AlreadyExists, "idempotency key %q was used for journey %d", key, journeyID.Int64)
+	}
+	if !ticketID.Valid {
+		return nil, status.Errorf(codes.AlreadyExists, "idempotency key %q was used for a ticket that has since been cancelled", key)
+	}
+	return s.loadTicket(ctx, uint64(ticketID.Int64))
+}
+
+// replayJourney returns the journey previously booked under key, or nil if
+// the key is new.
+func (s *TicketReservationServer) replayJourney(ctx context.Context, key string) (*pb.JourneyReservation, error) {
+	var journeyID sql.NullInt64
+	err := s.db.QueryRowContext(ctx, "SELECT journey_id FROM idempotency_keys WHERE key = $1", key).Scan(&journeyID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if !journeyID.Valid {
+		return nil, status.Errorf(codes.AlreadyExists, "idempotency key %q was used for a ticket, not a journey", key)
+	}
+	return s.loadJourney(ctx, uint64(journeyID.Int64))
+}
+
+func rememberKey(ctx context.Context, tx *sql.Tx, key string, ticketID uint64) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO idempotency_keys (key, ticket_id) VALUES ($1, $2)", key, ticketID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	return nil
+}
+
+func rememberJourneyKey(ctx context.Context, tx *sql.Tx, key string, journeyID uint64) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO idempotency_keys (key, journey_id) VALUES ($1, $2)", key, journeyID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	return nil
+}
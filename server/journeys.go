@@ -0,0 +1,482 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// journeyWindow is how long after the requested time PlanJourneys looks
+// for trains to take.
+const journeyWindow = 24 * time.Hour
+
+const (
+	defaultJourneys = 5
+	maxJourneys     = 20
+)
+
+// parseTransferTimes reads TRANSFER_TIMES, e.g. "Paris=45m,Brussels=15m".
+// Stations are matched without regard to case.
+func parseTransferTimes(spec string) (map[string]time.Duration, error) {
+	m := map[string]time.Duration{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		station, d, ok := strings.Cut(entry, "=")
+		t, err := time.ParseDuration(strings.TrimSpace(d))
+		if !ok || err != nil || t < 0 {
+			return nil, fmt.Errorf("transfer time %q: want STATION=DURATION", entry)
+		}
+		m[strings.ToLower(strings.TrimSpace(station))] = t
+	}
+	return m, nil
+}
+
+// transferTime is the least time to allow for changing trains at station.
+func (c config) transferTime(station string) time.Duration {
+	if d, ok := c.TransferTimes[strings.ToLower(station)]; ok {
+		return d
+	}
+	return c.MinTransfer
+}
+
+// trip is a departure with its stops, in order, as the planner sees it.
+type trip struct {
+	ID    uint64
+	Train string
+	Stops []stopTime
+}
+
+// journeyLeg is the part of a trip ridden, from stop From to stop To.
+type journeyLeg struct {
+	Trip     *trip
+	From, To int
+}
+
+// journey is the legs of an itinerary in travel order.
+type journey []journeyLeg
+
+func (j journey) departs() time.Time { return j[0].Trip.Stops[j[0].From].Departs }
+
+func (j journey) arrives() time.Time {
+	last := j[len(j)-1]
+	return last.Trip.Stops[last.To].Arrives
+}
+
+func (j journey) changes() int { return len(j) - 1 }
+
+// dominates reports whether j is as good as other on every count: leaving
+// no earlier, arriving no later and changing no more often.
+func (j journey) dominates(other journey) bool {
+	return !j.departs().Before(other.departs()) && !j.arrives().After(other.arrives()) && j.changes() <= other.changes()
+}
+
+// loadTrips reads the departures that leave any of their stops between
+// after and until.
+func loadTrips(ctx context.Context, db *sql.DB, after, until time.Time) ([]*trip, error) {
+	rows, err := db.QueryContext(ctx, `SELECT d.id, d.train, s.code, s.arrives_at, s.departs_at
+		FROM departures d JOIN departure_stops s ON s.departure_id = d.id
+		WHERE d.id IN (SELECT departure_id FROM departure_stops WHERE departs_at >= $1 AND departs_at < $2)
+		ORDER BY d.id, s.stop`, after, until)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	var trips []*trip
+	for rows.Next() {
+		var id uint64
+		var train string
+		var stop stopTime
+		if err := rows.Scan(&id, &train, &stop.Code, &stop.Arrives, &stop.Departs); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		if len(trips) == 0 || trips[len(trips)-1].ID != id {
+			trips = append(trips, &trip{ID: id, Train: train})
+		}
+		t := trips[len(trips)-1]
+		t.Stops = append(t.Stops, stop)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return trips, nil
+}
+
+// planJourneys finds the journeys from one station to another on trips,
+// boarding between after and until and changing at most maxChanges times,
+// with transfer(station) to spare at each change. A journey never calls at
+// a station twice, and at a change only the first run of each train is
+// taken. Journeys that another leaves no earlier than, arrives no later
+// than and changes no more often than are left out.
+func planJourneys(trips []*trip, from, to string, after, until time.Time, maxChanges int, transfer func(string) time.Duration) []journey {
+	var found []journey
+	seen := map[string]bool{strings.ToLower(from): true}
+
+	var walk func(at string, ready time.Time, path journey)
+	walk = func(at string, ready time.Time, path journey) {
+		var boardings []journeyLeg
+		for _, t := range trips {
+			if slices.ContainsFunc(path, func(l journeyLeg) bool { return l.Trip == t }) {
+				continue
+			}
+			for i, stop := range t.Stops[:len(t.Stops)-1] {
+				if strings.EqualFold(stop.Code, at) {
+					if !stop.Departs.Before(ready) && stop.Departs.Before(until) {
+						boardings = append(boardings, journeyLeg{Trip: t, From: i})
+					}
+					break
+				}
+			}
+		}
+		slices.SortStableFunc(boardings, func(a, b journeyLeg) int {
+			return a.Trip.Stops[a.From].Departs.Compare(b.Trip.Stops[b.From].Departs)
+		})
+
+		trains := map[string]bool{}
+		for _, leg := range boardings {
+			if len(path) > 0 {
+				if trains[leg.Trip.Train] {
+					continue
+				}
+				trains[leg.Trip.Train] = true
+			}
+			for j := leg.From + 1; j < len(leg.Trip.Stops); j++ {
+				leg.To = j
+				stop := leg.Trip.Stops[j]
+				code := strings.ToLower(stop.Code)
+				if code == strings.ToLower(to) {
+					found = append(found, append(slices.Clone(path), leg))
+					break
+				}
+				if seen[code] || len(path) >= maxChanges {
+					continue
+				}
+				seen[code] = true
+				walk(stop.Code, stop.Arrives.Add(transfer(stop.Code)), append(slices.Clone(path), leg))
+				delete(seen, code)
+			}
+		}
+	}
+	walk(from, after, nil)
+
+	var kept []journey
+	for i, j := range found {
+		dominated := slices.ContainsFunc(found, func(other journey) bool {
+			return other.dominates(j) && !j.dominates(other)
+		})
+		// Of journeys as good as each other, the first found is kept.
+		for k := 0; k < i && !dominated; k++ {
+			dominated = found[k].dominates(j) && j.dominates(found[k])
+		}
+		if !dominated {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
+
+// sortJourneys orders journeys by arrival, then changes, then the latest
+// departure, or with FEWEST_CHANGES by changes first.
+func sortJourneys(journeys []journey, by pb.JourneyRequest_Sort) {
+	slices.SortStableFunc(journeys, func(a, b journey) int {
+		byArrival := cmp.Or(a.arrives().Compare(b.arrives()), cmp.Compare(a.changes(), b.changes()), b.departs().Compare(a.departs()))
+		if by == pb.JourneyRequest_FEWEST_CHANGES {
+			return cmp.Or(cmp.Compare(a.changes(), b.changes()), byArrival)
+		}
+		return byArrival
+	})
+}
+
+// legDeparture describes a leg as ListDepartures describes a departure
+// between two of its stops.
+func legDeparture(ctx context.Context, q rowQueryer, l journeyLeg) (*pb.Departure, error) {
+	board, alight := l.Trip.Stops[l.From], l.Trip.Stops[l.To]
+	d := &pb.Departure{
+		DepartureId: l.Trip.ID,
+		Train:       l.Trip.Train,
+		FromCode:    board.Code,
+		ToCode:      alight.Code,
+		DepartsAt:   timestamppb.New(board.Departs),
+		ArrivesAt:   timestamppb.New(alight.Arrives),
+	}
+	for _, stop := range l.Trip.Stops {
+		d.Stops = append(d.Stops, &pb.Stop{Code: stop.Code, ArrivesAt: timestamppb.New(stop.Arrives), DepartsAt: timestamppb.New(stop.Departs)})
+	}
+	err := q.QueryRowContext(ctx, `SELECT `+freeBetween+`,
+		(SELECT count(*) FROM seats WHERE departure_id = d.id AND leg = 0 AND NOT blocked)
+		FROM departures d, departure_stops a, departure_stops b
+		WHERE d.id = $1 AND a.departure_id = d.id AND a.stop = $2 AND b.departure_id = d.id AND b.stop = $3`,
+		l.Trip.ID, l.From, l.To).Scan(&d.SeatsFree, &d.Capacity)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return d, nil
+}
+
+func (s *TicketReservationServer) PlanJourneys(ctx context.Context, req *pb.JourneyRequest) (*pb.JourneyList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.FromCode == "" || req.ToCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "from_code and to_code required")
+	}
+	if strings.EqualFold(req.FromCode, req.ToCode) {
+		return nil, status.Error(codes.InvalidArgument, "from_code and to_code are the same station")
+	}
+	maxChanges := s.cfg.MaxTransfers
+	if req.MaxTransfers != nil {
+		if int(*req.MaxTransfers) > s.cfg.MaxTransfers {
+			return nil, status.Errorf(codes.InvalidArgument, "at most %d changes", s.cfg.MaxTransfers)
+		}
+		maxChanges = int(*req.MaxTransfers)
+	}
+	passengers := max(req.Passengers, 1)
+	if passengers > maxPassengers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d passengers per booking", maxPassengers)
+	}
+	limit := defaultJourneys
+	if req.Limit != 0 {
+		limit = min(int(req.Limit), maxJourneys)
+	}
+	after := time.Now()
+	if req.DepartsAfter != nil && req.DepartsAfter.AsTime().After(after) {
+		after = req.DepartsAfter.AsTime()
+	}
+
+	trips, err := loadTrips(ctx, s.db, after, after.Add(journeyWindow))
+	if err != nil {
+		return nil, err
+	}
+	found := planJourneys(trips, req.FromCode, req.ToCode, after, after.Add(journeyWindow), maxChanges, s.cfg.transferTime)
+	sortJourneys(found, req.Sort)
+
+	// Journeys are given in order until limit of them have the seats.
+	list := &pb.JourneyList{}
+	legs := map[journeyLeg]*pb.Departure{}
+	for _, j := range found {
+		if len(list.Journeys) == limit {
+			break
+		}
+		out := &pb.Journey{DepartsAt: timestamppb.New(j.departs()), ArrivesAt: timestamppb.New(j.arrives()), Changes: uint32(j.changes())}
+		for _, l := range j {
+			d, ok := legs[l]
+			if !ok {
+				if d, err = legDeparture(ctx, s.db, l); err != nil {
+					return nil, err
+				}
+				legs[l] = d
+			}
+			if d.SeatsFree < passengers {
+				out = nil
+				break
+			}
+			out.Legs = append(out.Legs, d)
+		}
+		if out != nil {
+			list.Journeys = append(list.Journeys, out)
+		}
+	}
+	return list, nil
+}
+
+// rideStops returns the stops a ride boards and alights at.
+func rideStops(ctx context.Context, q rowQueryer, ride seatRef) (board, alight stopTime, err error) {
+	err = q.QueryRowContext(ctx, `SELECT a.code, a.departs_at, b.code, b.arrives_at FROM departure_stops a, departure_stops b
+		WHERE a.departure_id = $1 AND a.stop = $2 AND b.departure_id = $1 AND b.stop = $3`,
+		ride.DepartureID, ride.From, ride.To).Scan(&board.Code, &board.Departs, &alight.Code, &alight.Arrives)
+	if err != nil {
+		err = status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return board, alight, err
+}
+
+// checkConnections checks that the legs of a journey have not left, that
+// each starts where the one before ends and that it leaves at least the
+// station's transfer time after that one arrives.
+func (s *TicketReservationServer) checkConnections(ctx context.Context, tx *sql.Tx, legs []*pb.JourneyReservationRequest_Leg) error {
+	var prev stopTime
+	for i, leg := range legs {
+		if leg.DepartureId == 0 {
+			return status.Errorf(codes.InvalidArgument, "leg %d: departure_id required", i+1)
+		}
+		ride, err := resolveRide(ctx, tx, leg.DepartureId, leg.FromCode, leg.ToCode)
+		if err != nil {
+			return err
+		}
+		board, alight, err := rideStops(ctx, tx, ride)
+		if err != nil {
+			return err
+		}
+		switch {
+		case i == 0 && !board.Departs.After(time.Now()):
+			return status.Errorf(codes.FailedPrecondition, "departure %d has left %s", leg.DepartureId, board.Code)
+		case i > 0 && !strings.EqualFold(board.Code, prev.Code):
+			return status.Errorf(codes.InvalidArgument, "leg %d starts at %s, not at %s where leg %d ends", i+1, board.Code, prev.Code, i)
+		case i > 0 && board.Departs.Before(prev.Arrives.Add(s.cfg.transferTime(board.Code))):
+			return status.Errorf(codes.FailedPrecondition, "leg %d leaves %s less than %v after leg %d arrives",
+				i+1, board.Code, s.cfg.transferTime(board.Code), i)
+		}
+		prev = alight
+	}
+	return nil
+}
+
+func (s *TicketReservationServer) ReserveJourney(ctx context.Context, req *pb.JourneyReservationRequest) (*pb.JourneyReservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(req.Legs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one leg required")
+	}
+	if len(req.Legs) > s.cfg.MaxTransfers+1 {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d legs per journey", s.cfg.MaxTransfers+1)
+	}
+	if len(req.Passengers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one passenger required")
+	}
+	if len(req.Passengers) > maxPassengers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d passengers per booking", maxPassengers)
+	}
+	for _, p := range req.Passengers {
+		if p.Seat != 0 {
+			return nil, status.Error(codes.InvalidArgument, "seats are allocated on each leg of a journey; passengers cannot name one")
+		}
+	}
+
+	key := idempotencyKey(ctx)
+	if key != "" {
+		if prev, err := s.replayJourney(ctx, key); prev != nil || err != nil {
+			return prev, err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.checkConnections(ctx, tx, req.Legs); err != nil {
+		return nil, err
+	}
+	var journeyID uint64
+	if err := tx.QueryRowContext(ctx, "INSERT INTO journeys DEFAULT VALUES RETURNING id").Scan(&journeyID); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	ids := make([]uint64, len(req.Legs))
+	seats := make([][]seatRef, len(req.Legs))
+	unmet := make([][]*pb.UnmetPreference, len(req.Legs))
+	for i, leg := range req.Legs {
+		r := &pb.ReservationRequest{DepartureId: leg.DepartureId, FromCode: leg.FromCode, ToCode: leg.ToCode,
+			Passengers: req.Passengers, FareType: req.FareType}
+		if ids[i], seats[i], err = s.reserveTx(ctx, tx, r, nil); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tickets SET journey_id = $1 WHERE id = $2", journeyID, ids[i]); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+		if unmet[i], err = s.unmetPreferences(ctx, tx, req.Passengers, seats[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var total uint64
+	if err := tx.QueryRowContext(ctx, "SELECT sum(price_paid) FROM tickets WHERE journey_id = $1", journeyID).Scan(&total); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if req.PricePaid != 0 && req.PricePaid != total {
+		return nil, status.Errorf(codes.FailedPrecondition, "fare is now %s, not %s",
+			formatAmount(total, s.cfg.Currency), formatAmount(req.PricePaid, s.cfg.Currency))
+	}
+	if key != "" {
+		if err := rememberJourneyKey(ctx, tx, key, journeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	// One payment covers every leg, so settlePayment confirms or fails
+	// them together.
+	order := fmt.Sprintf("journey-%d", journeyID)
+	if err := s.expectPayment(ctx, tx, order, ids...); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if err := s.announceBooking(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	p := req.Passengers[0]
+	logging.FromContext(ctx).InfoContext(ctx, "journey reserved", "journey_id", journeyID, "tickets", ids,
+		"passengers", len(req.Passengers), logging.Name("passenger", p.FirstName), logging.Email("email", p.Email))
+	for i, id := range ids {
+		for _, seat := range seats[i] {
+			s.events.publish("TicketReserved", id, seat)
+		}
+	}
+	if err := s.pay(ctx, order, req.PaymentToken); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.loadJourney(ctx, journeyID)
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range resp.Tickets {
+		t.UnmetPreferences = unmet[i]
+	}
+	return resp, nil
+}
+
+// loadJourney returns the tickets of a journey that have not been
+// cancelled, in travel order.
+func (s *TicketReservationServer) loadJourney(ctx context.Context, id uint64) (*pb.JourneyReservation, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT t.id FROM tickets t
+		JOIN departure_stops a ON a.departure_id = t.departure_id AND a.stop = t.from_stop
+		WHERE t.journey_id = $1 ORDER BY a.departs_at`, id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	var ids []uint64
+	for rows.Next() {
+		var ticketID uint64
+		if err := rows.Scan(&ticketID); err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		ids = append(ids, ticketID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if len(ids) == 0 {
+		return nil, status.Errorf(codes.NotFound, "journey %d has no tickets left", id)
+	}
+
+	resp := &pb.JourneyReservation{JourneyId: id}
+	for _, ticketID := range ids {
+		t, err := s.loadTicket(ctx, ticketID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tickets = append(resp.Tickets, t)
+		resp.PricePaid += t.PricePaid
+	}
+	return resp, nil
+}
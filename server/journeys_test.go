@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+var day = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// clock is a time of day, "HH:MM", on the test day.
+func clock(hhmm string) time.Time {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		panic(err)
+	}
+	return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+}
+
+// testTrip builds a trip from stops written "CODE HH:MM", or
+// "CODE HH:MM/HH:MM" when the train waits there.
+func testTrip(id uint64, train string, stops ...string) *trip {
+	t := &trip{ID: id, Train: train}
+	for _, s := range stops {
+		code, times, _ := strings.Cut(s, " ")
+		arrives, departs, waits := strings.Cut(times, "/")
+		if !waits {
+			departs = arrives
+		}
+		t.Stops = append(t.Stops, stopTime{Code: code, Arrives: clock(arrives), Departs: clock(departs)})
+	}
+	return t
+}
+
+// describe writes a journey as its legs, e.g.
+// "T1 London 08:00-Lille 09:00, T2 Lille 09:10-Paris 10:00".
+func describe(j journey) string {
+	legs := make([]string, len(j))
+	for i, l := range j {
+		board, alight := l.Trip.Stops[l.From], l.Trip.Stops[l.To]
+		legs[i] = fmt.Sprintf("%s %s %s-%s %s", l.Trip.Train, board.Code, board.Departs.Format("15:04"), alight.Code, alight.Arrives.Format("15:04"))
+	}
+	return strings.Join(legs, ", ")
+}
+
+func describeAll(js []journey) []string {
+	var out []string
+	for _, j := range js {
+		out = append(out, describe(j))
+	}
+	return out
+}
+
+func fixedTransfer(d time.Duration) func(string) time.Duration {
+	return func(string) time.Duration { return d }
+}
+
+func TestPlanJourneys(t *testing.T) {
+	direct := testTrip(1, "T1", "London 08:00", "Lille 09:20/09:25", "Paris 10:30")
+	toLille := testTrip(2, "T2", "London 08:00", "Lille 09:00")
+	early := testTrip(3, "T3", "Lille 09:05", "Paris 10:00")
+	late := testTrip(4, "T4", "Lille 09:10", "Paris 10:05")
+	// A later, faster run of T4, which a change never takes.
+	express := testTrip(5, "T4", "Lille 09:20", "Paris 09:50")
+	slow := testTrip(6, "T6", "London 07:00", "Paris 11:00")
+	evening := testTrip(7, "T7", "Lille 10:10", "Paris 11:05")
+
+	tests := []struct {
+		name       string
+		trips      []*trip
+		from, to   string
+		after      string
+		until      string
+		maxChanges int
+		transfer   func(string) time.Duration
+		want       []string
+	}{
+		{
+			name:  "direct",
+			trips: []*trip{direct},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 2,
+			transfer: fixedTransfer(10 * time.Minute),
+			want:     []string{"T1 London 08:00-Paris 10:30"},
+		},
+		{
+			name:  "stations ignore case",
+			trips: []*trip{direct},
+			from:  "london", to: "PARIS", after: "07:00", until: "23:00", maxChanges: 2,
+			transfer: fixedTransfer(10 * time.Minute),
+			want:     []string{"T1 London 08:00-Paris 10:30"},
+		},
+		{
+			name:  "between stops on the way",
+			trips: []*trip{direct},
+			from:  "Lille", to: "Paris", after: "07:00", until: "23:00", maxChanges: 2,
+			transfer: fixedTransfer(10 * time.Minute),
+			want:     []string{"T1 Lille 09:25-Paris 10:30"},
+		},
+		{
+			name:  "a connection shorter than the transfer time is missed",
+			trips: []*trip{toLille, early, late},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 1,
+			transfer: fixedTransfer(10 * time.Minute),
+			want:     []string{"T2 London 08:00-Lille 09:00, T4 Lille 09:10-Paris 10:05"},
+		},
+		{
+			name:  "a shorter transfer time catches the earlier connection",
+			trips: []*trip{toLille, early, late},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 1,
+			transfer: fixedTransfer(5 * time.Minute),
+			want:     []string{"T2 London 08:00-Lille 09:00, T3 Lille 09:05-Paris 10:00"},
+		},
+		{
+			name:  "transfer time per station",
+			trips: []*trip{toLille, early, late},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 1,
+			transfer: func(station string) time.Duration {
+				if station == "Lille" {
+					return time.Hour
+				}
+				return 0
+			},
+		},
+		{
+			name:  "no changes allowed",
+			trips: []*trip{toLille, early, late},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 0,
+			transfer: fixedTransfer(0),
+		},
+		{
+			name:  "only the first run of a train is taken at a change",
+			trips: []*trip{toLille, express, late},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 1,
+			transfer: fixedTransfer(10 * time.Minute),
+			want:     []string{"T2 London 08:00-Lille 09:00, T4 Lille 09:10-Paris 10:05"},
+		},
+		{
+			name:  "boarding before after or from until is left out",
+			trips: []*trip{slow, direct},
+			from:  "London", to: "Paris", after: "07:30", until: "08:00", maxChanges: 0,
+			transfer: fixedTransfer(0),
+		},
+		{
+			name:  "a faster connection does not hide a direct train",
+			trips: []*trip{slow, toLille, early},
+			from:  "London", to: "Paris", after: "06:00", until: "23:00", maxChanges: 1,
+			transfer: fixedTransfer(5 * time.Minute),
+			want:     []string{"T6 London 07:00-Paris 11:00", "T2 London 08:00-Lille 09:00, T3 Lille 09:05-Paris 10:00"},
+		},
+		{
+			name:  "a direct train arriving earlier hides connections",
+			trips: []*trip{direct, toLille, evening},
+			from:  "London", to: "Paris", after: "07:00", until: "23:00", maxChanges: 1,
+			transfer: fixedTransfer(5 * time.Minute),
+			want:     []string{"T1 London 08:00-Paris 10:30"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describeAll(planJourneys(tt.trips, tt.from, tt.to, clock(tt.after), clock(tt.until), tt.maxChanges, tt.transfer))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("planJourneys = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortJourneys(t *testing.T) {
+	leg := func(tr *trip) journeyLeg { return journeyLeg{Trip: tr, From: 0, To: len(tr.Stops) - 1} }
+	// Arrive 10:00 with a change, leaving 08:00.
+	changing := journey{
+		leg(testTrip(1, "A1", "London 08:00", "Lille 09:00")),
+		leg(testTrip(2, "A2", "Lille 09:10", "Paris 10:00")),
+	}
+	// Arrive 10:30 without a change.
+	late := journey{leg(testTrip(3, "B", "London 08:10", "Paris 10:30"))}
+	// Arrive 10:00 without a change, leaving 07:00 or 07:30.
+	slow := journey{leg(testTrip(4, "C", "London 07:00", "Paris 10:00"))}
+	quick := journey{leg(testTrip(5, "D", "London 07:30", "Paris 10:00"))}
+
+	for _, tt := range []struct {
+		by   pb.JourneyRequest_Sort
+		want []string
+	}{
+		{pb.JourneyRequest_EARLIEST_ARRIVAL, []string{
+			"D London 07:30-Paris 10:00",
+			"C London 07:00-Paris 10:00",
+			"A1 London 08:00-Lille 09:00, A2 Lille 09:10-Paris 10:00",
+			"B London 08:10-Paris 10:30",
+		}},
+		{pb.JourneyRequest_FEWEST_CHANGES, []string{
+			"D London 07:30-Paris 10:00",
+			"C London 07:00-Paris 10:00",
+			"B London 08:10-Paris 10:30",
+			"A1 London 08:00-Lille 09:00, A2 Lille 09:10-Paris 10:00",
+		}},
+	} {
+		t.Run(tt.by.String(), func(t *testing.T) {
+			js := []journey{changing, late, slow, quick}
+			sortJourneys(js, tt.by)
+			if got := describeAll(js); !slices.Equal(got, tt.want) {
+				t.Errorf("sortJourneys = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJourneyTimes(t *testing.T) {
+	j := journey{
+		{Trip: testTrip(1, "T1", "London 08:00", "Lille 09:20/09:25", "Paris 10:30"), From: 0, To: 1},
+		{Trip: testTrip(2, "T2", "Lille 09:40", "Brussels 10:15"), From: 0, To: 1},
+	}
+	if got, want := j.departs(), clock("08:00"); !got.Equal(want) {
+		t.Errorf("departs = %v, want %v", got, want)
+	}
+	if got, want := j.arrives(), clock("10:15"); !got.Equal(want) {
+		t.Errorf("arrives = %v, want %v", got, want)
+	}
+	if got := j.changes(); got != 1 {
+		t.Errorf("changes = %d, want 1", got)
+	}
+	if got := j[:1].changes(); got != 0 {
+		t.Errorf("changes of one leg = %d, want 0", got)
+	}
+}
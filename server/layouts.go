@@ -0,0 +1,657 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultRow is the seats of a row when a coach does not say: window,
+// aisle, gangway, aisle, window.
+const defaultRow = "WA_AW"
+
+var (
+	layoutNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	// sectionPattern matches what the web UI accepts as a section.
+	sectionPattern = regexp.MustCompile(`^[A-Z0-9]{1,8}$`)
+)
+
+// seatLayout is a layout expanded into its seats, which is what capacity,
+// the seat map and the seat allocator work from.
+type seatLayout struct {
+	Name    string
+	Version uint32
+	coaches map[string]*coachLayout
+}
+
+// coachLayout is one coach of a seatLayout.
+type coachLayout struct {
+	Section string
+	Class   string
+	Quiet   bool
+	Rows    int
+	// Seats in numbering order.
+	Seats  []uint32
+	places map[uint32]seatPlace
+}
+
+// seatPlace is where a seat is in its coach and what it offers.
+type seatPlace struct {
+	// Row counts from 0 at the front; Col is the place in the row pattern,
+	// gangway included.
+	Row, Col   int
+	Position   pb.SeatPreferences_Position
+	Facing     pb.SeatPreferences_Facing
+	NearExit   bool
+	Accessible bool
+	Blocked    bool
+}
+
+// coach returns the coach of section. Sections the layout does not know,
+// e.g. seats left over from an earlier layout, come back empty.
+func (l *seatLayout) coach(section string) *coachLayout {
+	if c, ok := l.coaches[section]; ok {
+		return c
+	}
+	return &coachLayout{Section: section}
+}
+
+func (c *coachLayout) place(seat uint32) seatPlace {
+	return c.places[seat]
+}
+
+// capacity counts the seats of the layout that can be sold.
+func (l *seatLayout) capacity() uint32 {
+	n := uint32(0)
+	for _, c := range l.coaches {
+		for _, p := range c.places {
+			if !p.Blocked {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// expandLayout lays out the seats of every coach of l, which must have
+// passed checkLayout.
+func expandLayout(l *pb.Layout, version uint32) *seatLayout {
+	sl := &seatLayout{Name: l.Name, Version: version, coaches: map[string]*coachLayout{}}
+	for _, c := range l.Coaches {
+		rows := int(c.Rows)
+		forward := (rows + 1) / 2
+		if c.ForwardRows != nil {
+			forward = int(*c.ForwardRows)
+		}
+		cl := &coachLayout{Section: c.Section, Class: c.Class, Quiet: c.Quiet, Rows: rows, places: map[uint32]seatPlace{}}
+		seat := c.FirstSeat
+		for i := 0; i < rows && len(cl.Seats) < int(c.Seats); i++ {
+			row := i
+			if c.Numbering == pb.Coach_FROM_BACK {
+				row = rows - 1 - i
+			}
+			for col, kind := range c.Row {
+				if kind == '_' || len(cl.Seats) == int(c.Seats) {
+					continue
+				}
+				p := seatPlace{
+					Row:        row,
+					Col:        col,
+					Facing:     pb.SeatPreferences_FORWARD,
+					NearExit:   slices.Contains(c.ExitRows, uint32(row+1)),
+					Accessible: slices.Contains(c.AccessibleSeats, seat),
+					Blocked:    slices.Contains(c.BlockedSeats, seat),
+				}
+				switch kind {
+				case 'W':
+					p.Position = pb.SeatPreferences_WINDOW
+				case 'A':
+					p.Position = pb.SeatPreferences_AISLE
+				}
+				if row >= forward {
+					p.Facing = pb.SeatPreferences_BACKWARD
+				}
+				cl.Seats = append(cl.Seats, seat)
+				cl.places[seat] = p
+				seat++
+			}
+		}
+		sl.coaches[c.Section] = cl
+	}
+	return sl
+}
+
+// defaultLayout is the layout of departures without one: SECTIONS coaches
+// of SEATS_PER_SECTION seats in the default row, the first row holding the
+// priority seats and the first and last rows by the doors.
+func (c config) defaultLayout() *pb.Layout {
+	l := &pb.Layout{}
+	places := uint32(strings.Count(defaultRow, "W") + strings.Count(defaultRow, "A"))
+	for _, section := range c.Sections {
+		n := uint32(c.SeatsPerSection)
+		rows := (n + places - 1) / places
+		coach := &pb.Coach{Section: strings.TrimSpace(section), Rows: rows, Seats: n, ExitRows: []uint32{1, rows}}
+		for seat := uint32(1); seat <= min(places, n); seat++ {
+			coach.AccessibleSeats = append(coach.AccessibleSeats, seat)
+		}
+		for _, q := range c.QuietSections {
+			if strings.TrimSpace(q) == coach.Section {
+				coach.Quiet = true
+			}
+		}
+		l.Coaches = append(l.Coaches, coach)
+	}
+	// loadConfig has checked the sections, so this only fills in defaults.
+	checkLayout(l, false)
+	return l
+}
+
+// parseLayout reads a layout definition, YAML or JSON, and checks it.
+func parseLayout(definition string) (*pb.Layout, error) {
+	var v any
+	if err := yaml.Unmarshal([]byte(definition), &v); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "layout: %v", err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "layout: %v", err)
+	}
+	l := &pb.Layout{}
+	if err := protojson.Unmarshal(b, l); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "layout: %v", err)
+	}
+	if l.Version != 0 || l.Capacity != 0 || l.CreatedAt != nil {
+		return nil, status.Error(codes.InvalidArgument, "layout: version, capacity and created_at are set by the server")
+	}
+	if err := checkLayout(l, true); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// checkLayout fills in the defaults of l and checks that it makes sense.
+// Only stored layouts need a name.
+func checkLayout(l *pb.Layout, named bool) error {
+	if named && !layoutNamePattern.MatchString(l.Name) {
+		return status.Errorf(codes.InvalidArgument, "layout name %q: use up to 64 letters, digits, '.', '_' or '-'", l.Name)
+	}
+	if len(l.Coaches) == 0 {
+		return status.Error(codes.InvalidArgument, "layout has no coaches")
+	}
+	sections := map[string]bool{}
+	for _, c := range l.Coaches {
+		if !sectionPattern.MatchString(c.Section) {
+			return status.Errorf(codes.InvalidArgument, "coach section %q: use up to 8 capital letters or digits", c.Section)
+		}
+		if sections[c.Section] {
+			return status.Errorf(codes.InvalidArgument, "coach %s appears twice", c.Section)
+		}
+		sections[c.Section] = true
+		if err := checkCoach(c); err != nil {
+			return status.Errorf(codes.InvalidArgument, "coach %s: %v", c.Section, err)
+		}
+	}
+	return nil
+}
+
+func checkCoach(c *pb.Coach) error {
+	if c.Class == "" {
+		c.Class = "standard"
+	}
+	if c.Row == "" {
+		c.Row = defaultRow
+	}
+	if c.FirstSeat == 0 {
+		c.FirstSeat = 1
+	}
+	if len(c.Class) > 32 {
+		return fmt.Errorf("class is longer than 32 characters")
+	}
+	if c.Rows == 0 || c.Rows > 100 {
+		return fmt.Errorf("rows must be between 1 and 100")
+	}
+	places := uint32(0)
+	for _, kind := range c.Row {
+		switch kind {
+		case 'W', 'A', 'M':
+			places++
+		case '_':
+		default:
+			return fmt.Errorf("row %q: use W, A, M and _", c.Row)
+		}
+	}
+	if places == 0 || places > 10 {
+		return fmt.Errorf("row %q must have between 1 and 10 seats", c.Row)
+	}
+	if c.Seats == 0 {
+		c.Seats = c.Rows * places
+	}
+	if c.Seats > c.Rows*places {
+		return fmt.Errorf("%d seats do not fit in %d rows of %d", c.Seats, c.Rows, places)
+	}
+	last := c.FirstSeat + c.Seats - 1
+	if last > 999 {
+		return fmt.Errorf("seat numbers must stay below 1000")
+	}
+	if c.ForwardRows == nil {
+		c.ForwardRows = proto.Uint32((c.Rows + 1) / 2)
+	}
+	if *c.ForwardRows > c.Rows {
+		return fmt.Errorf("forward_rows is more than the %d rows", c.Rows)
+	}
+	for _, row := range c.ExitRows {
+		if row == 0 || row > c.Rows {
+			return fmt.Errorf("exit row %d is not between 1 and %d", row, c.Rows)
+		}
+	}
+	for _, seat := range slices.Concat(c.AccessibleSeats, c.BlockedSeats) {
+		if seat < c.FirstSeat || seat > last {
+			return fmt.Errorf("seat %d is not between %d and %d", seat, c.FirstSeat, last)
+		}
+	}
+	slices.Sort(c.ExitRows)
+	slices.Sort(c.AccessibleSeats)
+	slices.Sort(c.BlockedSeats)
+	c.ExitRows = slices.Compact(c.ExitRows)
+	c.AccessibleSeats = slices.Compact(c.AccessibleSeats)
+	c.BlockedSeats = slices.Compact(c.BlockedSeats)
+	return nil
+}
+
+// layoutKey names a stored layout version; the zero key is the default
+// layout.
+type layoutKey struct {
+	Name    string
+	Version uint32
+}
+
+// layoutCache keeps expanded layouts. Stored versions never change, so
+// entries are never invalidated.
+type layoutCache struct {
+	mu sync.Mutex
+	m  map[layoutKey]*seatLayout
+}
+
+// layout returns the expanded layout of key.
+func (s *TicketReservationServer) layout(ctx context.Context, q rowQueryer, key layoutKey) (*seatLayout, error) {
+	s.layouts.mu.Lock()
+	defer s.layouts.mu.Unlock()
+	if l, ok := s.layouts.m[key]; ok {
+		return l, nil
+	}
+	def := s.cfg.defaultLayout()
+	if key.Name != "" {
+		stored, err := loadLayout(ctx, q, key.Name, key.Version)
+		if err != nil {
+			return nil, err
+		}
+		def = stored
+	}
+	if s.layouts.m == nil {
+		s.layouts.m = map[layoutKey]*seatLayout{}
+	}
+	s.layouts.m[key] = expandLayout(def, key.Version)
+	return s.layouts.m[key], nil
+}
+
+// departureLayout returns the layout of a departure.
+func (s *TicketReservationServer) departureLayout(ctx context.Context, q rowQueryer, departureID uint64) (*seatLayout, error) {
+	var name sql.NullString
+	var version sql.NullInt64
+	err := q.QueryRowContext(ctx, "SELECT layout_name, layout_version FROM departures WHERE id = $1", departureID).Scan(&name, &version)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "departure %d not found", departureID)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return s.layout(ctx, q, layoutKey{Name: name.String, Version: uint32(version.Int64)})
+}
+
+// loadLayout reads a stored layout; version 0 is the latest.
+func loadLayout(ctx context.Context, q rowQueryer, name string, version uint32) (*pb.Layout, error) {
+	var def []byte
+	var created time.Time
+	err := q.QueryRowContext(ctx, `SELECT version, definition, created_at FROM layouts
+		WHERE name = $1 AND ($2 = 0 OR version = $2) ORDER BY version DESC LIMIT 1`, name, version,
+	).Scan(&version, &def, &created)
+	if err == sql.ErrNoRows && version != 0 {
+		return nil, status.Errorf(codes.NotFound, "layout %s version %d not found", name, version)
+	}
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "layout %s not found", name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	l := &pb.Layout{}
+	if err := jsonIn.Unmarshal(def, l); err != nil {
+		return nil, status.Errorf(codes.Internal, "decoding layout %s: %v", name, err)
+	}
+	l.Version, l.CreatedAt = version, timestamppb.New(created)
+	l.Capacity = expandLayout(l, version).capacity()
+	return l, nil
+}
+
+// layoutVersion is the version a parsed layout is stored as, given the
+// latest stored version of its name or nil: the next version, 1 for a new
+// name, or the latest again when nothing changed.
+func layoutVersion(l, latest *pb.Layout) (version uint32, changed bool) {
+	if latest == nil {
+		return 1, true
+	}
+	if proto.Equal(&pb.Layout{Name: latest.Name, Coaches: latest.Coaches}, l) {
+		return latest.Version, false
+	}
+	return latest.Version + 1, true
+}
+
+func (a *TicketAdminServer) PutLayout(ctx context.Context, req *pb.PutLayoutRequest) (*pb.Layout, error) {
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	l, err := parseLayout(req.Definition)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := a.srv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	latest, err := loadLayout(ctx, tx, l.Name, 0)
+	if status.Code(err) == codes.NotFound {
+		latest, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var changed bool
+	if l.Version, changed = layoutVersion(l, latest); !changed {
+		return latest, nil
+	}
+	def, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(l)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encoding layout: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO layouts (name, version, definition) VALUES ($1, $2, $3)", l.Name, l.Version, def)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	stored, err := loadLayout(ctx, tx, l.Name, l.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "layout stored", "layout", l.Name, "version", l.Version, "capacity", stored.Capacity)
+	return stored, nil
+}
+
+func (a *TicketAdminServer) ListLayouts(ctx context.Context, req *pb.EmptyRequest) (*pb.LayoutList, error) {
+	rows, err := a.srv.db.QueryContext(ctx, "SELECT DISTINCT name FROM layouts ORDER BY name")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	list := &pb.LayoutList{}
+	for _, name := range names {
+		l, err := loadLayout(ctx, a.srv.db, name, 0)
+		if err != nil {
+			return nil, err
+		}
+		list.Layouts = append(list.Layouts, l)
+	}
+	return list, nil
+}
+
+func (a *TicketAdminServer) GetLayout(ctx context.Context, req *pb.LayoutQuery) (*pb.Layout, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name required")
+	}
+	return loadLayout(ctx, a.srv.db, req.Name, req.Version)
+}
+
+func (a *TicketAdminServer) AttachLayout(ctx context.Context, req *pb.AttachLayoutRequest) (*pb.AttachLayoutResponse, error) {
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name required")
+	}
+	if (req.DepartureId == 0) == (req.Train == "") {
+		return nil, status.Error(codes.InvalidArgument, "either departure_id or train required")
+	}
+	tx, err := a.srv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	def, err := loadLayout(ctx, tx, req.Name, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	l, err := a.srv.layout(ctx, tx, layoutKey{Name: def.Name, Version: def.Version})
+	if err != nil {
+		return nil, err
+	}
+
+	// Departures that have left keep the layout they ran with.
+	var rows *sql.Rows
+	if req.DepartureId != 0 {
+		var departed bool
+		err := tx.QueryRowContext(ctx, "SELECT departs_at <= now() FROM departures WHERE id = $1", req.DepartureId).Scan(&departed)
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "departure %d not found", req.DepartureId)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+		}
+		if departed {
+			return nil, status.Errorf(codes.FailedPrecondition, "departure %d has left", req.DepartureId)
+		}
+		rows, err = tx.QueryContext(ctx, "SELECT id FROM departures WHERE id = $1 FOR UPDATE", req.DepartureId)
+	} else {
+		_, err = tx.ExecContext(ctx, `INSERT INTO train_layouts (train, layout_name, layout_version) VALUES ($1, $2, $3)
+			ON CONFLICT (train) DO UPDATE SET layout_name = EXCLUDED.layout_name, layout_version = EXCLUDED.layout_version`,
+			req.Train, l.Name, l.Version)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+		}
+		rows, err = tx.QueryContext(ctx, "SELECT id FROM departures WHERE train = $1 AND departs_at > now() ORDER BY departs_at FOR UPDATE", req.Train)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	resp := &pb.AttachLayoutResponse{}
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		resp.DepartureIds = append(resp.DepartureIds, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	for _, id := range resp.DepartureIds {
+		added, removed, err := applyLayout(ctx, tx, id, l)
+		if err != nil {
+			return nil, err
+		}
+		resp.SeatsAdded += added
+		resp.SeatsRemoved += removed
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "layout attached", "layout", l.Name, "version", l.Version, "train", req.Train,
+		"departures", len(resp.DepartureIds), "seats_added", resp.SeatsAdded, "seats_removed", resp.SeatsRemoved)
+	for _, id := range resp.DepartureIds {
+		a.srv.events.publish("LayoutAttached", 0, seatRef{DepartureID: id})
+	}
+	return resp, nil
+}
+
+// applyLayout gives a departure the seats of l. Free seats the layout does
+// not have are removed; a booked or held one fails the whole change, as
+// does blocking it.
+func applyLayout(ctx context.Context, tx *sql.Tx, departureID uint64, l *seatLayout) (added, removed uint32, err error) {
+	rows, err := tx.QueryContext(ctx, `WITH legs AS (SELECT * FROM seats WHERE departure_id = $1 FOR UPDATE)
+		SELECT section, seat, bool_or(blocked), bool_or(ticket_id IS NOT NULL OR held_until > now())
+		FROM legs GROUP BY section, seat`, departureID)
+	if err != nil {
+		return 0, 0, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	type current struct{ blocked, taken bool }
+	have := map[seatRef]current{}
+	for rows.Next() {
+		ref := seatRef{DepartureID: departureID}
+		var c current
+		if err := rows.Scan(&ref.Section, &ref.Seat, &c.blocked, &c.taken); err != nil {
+			rows.Close()
+			return 0, 0, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		have[ref] = c
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	for ref, c := range have {
+		coach, ok := l.coaches[ref.Section]
+		p, exists := seatPlace{}, false
+		if ok {
+			p, exists = coach.places[ref.Seat]
+		}
+		if c.taken && (!exists || p.Blocked) {
+			return 0, 0, status.Errorf(codes.FailedPrecondition, "departure %d: seat %s-%d is booked or held and layout %s version %d does not sell it",
+				departureID, ref.Section, ref.Seat, l.Name, l.Version)
+		}
+		if !exists {
+			_, err := tx.ExecContext(ctx, "DELETE FROM seats WHERE departure_id = $1 AND section = $2 AND seat = $3",
+				departureID, ref.Section, ref.Seat)
+			if err != nil {
+				return 0, 0, status.Errorf(codes.Internal, "DB Delete Error: %v", err)
+			}
+			removed++
+		}
+	}
+	for _, coach := range l.coaches {
+		for _, seat := range coach.Seats {
+			ref := seatRef{DepartureID: departureID, Section: coach.Section, Seat: seat}
+			blocked := coach.places[seat].Blocked
+			c, ok := have[ref]
+			if ok && c.blocked == blocked {
+				continue
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO seats (departure_id, section, seat, leg, blocked)
+				SELECT $1, $2, $3, leg, $4 FROM generate_series(0, (SELECT count(*) FROM departure_stops WHERE departure_id = $1) - 2) leg
+				ON CONFLICT (departure_id, section, seat, leg) DO UPDATE SET blocked = EXCLUDED.blocked`,
+				departureID, coach.Section, seat, blocked)
+			if err != nil {
+				return 0, 0, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+			}
+			if !ok {
+				added++
+			}
+		}
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE departures SET layout_name = $1, layout_version = $2 WHERE id = $3", l.Name, l.Version, departureID)
+	if err != nil {
+		return 0, 0, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return added, removed, nil
+}
+
+// seedSeats makes sure every seat of every departure's layout has a row in
+// the index for each leg of the departure. Seats no longer in the default
+// layout are left alone.
+func seedSeats(db *sql.DB, cfg config) error {
+	ctx := context.Background()
+	rows, err := db.QueryContext(ctx, "SELECT DISTINCT layout_name, layout_version FROM departures")
+	if err != nil {
+		return err
+	}
+	var keys []layoutKey
+	for rows.Next() {
+		var name sql.NullString
+		var version sql.NullInt64
+		if err := rows.Scan(&name, &version); err != nil {
+			rows.Close()
+			return err
+		}
+		keys = append(keys, layoutKey{Name: name.String, Version: uint32(version.Int64)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		def := cfg.defaultLayout()
+		if key.Name != "" {
+			if def, err = loadLayout(ctx, db, key.Name, key.Version); err != nil {
+				return err
+			}
+		}
+		for _, coach := range expandLayout(def, key.Version).coaches {
+			var blocked []int64
+			seats := make([]int64, len(coach.Seats))
+			for i, seat := range coach.Seats {
+				seats[i] = int64(seat)
+				if coach.places[seat].Blocked {
+					blocked = append(blocked, int64(seat))
+				}
+			}
+			_, err := db.ExecContext(ctx, `INSERT INTO seats (departure_id, section, seat, leg, blocked)
+				SELECT d.id, $1, n, leg, n = ANY($3::int[]) FROM departures d, unnest($2::int[]) n,
+					generate_series(0, (SELECT count(*) FROM departure_stops WHERE departure_id = d.id) - 2) leg
+				WHERE d.layout_name IS NOT DISTINCT FROM $4 AND d.layout_version IS NOT DISTINCT FROM $5
+				ON CONFLICT DO NOTHING`,
+				coach.Section, pq.Array(seats), pq.Array(blocked), sql.NullString{String: key.Name, Valid: key.Name != ""},
+				sql.NullInt64{Int64: int64(key.Version), Valid: key.Name != ""})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
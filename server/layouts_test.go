@@ -0,0 +1,253 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// class800 is the example layout of the README.
+const class800 = `
+name: class-800
+coaches:
+  - section: A
+    class: first
+    rows: 6
+    row: W_AW
+    quiet: true
+    exit_rows: [1, 6]
+    accessible_seats: [1, 2]
+    blocked_seats: [18]
+  - section: B
+    rows: 10
+    numbering: FROM_BACK
+    first_seat: 21
+    seats: 38
+    forward_rows: 5
+    exit_rows: [1, 10]
+`
+
+const class800JSON = `{
+  "name": "class-800",
+  "coaches": [
+    {"section": "A", "class": "first", "rows": 6, "row": "W_AW", "quiet": true,
+     "exitRows": [1, 6], "accessibleSeats": [1, 2], "blockedSeats": [18]},
+    {"section": "B", "rows": 10, "numbering": "FROM_BACK", "firstSeat": 21, "seats": 38,
+     "forwardRows": 5, "exitRows": [1, 10]}
+  ]
+}`
+
+// stored is l as loadLayout reads it back after PutLayout stored it as
+// version.
+func stored(t *testing.T, l *pb.Layout, version uint32) *pb.Layout {
+	t.Helper()
+	def, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &pb.Layout{}
+	if err := jsonIn.Unmarshal(def, out); err != nil {
+		t.Fatal(err)
+	}
+	out.Version = version
+	out.Capacity = expandLayout(out, version).capacity()
+	return out
+}
+
+func TestParseLayout(t *testing.T) {
+	fromYAML, err := parseLayout(class800)
+	if err != nil {
+		t.Fatalf("parseLayout(YAML): %v", err)
+	}
+	fromJSON, err := parseLayout(class800JSON)
+	if err != nil {
+		t.Fatalf("parseLayout(JSON): %v", err)
+	}
+	if !proto.Equal(fromYAML, fromJSON) {
+		t.Errorf("YAML and JSON layouts differ:\n%v\n%v", fromYAML, fromJSON)
+	}
+
+	// Defaults are filled in.
+	b := fromYAML.Coaches[1]
+	if b.Class != "standard" || b.Row != defaultRow || b.FirstSeat != 21 || b.Seats != 38 || b.GetForwardRows() != 5 {
+		t.Errorf("coach B = %v, want standard class, row %s, seats 21 to 58 and 5 forward rows", b, defaultRow)
+	}
+	a := fromYAML.Coaches[0]
+	if a.FirstSeat != 1 || a.Seats != 18 || a.GetForwardRows() != 3 {
+		t.Errorf("coach A = %v, want 18 seats from 1 and 3 forward rows", a)
+	}
+}
+
+func TestParseLayoutInvalid(t *testing.T) {
+	coach := "\ncoaches:\n  - section: A\n    rows: 2\n"
+	tests := []struct {
+		name, definition, want string
+	}{
+		{"not YAML", "name: [", "layout:"},
+		{"unknown field", "name: x\nseats: 4" + coach, "layout:"},
+		{"version set", "name: x\nversion: 2" + coach, "set by the server"},
+		{"capacity set", "name: x\ncapacity: 8" + coach, "set by the server"},
+		{"no name", strings.TrimPrefix(coach, "\n"), "layout name"},
+		{"bad name", "name: ../x" + coach, "layout name"},
+		{"no coaches", "name: x", "no coaches"},
+		{"lower-case section", "name: x\ncoaches:\n  - {section: a, rows: 2}", "coach section"},
+		{"duplicate section", "name: x\ncoaches:\n  - {section: A, rows: 2}\n  - {section: A, rows: 3}", "appears twice"},
+		{"no rows", "name: x\ncoaches:\n  - {section: A}", "rows must be"},
+		{"too many rows", "name: x\ncoaches:\n  - {section: A, rows: 101}", "rows must be"},
+		{"bad row", "name: x\ncoaches:\n  - {section: A, rows: 2, row: WX}", "use W, A, M and _"},
+		{"row without seats", "name: x\ncoaches:\n  - {section: A, rows: 2, row: _}", "between 1 and 10 seats"},
+		{"row too wide", "name: x\ncoaches:\n  - {section: A, rows: 2, row: WAMMMMMMMAW}", "between 1 and 10 seats"},
+		{"seats do not fit", "name: x\ncoaches:\n  - {section: A, rows: 2, seats: 9}", "do not fit"},
+		{"seat numbers too high", "name: x\ncoaches:\n  - {section: A, rows: 2, first_seat: 995}", "below 1000"},
+		{"too many forward rows", "name: x\ncoaches:\n  - {section: A, rows: 2, forward_rows: 3}", "forward_rows"},
+		{"exit row 0", "name: x\ncoaches:\n  - {section: A, rows: 2, exit_rows: [0]}", "exit row 0"},
+		{"exit row past the end", "name: x\ncoaches:\n  - {section: A, rows: 2, exit_rows: [3]}", "exit row 3"},
+		{"accessible seat outside the coach", "name: x\ncoaches:\n  - {section: A, rows: 2, accessible_seats: [9]}", "seat 9"},
+		{"blocked seat before the first", "name: x\ncoaches:\n  - {section: A, rows: 2, first_seat: 5, blocked_seats: [4]}", "seat 4"},
+		{"class too long", "name: x\ncoaches:\n  - {section: A, rows: 2, class: " + strings.Repeat("x", 33) + "}", "class"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := parseLayout(tt.definition)
+			if err == nil {
+				t.Fatalf("parseLayout = %v, want an error", l)
+			}
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseLayout = %v, want InvalidArgument mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLayout(t *testing.T) {
+	// The default layout needs no name.
+	if err := checkLayout(&pb.Layout{Coaches: []*pb.Coach{{Section: "A", Rows: 1}}}, false); err != nil {
+		t.Errorf("checkLayout unnamed: %v", err)
+	}
+	if err := checkLayout(&pb.Layout{Coaches: []*pb.Coach{{Section: "A", Rows: 1}}}, true); err == nil {
+		t.Error("checkLayout accepted a stored layout without a name")
+	}
+
+	// Seats listed twice are kept once, in order.
+	l := &pb.Layout{Name: "x", Coaches: []*pb.Coach{{
+		Section:         "A",
+		Rows:            3,
+		ExitRows:        []uint32{3, 1, 3},
+		AccessibleSeats: []uint32{2, 1, 2, 1},
+		BlockedSeats:    []uint32{12, 12},
+	}}}
+	if err := checkLayout(l, true); err != nil {
+		t.Fatalf("checkLayout: %v", err)
+	}
+	c := l.Coaches[0]
+	want := &pb.Coach{
+		Section:         "A",
+		Class:           "standard",
+		Rows:            3,
+		Row:             defaultRow,
+		FirstSeat:       1,
+		Seats:           12,
+		ForwardRows:     proto.Uint32(2),
+		ExitRows:        []uint32{1, 3},
+		AccessibleSeats: []uint32{1, 2},
+		BlockedSeats:    []uint32{12},
+	}
+	if !proto.Equal(c, want) {
+		t.Errorf("checkLayout coach = %v, want %v", c, want)
+	}
+	if got := expandLayout(l, 1).capacity(); got != 11 {
+		t.Errorf("capacity = %d, want 11", got)
+	}
+}
+
+func TestExpandLayout(t *testing.T) {
+	l, err := parseLayout(class800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl := expandLayout(l, 1)
+	if got := sl.capacity(); got != 17+38 {
+		t.Errorf("capacity = %d, want %d", got, 17+38)
+	}
+
+	// Every seat number of a coach is used once.
+	for _, c := range sl.coaches {
+		if len(c.places) != len(c.Seats) {
+			t.Errorf("coach %s numbers %d seats but places %d", c.Section, len(c.Seats), len(c.places))
+		}
+	}
+	a, b := sl.coach("A"), sl.coach("B")
+	if a.Seats[0] != 1 || a.Seats[len(a.Seats)-1] != 18 || b.Seats[0] != 21 || b.Seats[len(b.Seats)-1] != 58 {
+		t.Errorf("seats A %v, B %v, want 1 to 18 and 21 to 58", a.Seats, b.Seats)
+	}
+	if c := sl.coach("C"); len(c.Seats) != 0 || c.Section != "C" {
+		t.Errorf("unknown coach = %+v, want an empty coach C", c)
+	}
+
+	tests := []struct {
+		section string
+		seat    uint32
+		want    seatPlace
+	}{
+		// Coach A, W_AW numbered from the front, 3 of 6 rows facing forward.
+		{"A", 1, seatPlace{Row: 0, Col: 0, Position: pb.SeatPreferences_WINDOW, Facing: pb.SeatPreferences_FORWARD, NearExit: true, Accessible: true}},
+		{"A", 2, seatPlace{Row: 0, Col: 2, Position: pb.SeatPreferences_AISLE, Facing: pb.SeatPreferences_FORWARD, NearExit: true, Accessible: true}},
+		{"A", 9, seatPlace{Row: 2, Col: 3, Position: pb.SeatPreferences_WINDOW, Facing: pb.SeatPreferences_FORWARD}},
+		{"A", 10, seatPlace{Row: 3, Col: 0, Position: pb.SeatPreferences_WINDOW, Facing: pb.SeatPreferences_BACKWARD}},
+		{"A", 18, seatPlace{Row: 5, Col: 3, Position: pb.SeatPreferences_WINDOW, Facing: pb.SeatPreferences_BACKWARD, NearExit: true, Blocked: true}},
+		// Coach B is numbered from the back, the front row holding only
+		// the last two seats.
+		{"B", 21, seatPlace{Row: 9, Col: 0, Position: pb.SeatPreferences_WINDOW, Facing: pb.SeatPreferences_BACKWARD, NearExit: true}},
+		{"B", 23, seatPlace{Row: 9, Col: 3, Position: pb.SeatPreferences_AISLE, Facing: pb.SeatPreferences_BACKWARD, NearExit: true}},
+		{"B", 41, seatPlace{Row: 4, Col: 0, Position: pb.SeatPreferences_WINDOW, Facing: pb.SeatPreferences_FORWARD}},
+		{"B", 58, seatPlace{Row: 0, Col: 1, Position: pb.SeatPreferences_AISLE, Facing: pb.SeatPreferences_FORWARD, NearExit: true}},
+	}
+	for _, tt := range tests {
+		if got := sl.coach(tt.section).place(tt.seat); got != tt.want {
+			t.Errorf("seat %s-%d = %+v, want %+v", tt.section, tt.seat, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutVersion(t *testing.T) {
+	l, err := parseLayout(class800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, changed := layoutVersion(l, nil); v != 1 || !changed {
+		t.Errorf("new layout = version %d, changed %v, want 1, true", v, changed)
+	}
+
+	latest := stored(t, l, 3)
+	for name, def := range map[string]string{"YAML": class800, "JSON": class800JSON} {
+		again, err := parseLayout(def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, changed := layoutVersion(again, latest); v != 3 || changed {
+			t.Errorf("same layout as %s = version %d, changed %v, want 3, false", name, v, changed)
+		}
+	}
+
+	// Writing out a default makes no difference either.
+	explicit, err := parseLayout(strings.Replace(class800, "    rows: 10\n", "    rows: 10\n    class: standard\n    row: WA_AW\n", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, changed := layoutVersion(explicit, latest); v != 3 || changed {
+		t.Errorf("layout with defaults written out = version %d, changed %v, want 3, false", v, changed)
+	}
+
+	changed, err := parseLayout(strings.Replace(class800, "blocked_seats: [18]", "blocked_seats: [17, 18]", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := layoutVersion(changed, latest); v != 4 || !ok {
+		t.Errorf("changed layout = version %d, changed %v, want 4, true", v, ok)
+	}
+}
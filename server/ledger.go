@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The event outbox doubles as the ticket ledger: every change to a ticket
+// appends an event, and each event keeps the full state of the ticket
+// after the change in event_outbox.state. The tickets and
+// ticket_passengers tables are projections of the ledger that the RPCs
+// read and write for speed; RebuildProjections puts them back in line with
+// it, and GetSeatOccupancy answers from it for any point in the past.
+
+// ledgerState is the state of a ticket as the ledger records it: all of
+// tickets and ticket_passengers except the bookkeeping of notifications.
+type ledgerState struct {
+	Status      string            `json:"status"`
+	DepartureID uint64            `json:"departure_id"`
+	FromStop    int               `json:"from_stop"`
+	ToStop      int               `json:"to_stop"`
+	AccountID   uint64            `json:"account_id"`
+	PricePaid   uint64            `json:"price_paid"`
+	FareType    string            `json:"fare_type"`
+	JourneyID   uint64            `json:"journey_id,omitempty"`
+	Passengers  []ledgerPassenger `json:"passengers"`
+}
+
+type ledgerPassenger struct {
+	Position    uint32     `json:"position"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Email       string     `json:"email"`
+	Address     string     `json:"address"`
+	Section     string     `json:"section"`
+	Seat        uint32     `json:"seat"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	BoardedAt   *time.Time `json:"boarded_at,omitempty"`
+	UsedAt      *time.Time `json:"used_at,omitempty"`
+}
+
+// loadLedgerState reads the current state of a ticket from the projections.
+func loadLedgerState(ctx context.Context, tx *sql.Tx, ticketID uint64) (*ledgerState, error) {
+	st := &ledgerState{}
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(status, ''), COALESCE(departure_id, 0), from_stop, to_stop, COALESCE(account_id, 0), price_paid, fare_type,
+		COALESCE(journey_id, 0) FROM tickets WHERE id = $1`, ticketID,
+	).Scan(&st.Status, &st.DepartureID, &st.FromStop, &st.ToStop, &st.AccountID, &st.PricePaid, &st.FareType, &st.JourneyID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT position, first_name, last_name, email, address, section, seat, checked_in_at, boarded_at, used_at
+		FROM ticket_passengers WHERE ticket_id = $1 ORDER BY position`, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p ledgerPassenger
+		var checkedIn, boarded, used sql.NullTime
+		err := rows.Scan(&p.Position, &p.FirstName, &p.LastName, &p.Email, &p.Address, &p.Section, &p.Seat, &checkedIn, &boarded, &used)
+		if err != nil {
+			return nil, err
+		}
+		p.CheckedInAt, p.BoardedAt, p.UsedAt = utcTime(checkedIn), utcTime(boarded), utcTime(used)
+		st.Passengers = append(st.Passengers, p)
+	}
+	return st, rows.Err()
+}
+
+func utcTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	u := t.Time.UTC()
+	return &u
+}
+
+// restoreTicket writes a ticket's rows in the projections to match st,
+// creating them if they are missing.
+func restoreTicket(ctx context.Context, tx *sql.Tx, ticketID uint64, st *ledgerState) error {
+	if len(st.Passengers) == 0 {
+		return fmt.Errorf("ticket %d: ledger state has no passengers", ticketID)
+	}
+	lead := st.Passengers[0]
+	// Accounts can be deleted; their tickets become anonymous, as they
+	// would have through the foreign key. A journey missing from the table
+	// is left out the same way.
+	_, err := tx.ExecContext(ctx, `INSERT INTO tickets (id, passenger_name, email, section, seat, status, departure_id, account_id, price_paid, fare_type, from_stop, to_stop, journey_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), (SELECT id FROM accounts WHERE id = $8), $9, $10, $11, $12,
+			(SELECT id FROM journeys WHERE id = $13))
+		ON CONFLICT (id) DO UPDATE SET passenger_name = EXCLUDED.passenger_name, email = EXCLUDED.email,
+			section = EXCLUDED.section, seat = EXCLUDED.seat, status = EXCLUDED.status, departure_id = EXCLUDED.departure_id,
+			account_id = EXCLUDED.account_id, price_paid = EXCLUDED.price_paid, fare_type = EXCLUDED.fare_type,
+			from_stop = EXCLUDED.from_stop, to_stop = EXCLUDED.to_stop, journey_id = EXCLUDED.journey_id`,
+		ticketID, lead.FirstName, lead.Email, lead.Section, lead.Seat, st.Status, st.DepartureID, st.AccountID, st.PricePaid, st.FareType,
+		st.FromStop, st.ToStop, st.JourneyID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ticket_passengers WHERE ticket_id = $1", ticketID); err != nil {
+		return err
+	}
+	for _, p := range st.Passengers {
+		_, err := tx.ExecContext(ctx, `INSERT INTO ticket_passengers
+			(ticket_id, position, first_name, last_name, email, address, section, seat, checked_in_at, boarded_at, used_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			ticketID, p.Position, p.FirstName, p.LastName, p.Email, p.Address, p.Section, p.Seat, p.CheckedInAt, p.BoardedAt, p.UsedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importTickets records a TicketImported event for every ticket the ledger
+// has no state of, i.e. those booked before it kept one, so that the
+// ledger covers every ticket from then on. It returns how many it
+// recorded.
+func (s *TicketReservationServer) importTickets(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM tickets t
+		WHERE NOT EXISTS (SELECT 1 FROM event_outbox e WHERE e.ticket_no = t.id AND e.state IS NOT NULL)
+		ORDER BY id`)
+	if err != nil {
+		return 0, err
+	}
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err := s.recordEvent(ctx, tx, "TicketImported", id, nil); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), tx.Commit()
+}
+
+func (a *TicketAdminServer) RebuildProjections(ctx context.Context, req *pb.RebuildProjectionsRequest) (*pb.RebuildProjectionsResponse, error) {
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	if !req.DryRun {
+		if err := seedSeats(a.srv.db, a.srv.cfg); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+		}
+	}
+	tx, err := a.srv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	type entry struct {
+		ticketNo uint64
+		typ      string
+		state    []byte
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT ON (ticket_no) ticket_no, type, state FROM event_outbox
+		WHERE state IS NOT NULL ORDER BY ticket_no, sequence DESC`)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	var ledger []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.ticketNo, &e.typ, &e.state); err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		ledger = append(ledger, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	resp := &pb.RebuildProjectionsResponse{Tickets: uint32(len(ledger))}
+	for _, e := range ledger {
+		cur, err := loadLedgerState(ctx, tx, e.ticketNo)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+		}
+		exists := err == nil
+
+		if e.typ == "TicketCancelled" {
+			if exists {
+				if _, err := tx.ExecContext(ctx, "DELETE FROM tickets WHERE id = $1", e.ticketNo); err != nil {
+					return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+				}
+				resp.Deleted = append(resp.Deleted, e.ticketNo)
+			}
+			continue
+		}
+
+		var want ledgerState
+		if err := json.Unmarshal(e.state, &want); err != nil {
+			return nil, status.Errorf(codes.Internal, "ticket %d: decoding ledger state: %v", e.ticketNo, err)
+		}
+		if want.ToStop == 0 {
+			// Recorded before departures had stops on the way: end to end.
+			want.ToStop = 1
+		}
+		if exists {
+			have, _ := json.Marshal(cur)
+			wanted, _ := json.Marshal(&want)
+			if bytes.Equal(have, wanted) {
+				continue
+			}
+		}
+		if err := restoreTicket(ctx, tx, e.ticketNo, &want); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+		resp.Rewritten = append(resp.Rewritten, e.ticketNo)
+	}
+
+	rows, err = tx.QueryContext(ctx, `SELECT id FROM tickets t
+		WHERE NOT EXISTS (SELECT 1 FROM event_outbox e WHERE e.ticket_no = t.id AND e.state IS NOT NULL)
+		ORDER BY id`)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		resp.Unrecorded = append(resp.Unrecorded, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	if resp.Seats, err = reindexSeats(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	logger := logging.FromContext(ctx)
+	if req.DryRun {
+		logger.InfoContext(ctx, "projection rebuild checked", "tickets", resp.Tickets,
+			"rewritten", len(resp.Rewritten), "deleted", len(resp.Deleted), "unrecorded", len(resp.Unrecorded))
+		return resp, nil
+	}
+	// Restored tickets keep their numbers, so new ones must start above
+	// every number the ledger has used. Sequences ignore rollbacks, hence
+	// not in a dry run.
+	_, err = tx.ExecContext(ctx, `SELECT setval(pg_get_serial_sequence('tickets', 'id'),
+		GREATEST(nextval(pg_get_serial_sequence('tickets', 'id')), (SELECT max(ticket_no) FROM event_outbox)))`)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+	logger.InfoContext(ctx, "projections rebuilt from the ledger", "tickets", resp.Tickets,
+		"rewritten", len(resp.Rewritten), "deleted", len(resp.Deleted), "unrecorded", len(resp.Unrecorded))
+	a.srv.events.publish("ProjectionsRebuilt", 0, seatRef{})
+	return resp, nil
+}
+
+func (a *TicketAdminServer) GetSeatOccupancy(ctx context.Context, req *pb.SeatOccupancyRequest) (*pb.SeatOccupancy, error) {
+	if req.DepartureId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "departure_id required")
+	}
+	at := time.Now()
+	if req.At != nil {
+		at = req.At.AsTime()
+	}
+
+	// The latest state of each ticket recorded up to the time, unless the
+	// ticket was cancelled by then or had given its seats up.
+	rows, err := a.srv.db.QueryContext(ctx, `SELECT x.p->>'section', (x.p->>'seat')::int, e.ticket_no, (x.p->>'position')::int,
+		trim(concat_ws(' ', x.p->>'first_name', x.p->>'last_name')), e.state->>'status', e.id, e.type, e.created_at,
+		COALESCE(a.code, ''), COALESCE(b.code, '')
+		FROM (SELECT DISTINCT ON (ticket_no) id, ticket_no, type, state, created_at FROM event_outbox
+			WHERE state IS NOT NULL AND created_at <= $2 ORDER BY ticket_no, sequence DESC) e
+		CROSS JOIN LATERAL jsonb_array_elements(e.state->'passengers') x(p)
+		LEFT JOIN departure_stops a ON a.departure_id = $1 AND a.stop = COALESCE((e.state->>'from_stop')::int, 0)
+		LEFT JOIN departure_stops b ON b.departure_id = $1 AND b.stop = COALESCE(NULLIF((e.state->>'to_stop')::int, 0), 1)
+		WHERE e.type <> 'TicketCancelled' AND e.state->>'status' <> $3
+			AND (e.state->>'departure_id')::bigint = $1
+			AND ($4 = '' OR x.p->>'section' = $4)
+			AND ($5 = 0 OR (x.p->>'seat')::int = $5)
+		ORDER BY 1, 2, 3`,
+		req.DepartureId, at, statusPaymentFailed, req.Section, req.Seat)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.SeatOccupancy{DepartureId: req.DepartureId, At: timestamppb.New(at)}
+	for rows.Next() {
+		seat := &pb.SeatOccupancy_Seat{}
+		var recorded time.Time
+		err := rows.Scan(&seat.Section, &seat.Seat, &seat.TicketNo, &seat.Position, &seat.PassengerName, &seat.Status,
+			&seat.EventId, &seat.EventType, &recorded, &seat.FromCode, &seat.ToCode)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		seat.RecordedAt = timestamppb.New(recorded)
+		resp.Seats = append(resp.Seats, seat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kinds of notification email. Each has a <kind>.txt and a <kind>.html
+// template under mail/.
+const (
+	mailConfirmation = "confirmation"
+	mailModification = "modification"
+	mailCancellation = "cancellation"
+	mailReminder     = "reminder"
+	mailPromotion    = "promotion"
+)
+
+//go:embed mail/*
+var mailFS embed.FS
+
+// mailer renders notification emails. The text template of each kind
+// defines "subject" and "text", which can use "details" from
+// mail/details.txt; the HTML one is rendered inside mail/layout.html.
+type mailer struct {
+	from     *mail.Address
+	currency string
+	text     map[string]*texttemplate.Template
+	html     map[string]*htmltemplate.Template
+}
+
+// mailData is what the templates see.
+type mailData struct {
+	Ticket    *pb.ReservationResponse
+	Lead      *pb.UserDetails
+	Departure departureInfo
+	Refund    *pb.Refund
+	Currency  string
+	// Waitlist is set instead of Ticket for promotion emails.
+	Waitlist *waitlistMail
+}
+
+// waitlistMail is the promoted waitlist entry a promotion email is about.
+type waitlistMail struct {
+	ID               uint64
+	Passengers       int
+	FromCode, ToCode string
+	ClaimBy          time.Time
+}
+
+var mailFuncs = map[string]any{
+	"money": formatAmount,
+	"when":  func(t time.Time) string { return t.UTC().Format("Mon 2 Jan 2006 15:04 UTC") },
+	"inc":   func(i int) int { return i + 1 },
+}
+
+func newMailer(cfg config) (*mailer, error) {
+	from, err := mail.ParseAddress(cfg.SMTPFrom)
+	if err != nil {
+		return nil, fmt.Errorf("SMTP_FROM: %w", err)
+	}
+	layout, err := htmltemplate.New("layout.html").Funcs(mailFuncs).ParseFS(mailFS, "mail/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	m := &mailer{from: from, currency: cfg.Currency,
+		text: map[string]*texttemplate.Template{}, html: map[string]*htmltemplate.Template{}}
+	for _, kind := range []string{mailConfirmation, mailModification, mailCancellation, mailReminder, mailPromotion} {
+		if m.text[kind], err = texttemplate.New(kind+".txt").Funcs(mailFuncs).ParseFS(mailFS, "mail/details.txt", "mail/"+kind+".txt"); err != nil {
+			return nil, err
+		}
+		if m.html[kind], err = htmltemplate.Must(layout.Clone()).ParseFS(mailFS, "mail/"+kind+".html"); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// render returns the subject and both bodies of an email.
+func (m *mailer) render(kind string, data mailData) (subject, text, html string, err error) {
+	var b strings.Builder
+	if err := m.text[kind].ExecuteTemplate(&b, "subject", data); err != nil {
+		return "", "", "", err
+	}
+	subject = strings.TrimSpace(b.String())
+	b.Reset()
+	if err := m.text[kind].ExecuteTemplate(&b, "text", data); err != nil {
+		return "", "", "", err
+	}
+	text = b.String()
+	b.Reset()
+	if err := m.html[kind].ExecuteTemplate(&b, "layout.html", data); err != nil {
+		return "", "", "", err
+	}
+	return subject, text, b.String(), nil
+}
+
+// notify queues an email about the ticket to its lead passenger in the
+// outbox, within the transaction that changes the ticket so the email is
+// sent if and only if the change is committed. refund is only given for
+// cancellations. Nothing is queued when SMTP is not configured.
+func (s *TicketReservationServer) notify(ctx context.Context, tx *sql.Tx, kind string, ticketID uint64, refund *pb.Refund) error {
+	if s.mail == nil {
+		return nil
+	}
+	t, err := scanTicket(tx.QueryRowContext(ctx, ticketSelect+" WHERE t.id = $1", ticketID))
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	lead := t.Passengers[0]
+	if lead.Email == "" {
+		return nil
+	}
+	dep, err := s.departureInfo(ctx, tx, ticketID)
+	if err != nil {
+		return err
+	}
+	subject, text, html, err := s.mail.render(kind, mailData{Ticket: t, Lead: lead, Departure: dep, Refund: refund, Currency: s.mail.currency})
+	if err != nil {
+		return status.Errorf(codes.Internal, "rendering %s email: %v", kind, err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO mail_outbox (kind, ticket_no, recipient, subject, text_body, html_body)
+		VALUES ($1, $2, $3, $4, $5, $6)`, kind, ticketID, lead.Email, subject, text, html)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "email queued", "kind", kind, "ticket_no", ticketID, logging.Email("to", lead.Email))
+	return nil
+}
+
+// notifyPromotion queues the email telling the lead passenger of a promoted
+// waitlist entry that seats are held for them until claimBy, within the
+// transaction that holds the seats. Nothing is queued when SMTP is not
+// configured.
+func (s *TicketReservationServer) notifyPromotion(ctx context.Context, tx *sql.Tx, e waitingEntry, lead *pb.UserDetails, claimBy time.Time) error {
+	if s.mail == nil || lead == nil || lead.Email == "" {
+		return nil
+	}
+	w := &waitlistMail{ID: e.ID, Passengers: e.Passengers, ClaimBy: claimBy}
+	var dep departureInfo
+	err := tx.QueryRowContext(ctx, `SELECT d.train, a.code, b.code, a.departs_at, b.arrives_at
+		FROM departures d JOIN departure_stops a ON a.departure_id = d.id AND a.stop = $2
+			JOIN departure_stops b ON b.departure_id = d.id AND b.stop = $3
+		WHERE d.id = $1`, e.Ride.DepartureID, e.Ride.From, e.Ride.To).Scan(&dep.Train, &w.FromCode, &w.ToCode, &dep.Departs, &dep.Arrives)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	subject, text, html, err := s.mail.render(mailPromotion, mailData{Lead: lead, Departure: dep, Waitlist: w, Currency: s.mail.currency})
+	if err != nil {
+		return status.Errorf(codes.Internal, "rendering %s email: %v", mailPromotion, err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO mail_outbox (kind, waitlist_id, recipient, subject, text_body, html_body)
+		VALUES ($1, $2, $3, $4, $5, $6)`, mailPromotion, e.ID, lead.Email, subject, text, html)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "email queued", "kind", mailPromotion, "waitlist_id", e.ID, logging.Email("to", lead.Email))
+	return nil
+}
+
+// sendMail delivers the outbox every few seconds while the server runs.
+// Sending does not touch tickets or seats, so it runs without s.mu and a
+// slow SMTP server never holds up bookings.
+func (s *TicketReservationServer) sendMail(logger *slog.Logger) {
+	for range time.Tick(5 * time.Second) {
+		if err := s.deliverOutbox(context.Background(), logger); err != nil {
+			logger.Error("mail delivery failed", "error", err)
+		}
+	}
+}
+
+// outboxMail is a queued email.
+type outboxMail struct {
+	ID                             uint64
+	Attempts                       int
+	Recipient, Subject, Text, HTML string
+}
+
+// deliverOutbox sends the emails that are due. A failed email is retried
+// with exponential backoff from MAIL_RETRY_BASE and given up after
+// MAIL_MAX_ATTEMPTS attempts.
+func (s *TicketReservationServer) deliverOutbox(ctx context.Context, logger *slog.Logger) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, attempts, recipient, subject, text_body, html_body FROM mail_outbox
+		WHERE status = 'PENDING' AND next_attempt_at <= now() ORDER BY id LIMIT 50`)
+	if err != nil {
+		return err
+	}
+	var due []outboxMail
+	for rows.Next() {
+		var m outboxMail
+		if err := rows.Scan(&m.ID, &m.Attempts, &m.Recipient, &m.Subject, &m.Text, &m.HTML); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range due {
+		msg, err := s.mail.message(m)
+		if err == nil {
+			err = s.cfg.sendSMTP(s.mail.from.Address, m.Recipient, msg)
+		}
+		if err == nil {
+			_, err = s.db.ExecContext(ctx, "UPDATE mail_outbox SET status = 'SENT', attempts = attempts + 1, sent_at = now(), last_error = '' WHERE id = $1", m.ID)
+			if err != nil {
+				return err
+			}
+			logger.Info("email sent", "mail_id", m.ID, logging.Email("to", m.Recipient))
+			continue
+		}
+
+		attempts := m.Attempts + 1
+		st, retry := "PENDING", retryBackoff(s.cfg.MailRetryBase, attempts)
+		if attempts >= s.cfg.MailMaxAttempts {
+			st = "FAILED"
+		}
+		logger.Warn("email not sent", "mail_id", m.ID, "attempts", attempts, "status", st, "retry_in", retry, "error", err)
+		_, err = s.db.ExecContext(ctx, "UPDATE mail_outbox SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4 WHERE id = $5",
+			st, attempts, time.Now().Add(retry), err.Error(), m.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryBackoff is how long to wait before the next attempt after attempts
+// failed ones: base, doubling each time, at most six hours.
+func retryBackoff(base time.Duration, attempts int) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < 6*time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, 6*time.Hour)
+}
+
+// message builds the MIME message of a queued email, with the text and
+// HTML bodies as alternatives. The Message-ID stays the same across
+// retries so receivers can drop duplicates.
+func (m *mailer) message(o outboxMail) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range []struct{ typ, content string }{
+		{"text/plain; charset=utf-8", o.Text},
+		{"text/html; charset=utf-8", o.HTML},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.typ},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	_, domain, _ := strings.Cut(m.from.Address, "@")
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", (&mail.Address{Address: o.Recipient}).String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", o.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <outbox-%d@%s>\r\n", o.ID, domain)
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", w.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// sendSMTP delivers one message through SMTP_ADDR, upgrading to TLS when
+// the server offers STARTTLS and authenticating when SMTP_USERNAME is set.
+func (c config) sendSMTP(from, to string, msg []byte) error {
+	conn, err := net.DialTimeout("tcp", c.SMTPAddr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	host, _, _ := net.SplitHostPort(c.SMTPAddr)
+	cl, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer cl.Close()
+	if ok, _ := cl.Extension("STARTTLS"); ok {
+		if err := cl.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if c.SMTPUsername != "" {
+		if err := cl.Auth(smtp.PlainAuth("", c.SMTPUsername, c.SMTPPassword, host)); err != nil {
+			return err
+		}
+	}
+	if err := cl.Mail(from); err != nil {
+		return err
+	}
+	if err := cl.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := cl.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return cl.Quit()
+}
+
+// remindTravellers queues a reminder for every ticket whose train leaves
+// within REMINDER_BEFORE, once per ticket, every minute while the server
+// runs.
+func (s *TicketReservationServer) remindTravellers(logger *slog.Logger) {
+	for range time.Tick(time.Minute) {
+		s.mu.Lock()
+		err := s.queueReminders(context.Background(), logger)
+		s.mu.Unlock()
+		if err != nil {
+			logger.Error("queueing reminders failed", "error", err)
+		}
+	}
+}
+
+func (s *TicketReservationServer) queueReminders(ctx context.Context, logger *slog.Logger) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `UPDATE tickets t SET reminded_at = now() FROM departure_stops d
+		WHERE d.departure_id = t.departure_id AND d.stop = t.from_stop AND t.reminded_at IS NULL AND d.departs_at > now() AND d.departs_at <= $1
+		AND t.status IN ('Confirmed', 'Modified', $2)
+		RETURNING t.id`, time.Now().Add(s.cfg.ReminderBefore), statusCheckedIn)
+	if err != nil {
+		return err
+	}
+	var due []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range due {
+		if err := s.notify(ctx, tx, mailReminder, id, nil); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if len(due) > 0 {
+		logger.Info("reminders queued", "count", len(due))
+	}
+	return nil
+}
@@ -2,80 +2,320 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"database/sql"
-	"log"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
 	"net"
 	"os"
+	"slices"
 	"sync"
 
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	"github.com/Akash-private/Cloudbees_code/internal/tickettoken"
 	pb "github.com/Akash-private/Cloudbees_code/proto"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
 
+const grpcPort = ":50051"
+
 type TicketReservationServer struct {
 	pb.UnimplementedTicketReservationServer
-	mu sync.Mutex
-	db *sql.DB
+	mu       sync.Mutex
+	db       *sql.DB
+	cfg      config
+	events   *broker
+	payments PaymentProvider
+	// mail is nil when SMTP is not configured.
+	mail    *mailer
+	layouts layoutCache
 }
 
 func main() {
+	logger := logging.New("grpc-server")
+	slog.SetDefault(logger)
+	cfg, err := loadConfig()
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+
+	if cfg.TicketKey == nil {
+		logger.Warn("TICKET_SIGNING_KEY not set, issued tickets will not verify after a restart")
+		_, cfg.TicketKey, _ = ed25519.GenerateKey(rand.Reader)
+	}
+	pub := cfg.TicketKey.Public().(ed25519.PublicKey)
+	logger.Info("ticket tokens are verified with this public key",
+		"public_key", base64.StdEncoding.EncodeToString(pub), "key_id", tickettoken.KeyID(pub))
+
 	// Connect to DB via Environment Variable
-	dbURL := os.Getenv("DATABASE_URL")
-	db, err := sql.Open("postgres", dbURL)
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
-		log.Fatalf("Could not connect to DB: %v", err)
+		fatal("could not connect to DB", err)
 	}
 	defer db.Close()
 
-	// Setup Database Table
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS tickets (
-		id SERIAL PRIMARY KEY,
-		passenger_name TEXT,
-		email TEXT,
-		section TEXT,
-		seat INT,
-		status TEXT
-	)`)
-	if err != nil {
-		log.Fatalf("Table creation failed: %v", err)
+	// Setup Database Tables
+	if err := migrate(db, cfg, logger); err != nil {
+		fatal("schema migration failed", err)
 	}
 
 	// Start Listener
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", grpcPort)
+	if err != nil {
+		fatal("failed to listen", err)
+	}
+
+	srv := &TicketReservationServer{db: db, cfg: cfg, events: newBroker()}
+	srv.payments, err = newPaymentProvider(cfg, srv.paymentWebhook)
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+	if n, err := srv.importTickets(context.Background()); err != nil {
+		fatal("ledger import failed", err)
+	} else if n > 0 {
+		logger.Info("tickets booked before the ledger were imported into it", "count", n)
+	}
+	go srv.extendTimetable(logger)
+	go srv.markNoShows(logger)
+	go srv.runWaitlist(logger)
+	go srv.expirePayments(logger)
+	go srv.refundPayments(logger)
+	sinks, err := newEventSinks(cfg.EventSinks)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		fatal("invalid configuration", err)
+	}
+	for _, sink := range sinks {
+		go srv.relayTo(logger.With("sink", sink.Name()), sink)
+	}
+	go srv.relayTo(logger.With("sink", "webhooks"), &webhookFanout{db: db})
+	go srv.deliverWebhooks(logger)
+	if len(sinks) == 0 {
+		logger.Warn("EVENT_SINKS not set, domain events are kept in the outbox until a sink is configured")
+	}
+	if cfg.SMTPAddr != "" {
+		if srv.mail, err = newMailer(cfg); err != nil {
+			fatal("invalid configuration", err)
+		}
+		go srv.sendMail(logger)
+		go srv.remindTravellers(logger)
+	} else {
+		logger.Warn("SMTP_ADDR not set, no notification emails are sent")
+	}
+	if len(cfg.APIKeys) == 0 {
+		logger.Warn("API_KEYS not set, every TicketReservation call is rejected")
+	}
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		logging.UnaryServerInterceptor(logger),
+		adminAuthInterceptor(cfg.AdminToken),
+		authInterceptor(cfg.APIKeys),
+	), grpc.ChainStreamInterceptor(
+		logging.StreamServerInterceptor(logger),
+		authStreamInterceptor(cfg.APIKeys),
+	))
+	pb.RegisterTicketReservationServer(s, srv)
+	if cfg.AdminToken != "" {
+		pb.RegisterTicketAdminServer(s, &TicketAdminServer{srv: srv})
+	} else {
+		logger.Warn("ADMIN_TOKEN not set, TicketAdmin service disabled")
+	}
+	if cfg.Reflection {
+		reflection.Register(s)
+		logger.Info("gRPC reflection enabled")
 	}
 
-	s := grpc.NewServer()
-	pb.RegisterTicketReservationServer(s, &TicketReservationServer{db: db})
+	go serveGateway(cfg.GatewayAddr, "localhost"+grpcPort, cfg.APIKeys, logger)
 
-	log.Println("🚆 gRPC Server with Postgres running on :50051")
+	logger.Info("gRPC server listening", "addr", lis.Addr().String())
 	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+		fatal("failed to serve", err)
 	}
 }
 
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// maxPassengers bounds the size of a single booking.
+const maxPassengers = 9
+
 func (s *TicketReservationServer) ReserveTicket(ctx context.Context, req *pb.ReservationRequest) (*pb.ReservationResponse, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var id uint64
+	if len(req.Passengers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one passenger required")
+	}
+	if len(req.Passengers) > maxPassengers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d passengers per booking", maxPassengers)
+	}
+
+	key := idempotencyKey(ctx)
+	if key != "" {
+		if prev, err := s.replay(ctx, key); prev != nil || err != nil {
+			return prev, err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	id, seats, err := s.reserveTx(ctx, tx, req, []string{req.HoldId})
+	if err != nil {
+		return nil, err
+	}
+	unmet, err := s.unmetPreferences(ctx, tx, req.Passengers, seats)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		if err := rememberKey(ctx, tx, key, id); err != nil {
+			return nil, err
+		}
+	}
+	// The seats are committed before the payment is taken, so the
+	// provider is not called inside the transaction.
+	order := ticketOrder(id)
+	if err := s.expectPayment(ctx, tx, order, id); err != nil {
+		return nil, err
+	}
+	if err := s.announceBooking(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
 	p := req.Passengers[0]
+	logging.FromContext(ctx).InfoContext(ctx, "ticket reserved",
+		"ticket_no", id, "passengers", len(seats), logging.Name("passenger", p.FirstName), logging.Email("email", p.Email))
+	for _, seat := range seats {
+		s.events.publish("TicketReserved", id, seat)
+	}
+	if err := s.pay(ctx, order, req.PaymentToken); err != nil {
+		return nil, err
+	}
 
-	// Default allocation for demo: Section A, Seat 1 (You can add logic to find next free seat)
-	err := s.db.QueryRow(
-		"INSERT INTO tickets (passenger_name, email, section, seat, status) VALUES ($1, $2, $3, $4, $5) RETURNING id",
-		p.FirstName, p.Email, "A", 1, "Confirmed",
+	resp, err := s.loadTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp.UnmetPreferences = unmet
+	return resp, nil
+}
+
+// reserveTx books req within tx and returns the new ticket and its seats.
+// holds[i], where given, is the hold that passenger i books and the
+// passengers who name a seat get that seat; the others are seated by
+// allocateSeats, as near each other and their preferences as it can.
+func (s *TicketReservationServer) reserveTx(ctx context.Context, tx *sql.Tx, req *pb.ReservationRequest, holds []string) (uint64, []seatRef, error) {
+	hold := func(i int) string {
+		if i < len(holds) {
+			return holds[i]
+		}
+		return ""
+	}
+
+	ft, err := s.cfg.fareType(req.FareType)
+	if err != nil {
+		return 0, nil, err
+	}
+	// The lead passenger's hold, if any, sets the departure and the stops.
+	ride := seatRef{DepartureID: req.DepartureId}
+	if hold(0) == "" {
+		ride, err = resolveRide(ctx, tx, req.DepartureId, req.FromCode, req.ToCode)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// Held and named seats are taken first so the allocator seats everyone
+	// else around them.
+	seats := make([]seatRef, len(req.Passengers))
+	var taken []seatRef
+	var rest []int
+	for i, p := range req.Passengers {
+		if hold(i) == "" && (p.Section == "" || p.Seat == 0) {
+			rest = append(rest, i)
+			continue
+		}
+		want := ride
+		want.Section, want.Seat = p.Section, p.Seat
+		got, err := pickSeat(ctx, tx, hold(i), want)
+		if err != nil {
+			return 0, nil, err
+		}
+		if slices.Contains(taken, got) {
+			return 0, nil, status.Errorf(codes.FailedPrecondition, "seat %s-%d is not available", got.Section, got.Seat)
+		}
+		if i == 0 {
+			ride.DepartureID, ride.From, ride.To = got.DepartureID, got.From, got.To
+		} else if got.From != ride.From || got.To != ride.To {
+			return 0, nil, status.Errorf(codes.FailedPrecondition, "hold %s is for other stops than the rest of the booking", hold(i))
+		}
+		seats[i] = got
+		taken = append(taken, got)
+	}
+	if len(rest) > 0 {
+		passengers := make([]*pb.UserDetails, len(rest))
+		for j, i := range rest {
+			passengers[j] = req.Passengers[i]
+		}
+		got, err := s.allocateSeats(ctx, tx, ride, passengers, taken)
+		if err != nil {
+			return 0, nil, err
+		}
+		for j, i := range rest {
+			seats[i] = got[j]
+		}
+	}
+
+	var id uint64
+	var account sql.NullInt64
+	if a := principalAccount(ctx); a != 0 {
+		account = sql.NullInt64{Int64: int64(a), Valid: true}
+	}
+	p := req.Passengers[0]
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO tickets (passenger_name, email, section, seat, status, departure_id, account_id, fare_type, from_stop, to_stop)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+		p.FirstName, p.Email, seats[0].Section, seats[0].Seat, "Confirmed", seats[0].DepartureID, account, ft.Name, ride.From, ride.To,
 	).Scan(&id)
 
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+		return 0, nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+
+	for i, p := range req.Passengers {
+		if err := occupySeat(ctx, tx, id, seats[i]); err != nil {
+			return 0, nil, err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO ticket_passengers (ticket_id, position, first_name, last_name, email, address, section, seat)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			id, i, p.FirstName, p.LastName, p.Email, p.Address, seats[i].Section, seats[i].Seat)
+		if err != nil {
+			return 0, nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+		}
 	}
 
-	return &pb.ReservationResponse{TicketNo: id, Status: "Booked Successfully", Passengers: req.Passengers}, nil
+	fare := s.cfg.fare(seats[0].DepartureID, ft, len(seats), chosenSections(req, seats))
+	if req.PricePaid != 0 && req.PricePaid != fare.Total {
+		return 0, nil, status.Errorf(codes.FailedPrecondition, "fare is now %s, not %s",
+			formatAmount(fare.Total, fare.Currency), formatAmount(req.PricePaid, fare.Currency))
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE tickets SET price_paid = $1 WHERE id = $2", fare.Total, id); err != nil {
+		return 0, nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return id, seats, nil
 }
 
 func (s *TicketReservationServer) ModifyTicket(ctx context.Context, req *pb.ReservationRequest) (*pb.ReservationResponse, error) {
@@ -85,16 +325,85 @@ func (s *TicketReservationServer) ModifyTicket(ctx context.Context, req *pb.Rese
 	if req.TicketNo == nil {
 		return nil, status.Error(codes.InvalidArgument, "ID required")
 	}
+	if len(req.Passengers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one passenger required")
+	}
 
 	p := req.Passengers[0]
-	_, err := s.db.Exec("UPDATE tickets SET section = $1, seat = $2, status = $3 WHERE id = $4",
-		p.Section, p.Seat, "Modified", *req.TicketNo)
+	if req.HoldId == "" && (p.Section == "" || p.Seat == 0) {
+		return nil, status.Error(codes.InvalidArgument, "section and seat required")
+	}
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
 
+	// Only the lead passenger's seat is changed, and only within the
+	// ticket's own departure and stops. Tickets of other accounts are reported as not
+	// found.
+	var old seatRef
+	var st string
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(departure_id, 0), COALESCE(section, ''), COALESCE(seat, 0), from_stop, to_stop, COALESCE(status, '')
+		FROM tickets WHERE id = $1 AND ($2 = 0 OR account_id = $2) FOR UPDATE`,
+		*req.TicketNo, owner).Scan(&old.DepartureID, &old.Section, &old.Seat, &old.From, &old.To, &st)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "ticket %d not found", *req.TicketNo)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if err := travelled(*req.TicketNo, st); err != nil {
+		return nil, err
+	}
+	if err := unpaid(*req.TicketNo, st); err != nil {
+		return nil, err
+	}
+	got, err := pickSeat(ctx, tx, req.HoldId, seatRef{DepartureID: old.DepartureID, Section: p.Section, Seat: p.Seat, From: old.From, To: old.To})
+	if err != nil {
+		return nil, err
+	}
+	if got.From != old.From || got.To != old.To {
+		return nil, status.Errorf(codes.FailedPrecondition, "hold %s is for other stops than ticket %d", req.HoldId, *req.TicketNo)
+	}
+
+	// A checked-in ticket stays checked in when its seat changes.
+	_, err = tx.ExecContext(ctx, `UPDATE tickets SET section = $1, seat = $2,
+		status = CASE WHEN status = $5 THEN status ELSE $3 END WHERE id = $4`,
+		got.Section, got.Seat, "Modified", *req.TicketNo, statusCheckedIn)
+
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE ticket_passengers SET section = $1, seat = $2 WHERE ticket_id = $3 AND position = 0",
+		got.Section, got.Seat, *req.TicketNo)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
 	}
+	if err := releaseSeat(ctx, tx, *req.TicketNo, old); err != nil {
+		return nil, err
+	}
+	if err := occupySeat(ctx, tx, *req.TicketNo, got); err != nil {
+		return nil, err
+	}
+	if err := s.recordEvent(ctx, tx, "TicketModified", *req.TicketNo, nil); err != nil {
+		return nil, err
+	}
+	if err := s.notify(ctx, tx, mailModification, *req.TicketNo, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+	s.events.publish("TicketModified", *req.TicketNo, got)
 
-	return &pb.ReservationResponse{TicketNo: *req.TicketNo, Status: "Modification Saved"}, nil
+	return &pb.ReservationResponse{TicketNo: *req.TicketNo, Status: "Modification Saved", DepartureId: got.DepartureID,
+		Passengers: []*pb.UserDetails{{Section: got.Section, Seat: got.Seat}}}, nil
 }
 
 func (s *TicketReservationServer) CancelTicket(ctx context.Context, req *pb.ReservationRequest) (*pb.ReservationResponse, error) {
@@ -105,19 +414,101 @@ func (s *TicketReservationServer) CancelTicket(ctx context.Context, req *pb.Rese
 		return nil, status.Error(codes.InvalidArgument, "ID required")
 	}
 
-	_, err := s.db.Exec("DELETE FROM tickets WHERE id = $1", *req.TicketNo)
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	refund, err := s.cancellationQuote(ctx, tx, *req.TicketNo, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRefund(ctx, tx, refund); err != nil {
+		return nil, err
+	}
+	if err := s.settleRefund(ctx, tx, *req.TicketNo, refund.Amount); err != nil {
+		return nil, err
+	}
+	if err := s.recordEvent(ctx, tx, "TicketCancelled", *req.TicketNo, refund); err != nil {
+		return nil, err
+	}
+	if err := s.notify(ctx, tx, mailCancellation, *req.TicketNo, refund); err != nil {
+		return nil, err
+	}
+
+	// The seats of all passengers are released by the foreign keys; read
+	// them first so each freed seat can be announced.
+	freed, err := ticketSeats(ctx, tx, *req.TicketNo)
+	if err != nil {
+		return nil, err
+	}
+	var lead seatRef
+	err = tx.QueryRowContext(ctx,
+		"DELETE FROM tickets WHERE id = $1 RETURNING COALESCE(departure_id, 0), section, seat",
+		*req.TicketNo,
+	).Scan(&lead.DepartureID, &lead.Section, &lead.Seat)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "DB Delete Error: %v", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "ticket cancelled", "ticket_no", *req.TicketNo,
+		"refund", refund.Amount, "fee", refund.Fee, "policy", refund.Policy)
 
-	return &pb.ReservationResponse{TicketNo: *req.TicketNo, Status: "Ticket Cancelled/Deleted"}, nil
+	if len(freed) == 0 {
+		freed = []seatRef{lead}
+	}
+	for _, seat := range freed {
+		s.events.publish("TicketCancelled", *req.TicketNo, seat)
+	}
+	// The freed seats go to the waitlist first; the cancellation itself has
+	// succeeded even if that fails, and the sweep will retry.
+	if lead.DepartureID != 0 {
+		if err := s.promoteWaitlist(ctx, lead.DepartureID); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "waitlist promotion failed", "departure_id", lead.DepartureID, "error", err)
+		}
+	}
+	return &pb.ReservationResponse{TicketNo: *req.TicketNo, Status: "Ticket Cancelled/Deleted", DepartureId: lead.DepartureID,
+		PricePaid: refund.PricePaid, FareType: refund.FareType, Refund: refund}, nil
+}
+
+// ticketSeats returns the seats occupied by the ticket's passengers.
+func ticketSeats(ctx context.Context, tx *sql.Tx, ticketID uint64) ([]seatRef, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT COALESCE(t.departure_id, 0), p.section, p.seat, t.from_stop, t.to_stop
+		FROM ticket_passengers p JOIN tickets t ON t.id = p.ticket_id
+		WHERE p.ticket_id = $1 ORDER BY p.position`, ticketID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	var seats []seatRef
+	for rows.Next() {
+		var ref seatRef
+		if err := rows.Scan(&ref.DepartureID, &ref.Section, &ref.Seat, &ref.From, &ref.To); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		seats = append(seats, ref)
+	}
+	return seats, rows.Err()
 }
 
 func (s *TicketReservationServer) GetAllTickets(ctx context.Context, req *pb.EmptyRequest) (*pb.AllTicketsResponse, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	rows, err := s.db.Query("SELECT id, passenger_name, email, section, seat, status FROM tickets ORDER BY id DESC")
+	// Customers only see their own tickets.
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, ticketSelect+" WHERE ($1 = 0 OR t.account_id = $1) ORDER BY t.id DESC", owner)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
 	}
@@ -125,14 +516,145 @@ func (s *TicketReservationServer) GetAllTickets(ctx context.Context, req *pb.Emp
 
 	var tickets []*pb.ReservationResponse
 	for rows.Next() {
-		var t pb.ReservationResponse
-		var p pb.UserDetails
-		err := rows.Scan(&t.TicketNo, &p.FirstName, &p.Email, &p.Section, &p.Seat, &t.Status)
+		t, err := scanTicket(rows)
 		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "skipping unreadable ticket row", "error", err)
 			continue
 		}
-		t.Passengers = []*pb.UserDetails{&p}
-		tickets = append(tickets, &t)
+		tickets = append(tickets, t)
+	}
+
+	return &pb.AllTicketsResponse{Tickets: tickets}, nil
+}
+
+func (s *TicketReservationServer) GetTicket(ctx context.Context, req *pb.ReservationRequest) (*pb.ReservationResponse, error) {
+	if req.TicketNo == nil {
+		return nil, status.Error(codes.InvalidArgument, "ID required")
+	}
+	// The response carries the boarding tokens, so customers only get
+	// their own tickets.
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.loadTicket(ctx, *req.TicketNo)
+	if err != nil {
+		return nil, err
+	}
+	if owner != 0 && t.AccountId != owner {
+		return nil, status.Errorf(codes.NotFound, "ticket %d not found", *req.TicketNo)
+	}
+	return t, nil
+}
+
+// ticketSelect reads tickets together with the stops they travel between.
+// Rows must be read with scanTicket.
+const ticketSelect = `SELECT t.id, t.passenger_name, t.email, t.section, t.seat, t.status,
+	COALESCE(t.departure_id, 0), COALESCE(a.code, ''), COALESCE(b.code, ''), COALESCE(t.account_id, 0), t.price_paid, t.fare_type,
+	COALESCE(t.journey_id, 0),
+	pay.provider, pay.reference, pay.status, pay.amount, pay.refunded, pay.currency, pay.reason,
+	(SELECT json_agg(json_build_object('first_name', p.first_name, 'last_name', p.last_name, 'email', p.email,
+		'address', p.address, 'section', p.section, 'seat', p.seat) ORDER BY p.position)
+		FROM ticket_passengers p WHERE p.ticket_id = t.id)
+	FROM tickets t LEFT JOIN departure_stops a ON a.departure_id = t.departure_id AND a.stop = t.from_stop
+		LEFT JOIN departure_stops b ON b.departure_id = t.departure_id AND b.stop = t.to_stop
+		LEFT JOIN payments pay ON pay.ticket_id = t.id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// passengerRow is one element of the passengers JSON array in ticketSelect.
+type passengerRow struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Address   string `json:"address"`
+	Section   string `json:"section"`
+	Seat      uint32 `json:"seat"`
+}
+
+func scanTicket(row rowScanner) (*pb.ReservationResponse, error) {
+	var t pb.ReservationResponse
+	var lead pb.UserDetails
+	var passengers []byte
+	var provider, reference, payment, currency, reason sql.NullString
+	var amount, refunded sql.NullInt64
+	err := row.Scan(&t.TicketNo, &lead.FirstName, &lead.Email, &lead.Section, &lead.Seat, &t.Status,
+		&t.DepartureId, &t.FromCode, &t.ToCode, &t.AccountId, &t.PricePaid, &t.FareType, &t.JourneyId,
+		&provider, &reference, &payment, &amount, &refunded, &currency, &reason, &passengers)
+	if err != nil {
+		return nil, err
+	}
+	t.Payment = scanPayment(provider, reference, payment, currency, reason, amount, refunded)
+	var rows []passengerRow
+	if passengers != nil {
+		if err := json.Unmarshal(passengers, &rows); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range rows {
+		t.Passengers = append(t.Passengers, &pb.UserDetails{FirstName: p.FirstName, LastName: p.LastName,
+			Email: p.Email, Address: p.Address, Section: p.Section, Seat: p.Seat})
+	}
+	if len(t.Passengers) == 0 {
+		t.Passengers = []*pb.UserDetails{&lead}
+	}
+	t.PassengerCount = uint64(len(t.Passengers))
+	return &t, nil
+}
+
+func (s *TicketReservationServer) loadTicket(ctx context.Context, id uint64) (*pb.ReservationResponse, error) {
+	t, err := scanTicket(s.db.QueryRowContext(ctx, ticketSelect+" WHERE t.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "ticket %d not found", id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	dep, err := s.departureInfo(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	// Tickets are only valid for travel once paid for.
+	if unpaid(t.TicketNo, t.Status) == nil {
+		t.Tokens = s.cfg.issueTokens(t, dep)
+	}
+	return t, nil
+}
+
+func (s *TicketReservationServer) SearchTickets(ctx context.Context, req *pb.SearchRequest) (*pb.AllTicketsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Customers only find their own tickets.
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The name is matched as plain text: % and _ are not wildcards.
+	rows, err := s.db.QueryContext(ctx, ticketSelect+`
+		WHERE ($1 = '' OR strpos(lower(t.passenger_name), lower($1)) > 0)
+		AND ($2 = '' OR lower(t.email) = lower($2))
+		AND ($3 = '' OR t.section = $3)
+		AND ($4 = '' OR t.status = $4)
+		AND ($5 = 0 OR t.departure_id = $5)
+		AND ($6 = 0 OR t.account_id = $6)
+		AND ($7 = 0 OR t.account_id = $7)
+		ORDER BY t.id DESC`,
+		req.Name, req.Email, req.Section, req.Status, req.DepartureId, req.AccountId, owner)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+
+	var tickets []*pb.ReservationResponse
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		tickets = append(tickets, t)
 	}
 
 	return &pb.AllTicketsResponse{Tickets: tickets}, nil
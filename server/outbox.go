@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// domainEventTypes are the types of event recordEvent is called with.
+var domainEventTypes = []string{"TicketReserved", "TicketConfirmed", "TicketPaymentFailed", "TicketModified",
+	"TicketCheckedIn", "PassengerBoarded", "TicketConsumed", "TicketNoShow", "TicketCancelled", "TicketImported"}
+
+// recordEvent writes a domain event about the ticket to the event outbox
+// within tx, so the event exists if and only if the change is committed.
+// refund is only given for cancellations, which must record their event
+// before the ticket is deleted. The event also keeps the ticket's full
+// state for the ledger, so every change to a ticket must record one.
+//
+// Events are numbered in commit order because every transaction that
+// records one runs under s.mu; the relay depends on that to never skip an
+// event.
+func (s *TicketReservationServer) recordEvent(ctx context.Context, tx *sql.Tx, typ string, ticketID uint64, refund *pb.Refund) error {
+	t, err := scanTicket(tx.QueryRowContext(ctx, ticketSelect+" WHERE t.id = $1", ticketID))
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	e := &pb.DomainEvent{Type: typ, TicketNo: ticketID, OccurredAt: timestamppb.Now(), Ticket: t, Refund: refund}
+	err = tx.QueryRowContext(ctx, `SELECT nextval(pg_get_serial_sequence('event_outbox', 'id')),
+		(SELECT COALESCE(max(sequence), 0) + 1 FROM event_outbox WHERE ticket_no = $1)`, ticketID,
+	).Scan(&e.EventId, &e.Sequence)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	payload, err := jsonOut.Marshal(e)
+	if err != nil {
+		return status.Errorf(codes.Internal, "encoding event: %v", err)
+	}
+	st, err := loadLedgerState(ctx, tx, ticketID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	state, err := json.Marshal(st)
+	if err != nil {
+		return status.Errorf(codes.Internal, "encoding ticket state: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO event_outbox (id, ticket_no, sequence, type, payload, state) VALUES ($1, $2, $3, $4, $5, $6)",
+		e.EventId, ticketID, e.Sequence, typ, payload, state)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "domain event recorded", "event_id", e.EventId, "type", typ, "ticket_no", ticketID)
+	return nil
+}
+
+// outboxEvent is a recorded domain event as the sinks get it. Payload is
+// the DomainEvent as JSON.
+type outboxEvent struct {
+	ID       uint64
+	TicketNo uint64
+	Sequence uint64
+	Type     string
+	Payload  []byte
+}
+
+// relayTo delivers events to one sink in outbox order while the server
+// runs. Each sink has its own goroutine, so a sink that is down does not
+// hold up the others, and its own row in event_sink_offsets. An event is
+// only marked delivered after the sink accepted it, so after a failure or
+// restart the sink may see it again, but never out of order: a failed
+// event is retried, with backoff, before any later one.
+func (s *TicketReservationServer) relayTo(logger *slog.Logger, sink EventSink) {
+	ctx := context.Background()
+	backoff := time.Duration(0)
+	for {
+		n, err := s.relayBatch(ctx, sink)
+		switch {
+		case err != nil:
+			backoff = min(max(2*backoff, time.Second), 5*time.Minute)
+			logger.Warn("event delivery failed", "retry_in", backoff, "error", err)
+			time.Sleep(backoff)
+		case n == 0:
+			backoff = 0
+			time.Sleep(2 * time.Second)
+		default:
+			backoff = 0
+			logger.Info("events delivered", "count", n)
+		}
+	}
+}
+
+// relayBatch delivers the next events the sink has not had and returns
+// how many it delivered.
+func (s *TicketReservationServer) relayBatch(ctx context.Context, sink EventSink) (int, error) {
+	var offset uint64
+	err := s.db.QueryRowContext(ctx, "SELECT last_event_id FROM event_sink_offsets WHERE sink = $1", sink.Name()).Scan(&offset)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT id, ticket_no, sequence, type, payload FROM event_outbox
+		WHERE id > $1 ORDER BY id LIMIT 100`, offset)
+	if err != nil {
+		return 0, err
+	}
+	var batch []outboxEvent
+	for rows.Next() {
+		var e outboxEvent
+		if err := rows.Scan(&e.ID, &e.TicketNo, &e.Sequence, &e.Type, &e.Payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for i, e := range batch {
+		if err := sink.Deliver(ctx, e); err != nil {
+			return i, err
+		}
+		_, err := s.db.ExecContext(ctx, `INSERT INTO event_sink_offsets (sink, last_event_id, updated_at) VALUES ($1, $2, now())
+			ON CONFLICT (sink) DO UPDATE SET last_event_id = EXCLUDED.last_event_id, updated_at = now()`, sink.Name(), e.ID)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
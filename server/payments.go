@@ -0,0 +1,579 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Ticket statuses while the fare is not captured. A PaymentPending ticket
+// holds its seats until the provider answers; a PaymentFailed one has
+// released them.
+const (
+	statusPaymentPending = "PaymentPending"
+	statusPaymentFailed  = "PaymentFailed"
+)
+
+// Payment statuses, as stored and returned in pb.Payment.
+const (
+	paymentPending  = "PENDING"
+	paymentCaptured = "CAPTURED"
+	paymentDeclined = "DECLINED"
+	paymentVoided   = "VOIDED"
+	paymentRefunded = "REFUNDED"
+	// A cancelled ticket's payment is VOID_PENDING or REFUND_PENDING until
+	// issueRefunds has told the provider.
+	paymentVoidPending   = "VOID_PENDING"
+	paymentRefundPending = "REFUND_PENDING"
+)
+
+// PaymentProvider moves the money for bookings. Amounts are in minor units.
+// Authorize may leave the outcome pending; the provider then reports it
+// later through the PaymentWebhook it was created with.
+type PaymentProvider interface {
+	Name() string
+	Authorize(ctx context.Context, req PaymentRequest) (PaymentResult, error)
+	Capture(ctx context.Context, reference string, amount uint64) error
+	Void(ctx context.Context, reference string) error
+	Refund(ctx context.Context, reference string, amount uint64) error
+}
+
+// PaymentRequest asks to authorize Amount. OrderID is ours and lets the
+// provider spot a repeated request.
+type PaymentRequest struct {
+	OrderID  string
+	Amount   uint64
+	Currency string
+	Token    string
+}
+
+// PaymentResult is the provider's answer to Authorize. Reference identifies
+// the payment in later calls.
+type PaymentResult struct {
+	Reference string
+	Status    string // paymentPending, paymentDeclined, or "AUTHORIZED"
+	Reason    string
+}
+
+// paymentAuthorized is the status of an authorization that can be captured.
+const paymentAuthorized = "AUTHORIZED"
+
+// PaymentWebhook receives the outcome of a pending authorization.
+type PaymentWebhook func(reference string, authorized bool, reason string)
+
+// newPaymentProvider returns the provider named by PAYMENT_PROVIDER.
+func newPaymentProvider(cfg config, hook PaymentWebhook) (PaymentProvider, error) {
+	switch cfg.PaymentProvider {
+	case "fake":
+		return newFakePayments(cfg.FakePaymentDelay, hook), nil
+	}
+	return nil, fmt.Errorf("unknown PAYMENT_PROVIDER %q", cfg.PaymentProvider)
+}
+
+// unpaid fails for tickets whose fare has not been captured.
+func unpaid(ticketNo uint64, st string) error {
+	if st == statusPaymentPending || st == statusPaymentFailed {
+		return status.Errorf(codes.FailedPrecondition, "ticket %d is %s", ticketNo, st)
+	}
+	return nil
+}
+
+// ticketOrder is the order a ticket paid for on its own is known by to the
+// provider.
+func ticketOrder(id uint64) string {
+	return fmt.Sprintf("ticket-%d", id)
+}
+
+// expectPayment records in tx that the tickets, just booked, are to be paid
+// for together as order, e.g. "ticket-12". They are PaymentPending, holding
+// their seats, until pay has heard from the provider; their payments carry
+// order as the reference until the provider names its own.
+func (s *TicketReservationServer) expectPayment(ctx context.Context, tx *sql.Tx, order string, tickets ...uint64) error {
+	for _, id := range tickets {
+		_, err := tx.ExecContext(ctx, `INSERT INTO payments (ticket_id, ticket_no, provider, reference, status, amount, currency)
+			SELECT id, id, $2, $3, $4, price_paid, $5 FROM tickets WHERE id = $1`,
+			id, s.payments.Name(), order, paymentPending, s.cfg.Currency)
+		if err != nil {
+			return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tickets SET status = $1 WHERE id = $2", statusPaymentPending, id); err != nil {
+			return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+	}
+	return nil
+}
+
+// pay takes the payment expectPayment recorded for order, once that has
+// committed. Callers hold s.mu; pay releases it while the provider is
+// called, so a slow provider does not hold up other calls, and settles the
+// outcome in a transaction of its own. A declined or failed payment fails
+// the tickets, releasing their seats, and returns why. A pending one is
+// settled later by the provider's webhook or expirePayments.
+func (s *TicketReservationServer) pay(ctx context.Context, order, token string) error {
+	var amount uint64
+	err := s.db.QueryRowContext(ctx, "SELECT COALESCE(sum(amount), 0) FROM payments WHERE provider = $1 AND reference = $2",
+		s.payments.Name(), order).Scan(&amount)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	s.mu.Unlock()
+	res, payErr := s.authorize(ctx, order, amount, token)
+	s.mu.Lock()
+
+	reference := order
+	if res.Reference != "" {
+		_, err := s.db.ExecContext(ctx, "UPDATE payments SET reference = $1, updated_at = now() WHERE provider = $2 AND reference = $3",
+			res.Reference, s.payments.Name(), order)
+		if err != nil {
+			// The tickets fail once the payment has been pending for
+			// PAYMENT_PENDING_TTL; their money must not stay taken.
+			s.voidPayment(ctx, res.Reference)
+			return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+		reference = res.Reference
+	} else {
+		// Nothing was charged, so a cancellation meanwhile has nothing to
+		// void.
+		_, err := s.db.ExecContext(ctx, "UPDATE payments SET status = $1, updated_at = now() WHERE provider = $2 AND reference = $3 AND status = $4",
+			paymentVoided, s.payments.Name(), order, paymentVoidPending)
+		if err != nil {
+			return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+	}
+	if payErr == nil && res.Status == paymentPending {
+		logging.FromContext(ctx).InfoContext(ctx, "payment pending", "order", order, "reference", reference, "amount", amount)
+		return nil
+	}
+
+	captured := payErr == nil && res.Status == paymentCaptured
+	reason := res.Reason
+	if payErr != nil {
+		reason = status.Convert(payErr).Message()
+	}
+	settled, err := s.settlePayment(ctx, reference, captured, reason)
+	if err != nil {
+		if captured {
+			s.voidPayment(ctx, reference)
+		}
+		return status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	switch {
+	case !settled:
+		// The tickets were cancelled while the provider was asked.
+		if captured {
+			s.voidPayment(ctx, reference)
+		}
+		return status.Error(codes.Aborted, "the booking was cancelled while it was being paid for")
+	case payErr != nil:
+		return payErr
+	case !captured:
+		return status.Errorf(codes.Aborted, "payment declined: %s", res.Reason)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "payment taken", "order", order, "reference", reference, "amount", amount)
+	return nil
+}
+
+// authorize asks the provider for amount and captures it once authorized.
+// The result's status is paymentCaptured, paymentPending or
+// paymentDeclined. When the provider fails the error is for the caller;
+// nothing is charged then.
+func (s *TicketReservationServer) authorize(ctx context.Context, order string, amount uint64, token string) (PaymentResult, error) {
+	pctx, cancel := context.WithTimeout(ctx, s.cfg.PaymentTimeout)
+	defer cancel()
+	res, err := s.payments.Authorize(pctx, PaymentRequest{
+		OrderID:  order,
+		Amount:   amount,
+		Currency: s.cfg.Currency,
+		Token:    token,
+	})
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "payment authorization failed", "order", order, "error", err)
+		return PaymentResult{}, status.Error(codes.DeadlineExceeded, "the payment provider did not answer; nothing was charged")
+	}
+	if res.Status == paymentAuthorized {
+		if err := s.payments.Capture(pctx, res.Reference, amount); err != nil {
+			s.voidPayment(ctx, res.Reference)
+			logging.FromContext(ctx).WarnContext(ctx, "payment capture failed", "order", order, "error", err)
+			return res, status.Error(codes.DeadlineExceeded, "the payment could not be completed; nothing was charged")
+		}
+		res.Status = paymentCaptured
+	}
+	return res, nil
+}
+
+// announceBooking records the TicketReserved event of a new ticket.
+// settlePayment queues its confirmation email once the payment is captured.
+func (s *TicketReservationServer) announceBooking(ctx context.Context, tx *sql.Tx, ticketID uint64) error {
+	return s.recordEvent(ctx, tx, "TicketReserved", ticketID, nil)
+}
+
+// voidPayment releases an authorization or capture whose booking failed.
+// Failures are logged for someone to reconcile by hand.
+func (s *TicketReservationServer) voidPayment(ctx context.Context, reference string) {
+	pctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.PaymentTimeout)
+	defer cancel()
+	if err := s.payments.Void(pctx, reference); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "payment void failed, reconcile by hand", "reference", reference, "error", err)
+	}
+}
+
+// settleRefund records in tx that amount of the ticket's payment, or of its
+// journey's, is to be paid back as the ticket is cancelled, or that a payment still pending is
+// to be voided. issueRefunds tells the provider once tx has committed, so
+// no money moves for a cancellation that rolls back. Tickets booked before
+// payments were taken have nothing to settle.
+func (s *TicketReservationServer) settleRefund(ctx context.Context, tx *sql.Tx, ticketID, amount uint64) error {
+	var reference, st string
+	err := tx.QueryRowContext(ctx, "SELECT reference, status FROM payments WHERE ticket_id = $1 FOR UPDATE", ticketID).Scan(&reference, &st)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+
+	switch {
+	case st == paymentPending:
+		// Voiding a journey's payment would leave its other legs unpaid.
+		var shared int
+		err := tx.QueryRowContext(ctx, "SELECT count(*) FROM payments WHERE provider = $1 AND reference = $2 AND ticket_id <> $3",
+			s.payments.Name(), reference, ticketID).Scan(&shared)
+		if err != nil {
+			return status.Errorf(codes.Internal, "DB Query Error: %v", err)
+		}
+		if shared > 0 {
+			return status.Errorf(codes.FailedPrecondition, "the payment of ticket %d's journey is still pending", ticketID)
+		}
+		st = paymentVoidPending
+	case st == paymentCaptured && amount > 0:
+		st = paymentRefundPending
+	default:
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE payments SET status = $1, refunded = $2, updated_at = now() WHERE ticket_id = $3",
+		st, amount, ticketID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return nil
+}
+
+// refundPayments passes the refunds and voids of cancelled tickets on to
+// the provider.
+func (s *TicketReservationServer) refundPayments(logger *slog.Logger) {
+	for range time.Tick(5 * time.Second) {
+		if err := s.issueRefunds(context.Background(), logger); err != nil {
+			logger.Error("refunds failed", "error", err)
+		}
+	}
+}
+
+// issueRefunds asks the provider for the refunds and voids settleRefund
+// recorded. Those the provider fails are retried on the next round.
+func (s *TicketReservationServer) issueRefunds(ctx context.Context, logger *slog.Logger) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, ticket_no, reference, status, refunded FROM payments
+		WHERE provider = $1 AND status IN ($2, $3) ORDER BY id LIMIT 50`,
+		s.payments.Name(), paymentRefundPending, paymentVoidPending)
+	if err != nil {
+		return err
+	}
+	type due struct {
+		id, ticketNo     uint64
+		reference, state string
+		amount           uint64
+	}
+	var todo []due
+	for rows.Next() {
+		var d due
+		if err := rows.Scan(&d.id, &d.ticketNo, &d.reference, &d.state, &d.amount); err != nil {
+			rows.Close()
+			return err
+		}
+		todo = append(todo, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, d := range todo {
+		pctx, cancel := context.WithTimeout(ctx, s.cfg.PaymentTimeout)
+		done := paymentRefunded
+		if d.state == paymentVoidPending {
+			err, done = s.payments.Void(pctx, d.reference), paymentVoided
+		} else {
+			err = s.payments.Refund(pctx, d.reference, d.amount)
+		}
+		cancel()
+		if err != nil {
+			logger.Warn("payment provider could not refund, retrying", "ticket_no", d.ticketNo, "reference", d.reference, "error", err)
+			continue
+		}
+		_, err = s.db.ExecContext(ctx, "UPDATE payments SET status = $1, updated_at = now() WHERE id = $2 AND status = $3",
+			done, d.id, d.state)
+		if err != nil {
+			return err
+		}
+		logger.Info("payment refunded", "ticket_no", d.ticketNo, "reference", d.reference, "status", done, "amount", d.amount)
+	}
+	return nil
+}
+
+// paymentWebhook is called by the provider with the outcome of a pending
+// authorization. The money is captured before taking s.mu, so a slow
+// provider does not hold up other calls.
+func (s *TicketReservationServer) paymentWebhook(reference string, authorized bool, reason string) {
+	ctx := context.Background()
+	if authorized {
+		var pending int
+		var amount uint64
+		err := s.db.QueryRowContext(ctx, "SELECT count(*), COALESCE(sum(amount), 0) FROM payments WHERE provider = $1 AND reference = $2 AND status = $3",
+			s.payments.Name(), reference, paymentPending).Scan(&pending, &amount)
+		if err != nil {
+			slog.Error("payment webhook failed", "reference", reference, "error", err)
+			return
+		}
+		if pending == 0 {
+			// Settled already, or cancelled and voided meanwhile.
+			return
+		}
+		pctx, cancel := context.WithTimeout(ctx, s.cfg.PaymentTimeout)
+		err = s.payments.Capture(pctx, reference, amount)
+		cancel()
+		if err != nil {
+			authorized, reason = false, "capture failed: "+err.Error()
+			s.voidPayment(ctx, reference)
+		}
+	}
+
+	s.mu.Lock()
+	settled, err := s.settlePayment(ctx, reference, authorized, reason)
+	s.mu.Unlock()
+	if err != nil {
+		slog.Error("payment webhook failed", "reference", reference, "error", err)
+		return
+	}
+	if authorized && !settled {
+		// The ticket was cancelled while the money was captured.
+		s.voidPayment(ctx, reference)
+	}
+}
+
+// settlePayment records the outcome of a pending payment: the tickets it
+// pays for are confirmed once the money is captured, or fail and release
+// their seats. A failed waitlist claim gets its seats held again until the
+// claim expires, so another card can be tried. It reports false, changing
+// nothing, when the payment is no longer pending. The provider has been
+// told already; callers must hold s.mu.
+func (s *TicketReservationServer) settlePayment(ctx context.Context, reference string, captured bool, reason string) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT ticket_id, status FROM payments WHERE provider = $1 AND reference = $2 ORDER BY id FOR UPDATE",
+		s.payments.Name(), reference)
+	if err != nil {
+		return false, err
+	}
+	var tickets []uint64
+	pending := false
+	for rows.Next() {
+		var ticketID sql.NullInt64
+		var st string
+		if err := rows.Scan(&ticketID, &st); err != nil {
+			rows.Close()
+			return false, err
+		}
+		pending = st == paymentPending
+		if ticketID.Valid {
+			tickets = append(tickets, uint64(ticketID.Int64))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	if !pending {
+		return false, nil
+	}
+
+	freed := map[uint64][]seatRef{}
+	var departures []uint64
+	if captured {
+		_, err = tx.ExecContext(ctx, "UPDATE payments SET status = $1, updated_at = now() WHERE provider = $2 AND reference = $3",
+			paymentCaptured, s.payments.Name(), reference)
+	} else {
+		_, err = tx.ExecContext(ctx, "UPDATE payments SET status = $1, reason = $2, updated_at = now() WHERE provider = $3 AND reference = $4",
+			paymentDeclined, reason, s.payments.Name(), reference)
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, id := range tickets {
+		if captured {
+			_, err = tx.ExecContext(ctx, "UPDATE tickets SET status = 'Confirmed' WHERE id = $1", id)
+			if err == nil {
+				err = s.recordEvent(ctx, tx, "TicketConfirmed", id, nil)
+			}
+			if err == nil {
+				err = s.notify(ctx, tx, mailConfirmation, id, nil)
+			}
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		seats, err := ticketSeats(ctx, tx, id)
+		if err != nil {
+			return false, err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE seats SET ticket_id = NULL WHERE ticket_id = $1", id)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, "UPDATE tickets SET status = $1 WHERE id = $2", statusPaymentFailed, id)
+		}
+		if err == nil {
+			// A retry with the same key books afresh.
+			_, err = tx.ExecContext(ctx, `DELETE FROM idempotency_keys
+				WHERE ticket_id = $1 OR journey_id = (SELECT journey_id FROM tickets WHERE id = $1)`, id)
+		}
+		if err == nil {
+			err = s.recordEvent(ctx, tx, "TicketPaymentFailed", id, nil)
+		}
+		if err != nil {
+			return false, err
+		}
+		reclaimed, err := reholdClaim(ctx, tx, id, seats)
+		if err != nil {
+			return false, err
+		}
+		freed[id] = seats
+		if !reclaimed && len(seats) > 0 {
+			departures = append(departures, seats[0].DepartureID)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	slog.Info("pending payment settled", "reference", reference, "tickets", tickets, "captured", captured, "reason", reason)
+	for _, id := range tickets {
+		if captured {
+			s.events.publish("TicketConfirmed", id, seatRef{})
+			continue
+		}
+		for _, seat := range freed[id] {
+			s.events.publish("TicketPaymentFailed", id, seat)
+		}
+	}
+	// The payment is settled even if promotion fails; the sweep retries.
+	for _, departure := range departures {
+		if err := s.promoteWaitlist(ctx, departure); err != nil {
+			slog.Warn("waitlist promotion failed", "departure_id", departure, "error", err)
+		}
+	}
+	return true, nil
+}
+
+// reholdClaim puts the seats of a ticket booked by claiming a waitlist entry
+// back on hold for the entry, if the claim has not expired, and reopens the
+// entry. It reports whether it did.
+func reholdClaim(ctx context.Context, tx *sql.Tx, ticketID uint64, seats []seatRef) (bool, error) {
+	var prefix string
+	var until time.Time
+	err := tx.QueryRowContext(ctx, `UPDATE waitlist SET status = 'PROMOTED', ticket_id = NULL
+		WHERE ticket_id = $1 AND status = 'BOOKED' AND hold_expires_at > now()
+		RETURNING hold_prefix, hold_expires_at`, ticketID).Scan(&prefix, &until)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for i, seat := range seats {
+		if err := holdSeat(ctx, tx, holdName(prefix, i), until, seat); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// expirePayments fails bookings whose payment is still pending after
+// PaymentPendingTTL, as if the provider had declined them. The provider is
+// told after the tickets have failed, outside s.mu.
+func (s *TicketReservationServer) expirePayments(logger *slog.Logger) {
+	for range time.Tick(time.Minute) {
+		ctx := context.Background()
+		s.mu.Lock()
+		failed, err := s.sweepPayments(ctx, logger)
+		s.mu.Unlock()
+		if err != nil {
+			logger.Error("payment sweep failed", "error", err)
+		}
+		for _, reference := range failed {
+			s.voidPayment(ctx, reference)
+		}
+	}
+}
+
+// sweepPayments fails the stale pending payments and returns their
+// references, including those failed before an error.
+func (s *TicketReservationServer) sweepPayments(ctx context.Context, logger *slog.Logger) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT reference FROM payments WHERE provider = $1 AND status = $2 AND created_at < $3",
+		s.payments.Name(), paymentPending, time.Now().Add(-s.cfg.PaymentPendingTTL))
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for rows.Next() {
+		var reference string
+		if err := rows.Scan(&reference); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stale = append(stale, reference)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var failed []string
+	for _, reference := range stale {
+		logger.Warn("payment still pending, giving up", "reference", reference)
+		settled, err := s.settlePayment(ctx, reference, false, "no answer from the payment provider")
+		if settled {
+			failed = append(failed, reference)
+		}
+		if err != nil {
+			return failed, err
+		}
+	}
+	return failed, nil
+}
+
+// scanPayment builds the pb.Payment of a ticket from the nullable columns
+// of ticketSelect.
+func scanPayment(provider, reference, st, currency, reason sql.NullString, amount, refunded sql.NullInt64) *pb.Payment {
+	if !provider.Valid {
+		return nil
+	}
+	return &pb.Payment{
+		Provider:  provider.String,
+		Reference: reference.String,
+		Status:    st.String,
+		Amount:    uint64(amount.Int64),
+		Refunded:  uint64(refunded.Int64),
+		Currency:  currency.String,
+		Reason:    reason.String,
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// pdfDoc writes a minimal PDF 1.4 file: A4 pages drawn with the standard
+// Helvetica fonts, filled rectangles and lines. That is all a ticket needs,
+// and it keeps the server free of a PDF library.
+type pdfDoc struct {
+	pages []*pdfPage
+}
+
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+)
+
+// pdfPage is the content stream of one page. Coordinates are in points
+// from the bottom-left corner.
+type pdfPage struct {
+	bytes.Buffer
+}
+
+func (d *pdfDoc) addPage() *pdfPage {
+	p := &pdfPage{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// text draws s with its baseline starting at x, y. gray is 0 for black and
+// 1 for white.
+func (p *pdfPage) text(x, y, size float64, bold bool, gray float64, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p, "BT %.3f g /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", gray, font, size, x, y, pdfString(s))
+}
+
+func (p *pdfPage) fillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(p, "%.3f g %.2f %.2f %.2f %.2f re f\n", gray, x, y, w, h)
+}
+
+func (p *pdfPage) strokeRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(p, "%.3f G 0.8 w %.2f %.2f %.2f %.2f re S\n", gray, x, y, w, h)
+}
+
+func (p *pdfPage) dashedLine(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p, "q 0.6 G 0.8 w [4 3] 0 d %.2f %.2f m %.2f %.2f l S Q\n", x1, y1, x2, y2)
+}
+
+// pdfString escapes s for a PDF literal string in WinAnsiEncoding. Latin-1
+// characters are kept; anything else becomes "?".
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '→':
+			b.WriteString("-")
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// bytes returns the finished file.
+func (d *pdfDoc) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1-4 are fixed; every page then takes a page object and a
+	// content stream, starting at object 5.
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.Len(), p.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
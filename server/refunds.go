@@ -0,0 +1,166 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// refundTier refunds Percent of the price when a ticket is cancelled at
+// least Before ahead of departure.
+type refundTier struct {
+	Percent uint64
+	Before  time.Duration
+}
+
+// cancellationPolicy lists the refund tiers of a fare type, longest notice
+// first. An empty policy makes the fare non-refundable.
+type cancellationPolicy []refundTier
+
+// parsePolicy reads one fare type's policy from CANCELLATION_POLICY:
+// "none", or tiers like "100%@24h,50%@2h".
+func parsePolicy(spec string) (cancellationPolicy, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "none" {
+		return cancellationPolicy{}, nil
+	}
+	var p cancellationPolicy
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		pct, before, ok := strings.Cut(entry, "%@")
+		n, err := strconv.ParseUint(pct, 10, 64)
+		if !ok || err != nil || n > 100 {
+			return nil, fmt.Errorf("%q: want PERCENT%%@DURATION, e.g. 50%%@2h, or none", entry)
+		}
+		d, err := time.ParseDuration(before)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%q: want PERCENT%%@DURATION, e.g. 50%%@2h, or none", entry)
+		}
+		p = append(p, refundTier{Percent: n, Before: d})
+	}
+	slices.SortFunc(p, func(a, b refundTier) int { return cmp.Compare(b.Before, a.Before) })
+	return p, nil
+}
+
+// refund works out what cancelling a ticket bought for pricePaid refunds
+// when the train leaves in left, and which rule of the policy says so.
+func (p cancellationPolicy) refund(pricePaid uint64, left time.Duration) (amount uint64, rule string) {
+	if len(p) == 0 {
+		return 0, "non-refundable fare"
+	}
+	for _, t := range p {
+		if left >= t.Before {
+			rule = fmt.Sprintf("%d%% refund", t.Percent)
+			if t.Percent == 100 {
+				rule = "full refund"
+			}
+			if t.Before == 0 {
+				return pricePaid * t.Percent / 100, rule + " until departure"
+			}
+			return pricePaid * t.Percent / 100, rule + " until " + shortDuration(t.Before) + " before departure"
+		}
+	}
+	if last := p[len(p)-1].Before; last > 0 {
+		return 0, "no refund within " + shortDuration(last) + " of departure"
+	}
+	return 0, "no refund after departure"
+}
+
+// shortDuration formats policy cutoffs as "24h" or "90m" rather than
+// "24h0m0s".
+func shortDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
+
+// cancellationQuote prices the cancellation of a ticket the caller may
+// cancel, locking the ticket when q is a transaction. Tickets of fare types
+// no longer configured are refunded like standard fares.
+func (s *TicketReservationServer) cancellationQuote(ctx context.Context, q rowQueryer, ticketNo, owner uint64) (*pb.Refund, error) {
+	var st, fare string
+	var price uint64
+	var departs time.Time
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(t.status, ''), t.fare_type, t.price_paid, COALESCE(d.departs_at, now())
+		FROM tickets t LEFT JOIN departure_stops d ON d.departure_id = t.departure_id AND d.stop = t.from_stop
+		WHERE t.id = $1 AND ($2 = 0 OR t.account_id = $2) FOR UPDATE OF t`,
+		ticketNo, owner).Scan(&st, &fare, &price, &departs)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "ticket %d not found", ticketNo)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if err := travelled(ticketNo, st); err != nil {
+		return nil, err
+	}
+
+	ft, ok := s.cfg.FareTypes[fare]
+	if !ok {
+		ft = s.cfg.FareTypes[standardFare]
+	}
+	amount, rule := ft.Policy.refund(price, time.Until(departs))
+	fee := price - amount
+	if unpaid(ticketNo, st) != nil {
+		amount, fee, rule = 0, 0, "nothing was charged"
+	}
+	return &pb.Refund{
+		TicketNo:  ticketNo,
+		FareType:  fare,
+		PricePaid: price,
+		Fee:       fee,
+		Amount:    amount,
+		Currency:  s.cfg.Currency,
+		Policy:    rule,
+	}, nil
+}
+
+// recordRefund stores the refund of a ticket about to be deleted and fills
+// in its ID and time.
+func recordRefund(ctx context.Context, tx *sql.Tx, r *pb.Refund) error {
+	var created time.Time
+	err := tx.QueryRowContext(ctx, `INSERT INTO refunds (ticket_no, departure_id, account_id, fare_type, price_paid, fee, amount, currency, policy)
+		SELECT t.id, t.departure_id, t.account_id, $2, $3, $4, $5, $6, $7 FROM tickets t WHERE t.id = $1
+		RETURNING id, created_at`,
+		r.TicketNo, r.FareType, r.PricePaid, r.Fee, r.Amount, r.Currency, r.Policy,
+	).Scan(&r.RefundId, &created)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	r.CreatedAt = timestamppb.New(created)
+	return nil
+}
+
+func (s *TicketReservationServer) QuoteCancellation(ctx context.Context, req *pb.ReservationRequest) (*pb.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.TicketNo == nil {
+		return nil, status.Error(codes.InvalidArgument, "ID required")
+	}
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.cancellationQuote(ctx, s.db, *req.TicketNo, owner)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "cancellation quoted", "ticket_no", r.TicketNo, "refund", r.Amount, "fee", r.Fee)
+	return r, nil
+}
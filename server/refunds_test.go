@@ -0,0 +1,131 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		spec string
+		want cancellationPolicy
+	}{
+		{"none", cancellationPolicy{}},
+		{" none ", cancellationPolicy{}},
+		{"100%@0s", cancellationPolicy{{100, 0}}},
+		{"100%@24h,50%@2h", cancellationPolicy{{100, 24 * time.Hour}, {50, 2 * time.Hour}}},
+		// Tiers are sorted longest notice first.
+		{"50%@2h, 100%@24h, 0%@30m", cancellationPolicy{{100, 24 * time.Hour}, {50, 2 * time.Hour}, {0, 30 * time.Minute}}},
+	}
+	for _, tt := range tests {
+		got, err := parsePolicy(tt.spec)
+		if err != nil {
+			t.Errorf("parsePolicy(%q): %v", tt.spec, err)
+			continue
+		}
+		if got == nil || !slices.Equal(got, tt.want) {
+			t.Errorf("parsePolicy(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "all", "101%@1h", "-5%@1h", "50%", "50@2h", "50%@soon", "50%@-1h", "100%@24h,"} {
+		if got, err := parsePolicy(spec); err == nil {
+			t.Errorf("parsePolicy(%q) = %v, want an error", spec, got)
+		}
+	}
+}
+
+func TestPolicyRefund(t *testing.T) {
+	tiered := cancellationPolicy{{100, 24 * time.Hour}, {50, 2 * time.Hour}}
+	untilDeparture := cancellationPolicy{{100, 0}}
+	partial := cancellationPolicy{{75, 90 * time.Minute}}
+
+	tests := []struct {
+		name       string
+		policy     cancellationPolicy
+		price      uint64
+		left       time.Duration
+		wantAmount uint64
+		wantRule   string
+	}{
+		{"days ahead", tiered, 5000, 72 * time.Hour, 5000, "full refund until 24h before departure"},
+		{"first tier starts", tiered, 5000, 24 * time.Hour, 5000, "full refund until 24h before departure"},
+		{"just inside first tier cutoff", tiered, 5000, 24*time.Hour - time.Second, 2500, "50% refund until 2h before departure"},
+		{"second tier starts", tiered, 5000, 2 * time.Hour, 2500, "50% refund until 2h before departure"},
+		{"past the last tier", tiered, 5000, 2*time.Hour - time.Second, 0, "no refund within 2h of departure"},
+		{"after departure", tiered, 5000, -time.Hour, 0, "no refund within 2h of departure"},
+		{"percent rounds down", tiered, 3333, 3 * time.Hour, 1666, "50% refund until 2h before departure"},
+		{"minutes cutoff", partial, 1000, 90 * time.Minute, 750, "75% refund until 90m before departure"},
+		{"up to departure", untilDeparture, 6500, 0, 6500, "full refund until departure"},
+		{"departed", untilDeparture, 6500, -time.Second, 0, "no refund after departure"},
+		{"non-refundable", cancellationPolicy{}, 3000, 72 * time.Hour, 0, "non-refundable fare"},
+		{"nothing paid", tiered, 0, 72 * time.Hour, 0, "full refund until 24h before departure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, rule := tt.policy.refund(tt.price, tt.left)
+			if amount != tt.wantAmount || rule != tt.wantRule {
+				t.Errorf("refund(%d, %v) = %d, %q, want %d, %q", tt.price, tt.left, amount, rule, tt.wantAmount, tt.wantRule)
+			}
+		})
+	}
+}
+
+// TestFareTypeRefunds checks the default cancellation policy of each fare
+// type, with the fee being what is not refunded.
+func TestFareTypeRefunds(t *testing.T) {
+	types, err := parseFareTypes(4500, defaultFareTypes, defaultCancellationPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fare    string
+		left    time.Duration
+		wantFee uint64
+	}{
+		{standardFare, 48 * time.Hour, 0},
+		{standardFare, 12 * time.Hour, 2250},
+		{standardFare, time.Hour, 4500},
+		{"flexible", 48 * time.Hour, 0},
+		{"flexible", time.Minute, 0},
+		{"flexible", -time.Minute, 6500},
+		{"saver", 48 * time.Hour, 3000},
+		{"saver", time.Hour, 3000},
+	}
+	for _, tt := range tests {
+		ft, ok := types[tt.fare]
+		if !ok {
+			t.Fatalf("no %s fare type", tt.fare)
+		}
+		amount, rule := ft.Policy.refund(ft.Base, tt.left)
+		if fee := ft.Base - amount; fee != tt.wantFee {
+			t.Errorf("%s fare cancelled %v ahead: fee %d (%s), want %d", tt.fare, tt.left, fee, rule, tt.wantFee)
+		}
+	}
+}
+
+func TestShortDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		24 * time.Hour:   "24h",
+		90 * time.Minute: "90m",
+		0:                "0h",
+		90 * time.Second: "1m30s",
+	} {
+		if got := shortDuration(d); got != want {
+			t.Errorf("shortDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestParseFareTypesPolicies(t *testing.T) {
+	for _, tt := range []struct{ prices, policies string }{
+		{"saver=3000", "standard=100%@24h"},
+		{"", "standard=100%@24h; saver=none"},
+		{"", "standard=half"},
+	} {
+		if _, err := parseFareTypes(4500, tt.prices, tt.policies); err == nil {
+			t.Errorf("parseFareTypes(%q, %q) succeeded", tt.prices, tt.policies)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"database/sql"
+	"log/slog"
+)
+
+// schema is applied in order on every start, so each statement must be
+// idempotent.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS tickets (
+		id SERIAL PRIMARY KEY,
+		passenger_name TEXT,
+		email TEXT,
+		section TEXT,
+		seat INT,
+		status TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS departures (
+		id SERIAL PRIMARY KEY,
+		train TEXT NOT NULL,
+		from_code TEXT NOT NULL,
+		to_code TEXT NOT NULL,
+		departs_at TIMESTAMPTZ NOT NULL,
+		arrives_at TIMESTAMPTZ NOT NULL,
+		UNIQUE (train, departs_at)
+	)`,
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS departure_id INT REFERENCES departures(id)`,
+	// Seat indexes created before departures existed cannot be mapped to a
+	// departure; drop them and let ReindexSeats rebuild from tickets.
+	`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_name = 'seats' AND column_name = 'departure_id') THEN
+			DROP TABLE IF EXISTS seats;
+		END IF;
+	END $$`,
+	// seats is the seat availability index: one row per physical seat of a
+	// departure, pointing at the ticket that occupies it or the hold that
+	// reserves it.
+	`CREATE TABLE IF NOT EXISTS seats (
+		departure_id INT NOT NULL REFERENCES departures(id),
+		section TEXT NOT NULL,
+		seat INT NOT NULL,
+		ticket_id INT REFERENCES tickets(id) ON DELETE SET NULL,
+		hold_id TEXT UNIQUE,
+		held_until TIMESTAMPTZ,
+		PRIMARY KEY (departure_id, section, seat)
+	)`,
+	// idempotency_keys remembers which ticket a client-supplied
+	// idempotency key produced, so retried bookings are not duplicated.
+	`CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key TEXT PRIMARY KEY,
+		ticket_id INT REFERENCES tickets(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	// accounts are customer logins for the web UI. Emails are stored in
+	// lower case.
+	`CREATE TABLE IF NOT EXISTS accounts (
+		id SERIAL PRIMARY KEY,
+		email TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL DEFAULT '',
+		password_hash TEXT NOT NULL,
+		role TEXT NOT NULL DEFAULT 'customer',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS account_id INT REFERENCES accounts(id) ON DELETE SET NULL`,
+	// price_paid is in minor units of the fare currency.
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS price_paid BIGINT NOT NULL DEFAULT 0`,
+	// ticket_passengers holds everyone travelling on a ticket, one seat
+	// each. Position 0 is the lead passenger, who is also kept in tickets.
+	`CREATE TABLE IF NOT EXISTS ticket_passengers (
+		ticket_id INT NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+		position INT NOT NULL,
+		first_name TEXT NOT NULL DEFAULT '',
+		last_name TEXT NOT NULL DEFAULT '',
+		email TEXT NOT NULL DEFAULT '',
+		address TEXT NOT NULL DEFAULT '',
+		section TEXT NOT NULL,
+		seat INT NOT NULL,
+		PRIMARY KEY (ticket_id, position)
+	)`,
+	`INSERT INTO ticket_passengers (ticket_id, position, first_name, email, section, seat)
+		SELECT id, 0, COALESCE(passenger_name, ''), COALESCE(email, ''), section, seat FROM tickets t
+		WHERE section IS NOT NULL AND seat IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM ticket_passengers p WHERE p.ticket_id = t.id)`,
+	`ALTER TABLE ticket_passengers ADD COLUMN IF NOT EXISTS used_at TIMESTAMPTZ`,
+	// ticket_inspections records every ValidateTicket call. ticket_no is
+	// not a foreign key so inspections of cancelled tickets are kept.
+	`CREATE TABLE IF NOT EXISTS ticket_inspections (
+		id SERIAL PRIMARY KEY,
+		ticket_no BIGINT,
+		position INT NOT NULL DEFAULT 0,
+		result TEXT NOT NULL,
+		inspector TEXT NOT NULL DEFAULT '',
+		consumed BOOLEAN NOT NULL DEFAULT false,
+		inspected_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`ALTER TABLE ticket_passengers ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ`,
+	`ALTER TABLE ticket_passengers ADD COLUMN IF NOT EXISTS boarded_at TIMESTAMPTZ`,
+	// waitlist queues bookings for full departures. request is the
+	// protojson ReservationRequest to book; promoted entries hold their
+	// seats under hold_prefix.0, hold_prefix.1, ...
+	`CREATE TABLE IF NOT EXISTS waitlist (
+		id SERIAL PRIMARY KEY,
+		departure_id INT NOT NULL REFERENCES departures(id),
+		account_id INT REFERENCES accounts(id) ON DELETE SET NULL,
+		request JSONB NOT NULL,
+		passengers INT NOT NULL,
+		priority INT NOT NULL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'WAITING',
+		hold_prefix TEXT,
+		hold_expires_at TIMESTAMPTZ,
+		ticket_id INT REFERENCES tickets(id) ON DELETE SET NULL,
+		joined_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS waitlist_queue ON waitlist (departure_id, priority DESC, id) WHERE status = 'WAITING'`,
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS fare_type TEXT NOT NULL DEFAULT 'standard'`,
+	// refunds records what CancelTicket paid back. The ticket row is gone by
+	// then, so ticket_no is not a foreign key.
+	`CREATE TABLE IF NOT EXISTS refunds (
+		id SERIAL PRIMARY KEY,
+		ticket_no BIGINT NOT NULL,
+		departure_id INT REFERENCES departures(id) ON DELETE SET NULL,
+		account_id INT REFERENCES accounts(id) ON DELETE SET NULL,
+		fare_type TEXT NOT NULL,
+		price_paid BIGINT NOT NULL,
+		fee BIGINT NOT NULL,
+		amount BIGINT NOT NULL,
+		currency TEXT NOT NULL,
+		policy TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	// payments holds the one payment of each ticket. ticket_no outlives
+	// the ticket for the record.
+	`CREATE TABLE IF NOT EXISTS payments (
+		id SERIAL PRIMARY KEY,
+		ticket_id INT UNIQUE REFERENCES tickets(id) ON DELETE SET NULL,
+		ticket_no BIGINT NOT NULL,
+		provider TEXT NOT NULL,
+		reference TEXT NOT NULL,
+		status TEXT NOT NULL,
+		amount BIGINT NOT NULL,
+		refunded BIGINT NOT NULL DEFAULT 0,
+		currency TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		UNIQUE (provider, reference)
+	)`,
+	// mail_outbox holds notification emails until they are sent. They are
+	// queued in the transaction that changes the ticket and sent from here,
+	// so no email is lost while the SMTP server is down.
+	`CREATE TABLE IF NOT EXISTS mail_outbox (
+		id SERIAL PRIMARY KEY,
+		kind TEXT NOT NULL,
+		ticket_no BIGINT NOT NULL,
+		recipient TEXT NOT NULL,
+		subject TEXT NOT NULL,
+		text_body TEXT NOT NULL,
+		html_body TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'PENDING',
+		attempts INT NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		sent_at TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS mail_outbox_due ON mail_outbox (next_attempt_at) WHERE status = 'PENDING'`,
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ`,
+	// event_outbox holds the domain events of tickets in the order they
+	// happened; payload is the DomainEvent as JSON. event_sink_offsets
+	// records the last event each sink has been sent.
+	`CREATE TABLE IF NOT EXISTS event_outbox (
+		id BIGSERIAL PRIMARY KEY,
+		ticket_no BIGINT NOT NULL,
+		sequence BIGINT NOT NULL,
+		type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		UNIQUE (ticket_no, sequence)
+	)`,
+	`CREATE TABLE IF NOT EXISTS event_sink_offsets (
+		sink TEXT PRIMARY KEY,
+		last_event_id BIGINT NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE IF NOT EXISTS webhooks (
+		id SERIAL PRIMARY KEY,
+		url TEXT NOT NULL,
+		event_types TEXT[] NOT NULL DEFAULT '{}',
+		account_id BIGINT NOT NULL DEFAULT 0,
+		secret TEXT NOT NULL,
+		from_event_id BIGINT NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+		event_id BIGINT NOT NULL REFERENCES event_outbox(id),
+		event_type TEXT NOT NULL,
+		ticket_no BIGINT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'PENDING',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		delivered_at TIMESTAMPTZ,
+		UNIQUE (webhook_id, event_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING'`,
+	// state turns the outbox into the ticket ledger: the full state of the
+	// ticket after each event, see ledgerState. The ledger is append-only.
+	`ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS state JSONB`,
+	`CREATE OR REPLACE FUNCTION event_outbox_append_only() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		RAISE EXCEPTION 'event_outbox is append-only';
+	END $$`,
+	`CREATE OR REPLACE TRIGGER event_outbox_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON event_outbox
+		FOR EACH STATEMENT EXECUTE FUNCTION event_outbox_append_only()`,
+	// layouts holds every version of every coach layout; definition is the
+	// Layout as JSON. Versions are never changed once stored. Departures
+	// without a layout use the configured sections; train_layouts names the
+	// layout new departures of a train get.
+	`CREATE TABLE IF NOT EXISTS layouts (
+		name TEXT NOT NULL,
+		version INT NOT NULL,
+		definition JSONB NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (name, version)
+	)`,
+	`ALTER TABLE departures ADD COLUMN IF NOT EXISTS layout_name TEXT`,
+	`ALTER TABLE departures ADD COLUMN IF NOT EXISTS layout_version INT`,
+	`CREATE TABLE IF NOT EXISTS train_layouts (
+		train TEXT PRIMARY KEY,
+		layout_name TEXT NOT NULL,
+		layout_version INT NOT NULL,
+		FOREIGN KEY (layout_name, layout_version) REFERENCES layouts (name, version)
+	)`,
+	// Blocked seats are in the seat map but never sold.
+	`ALTER TABLE seats ADD COLUMN IF NOT EXISTS blocked BOOLEAN NOT NULL DEFAULT false`,
+	// departure_stops lists where each departure calls, from stop 0 at
+	// from_code to the last at to_code. Departures seeded before they had
+	// stops get those two.
+	`CREATE TABLE IF NOT EXISTS departure_stops (
+		departure_id INT NOT NULL REFERENCES departures(id),
+		stop INT NOT NULL,
+		code TEXT NOT NULL,
+		arrives_at TIMESTAMPTZ NOT NULL,
+		departs_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (departure_id, stop)
+	)`,
+	`INSERT INTO departure_stops (departure_id, stop, code, arrives_at, departs_at)
+		SELECT id, 0, from_code, departs_at, departs_at FROM departures d
+		WHERE NOT EXISTS (SELECT 1 FROM departure_stops s WHERE s.departure_id = d.id)
+		UNION ALL
+		SELECT id, 1, to_code, arrives_at, arrives_at FROM departures d
+		WHERE NOT EXISTS (SELECT 1 FROM departure_stops s WHERE s.departure_id = d.id)`,
+	// The seat index has a row per leg of each seat, leg i running from
+	// stop i to stop i+1, so a seat can be sold on legs that do not overlap.
+	// A hold covers every leg it is for.
+	`ALTER TABLE seats ADD COLUMN IF NOT EXISTS leg INT NOT NULL DEFAULT 0`,
+	`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_index i JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+			WHERE i.indrelid = 'seats'::regclass AND i.indisprimary AND a.attname = 'leg') THEN
+			ALTER TABLE seats DROP CONSTRAINT seats_pkey, ADD PRIMARY KEY (departure_id, section, seat, leg);
+		END IF;
+	END $$`,
+	`ALTER TABLE seats DROP CONSTRAINT IF EXISTS seats_hold_id_key`,
+	`CREATE INDEX IF NOT EXISTS seats_hold ON seats (hold_id) WHERE hold_id IS NOT NULL`,
+	// Tickets and waitlist entries travel from from_stop to to_stop of their
+	// departure.
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS from_stop INT NOT NULL DEFAULT 0`,
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS to_stop INT NOT NULL DEFAULT 1`,
+	`ALTER TABLE waitlist ADD COLUMN IF NOT EXISTS from_stop INT NOT NULL DEFAULT 0`,
+	`ALTER TABLE waitlist ADD COLUMN IF NOT EXISTS to_stop INT NOT NULL DEFAULT 1`,
+	// journeys group the tickets booked together by ReserveJourney, one per
+	// leg. An idempotency key names either a ticket or a journey.
+	`CREATE TABLE IF NOT EXISTS journeys (
+		id SERIAL PRIMARY KEY,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`ALTER TABLE tickets ADD COLUMN IF NOT EXISTS journey_id INT REFERENCES journeys(id)`,
+	`ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS journey_id INT REFERENCES journeys(id)`,
+	// A journey is paid for with one payment: each leg's row holds its
+	// share of the amount under the same reference.
+	`ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_provider_reference_key`,
+	`CREATE INDEX IF NOT EXISTS payments_reference ON payments (provider, reference)`,
+	// Promotion emails are about a waitlist entry rather than a ticket.
+	`ALTER TABLE mail_outbox ALTER COLUMN ticket_no DROP NOT NULL`,
+	`ALTER TABLE mail_outbox ADD COLUMN IF NOT EXISTS waitlist_id INT REFERENCES waitlist(id)`,
+}
+
+func migrate(db *sql.DB, cfg config, logger *slog.Logger) error {
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	if err := seedDepartures(db, cfg, logger); err != nil {
+		return err
+	}
+	// Tickets booked before departures existed are attached to the first
+	// departure so they keep showing up with a route.
+	_, err := db.Exec(`UPDATE tickets SET departure_id = (SELECT id FROM departures ORDER BY departs_at LIMIT 1)
+		WHERE departure_id IS NULL`)
+	return err
+}
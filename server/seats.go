@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// seatFree matches seats that are neither blocked, booked nor under an
+// active hold.
+const seatFree = "NOT blocked AND ticket_id IS NULL AND (held_until IS NULL OR held_until < now())"
+
+// seatRef identifies one seat on one departure and the stops it is taken
+// between: it is used on legs From to To-1, leg i running from stop i to
+// stop i+1.
+type seatRef struct {
+	DepartureID uint64
+	Section     string
+	Seat        uint32
+	From, To    int
+}
+
+// freeSeats selects the seats of departure $1 free on every leg from stop $2
+// to stop $3 and locks them.
+const freeSeats = `WITH free AS (SELECT section, seat FROM seats
+	WHERE departure_id = $1 AND leg >= $2 AND leg < $3 AND ` + seatFree + ` FOR UPDATE SKIP LOCKED)
+	SELECT section, seat FROM free GROUP BY section, seat HAVING count(*) = $3 - $2 ORDER BY section, seat`
+
+// pickSeat locks and returns a seat for a new or modified ticket. A hold ID
+// takes precedence, then an explicit section and seat on want.DepartureID;
+// otherwise the first free seat of that departure is chosen. Both are
+// taken between want.From and want.To.
+func pickSeat(ctx context.Context, tx *sql.Tx, holdID string, want seatRef) (seatRef, error) {
+	var row *sql.Row
+	got := want
+	switch {
+	case holdID != "":
+		row = tx.QueryRowContext(ctx, `WITH held AS (SELECT departure_id, section, seat, leg FROM seats
+			WHERE hold_id = $1 AND held_until > now() FOR UPDATE)
+			SELECT departure_id, section, seat, min(leg), max(leg) + 1 FROM held GROUP BY departure_id, section, seat`, holdID)
+	case want.Section != "" && want.Seat != 0:
+		row = tx.QueryRowContext(ctx, `WITH free AS (SELECT section, seat FROM seats
+			WHERE departure_id = $1 AND leg >= $2 AND leg < $3 AND section = $4 AND seat = $5 AND `+seatFree+` FOR UPDATE)
+			SELECT section, seat FROM free GROUP BY section, seat HAVING count(*) = $3 - $2`,
+			want.DepartureID, want.From, want.To, want.Section, want.Seat)
+	default:
+		row = tx.QueryRowContext(ctx, freeSeats+" LIMIT 1", want.DepartureID, want.From, want.To)
+	}
+
+	var err error
+	if holdID != "" {
+		err = row.Scan(&got.DepartureID, &got.Section, &got.Seat, &got.From, &got.To)
+	} else {
+		err = row.Scan(&got.Section, &got.Seat)
+	}
+	switch {
+	case err == sql.ErrNoRows && holdID != "":
+		return got, status.Errorf(codes.FailedPrecondition, "hold %s is unknown or has expired", holdID)
+	case err == sql.ErrNoRows && want.Seat != 0:
+		return got, status.Errorf(codes.FailedPrecondition, "seat %s-%d is not available", want.Section, want.Seat)
+	case err == sql.ErrNoRows:
+		return got, status.Errorf(codes.ResourceExhausted, "departure %d is sold out", want.DepartureID)
+	case err != nil:
+		return got, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if holdID != "" && want.DepartureID != 0 && got.DepartureID != want.DepartureID {
+		return got, status.Errorf(codes.FailedPrecondition, "hold %s is for departure %d", holdID, got.DepartureID)
+	}
+	return got, nil
+}
+
+// occupySeat points the seat at ticketID between ref.From and ref.To,
+// releasing any hold on it.
+func occupySeat(ctx context.Context, tx *sql.Tx, ticketID uint64, ref seatRef) error {
+	_, err := tx.ExecContext(ctx, `UPDATE seats SET ticket_id = $1, hold_id = NULL, held_until = NULL
+		WHERE departure_id = $2 AND section = $3 AND seat = $4 AND leg >= $5 AND leg < $6`,
+		ticketID, ref.DepartureID, ref.Section, ref.Seat, ref.From, ref.To)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return nil
+}
+
+// releaseSeat frees the seat if it is occupied by ticketID.
+func releaseSeat(ctx context.Context, tx *sql.Tx, ticketID uint64, ref seatRef) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE seats SET ticket_id = NULL WHERE ticket_id = $1 AND departure_id = $2 AND section = $3 AND seat = $4",
+		ticketID, ref.DepartureID, ref.Section, ref.Seat)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return nil
+}
+
+// holdSeat holds the seat under holdID until the given time, between
+// ref.From and ref.To.
+func holdSeat(ctx context.Context, tx *sql.Tx, holdID string, until time.Time, ref seatRef) error {
+	_, err := tx.ExecContext(ctx, `UPDATE seats SET hold_id = $1, held_until = $2
+		WHERE departure_id = $3 AND section = $4 AND seat = $5 AND leg >= $6 AND leg < $7`,
+		holdID, until, ref.DepartureID, ref.Section, ref.Seat, ref.From, ref.To)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return nil
+}
+
+func (s *TicketReservationServer) HoldSeat(ctx context.Context, req *pb.HoldRequest) (*pb.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	ride, err := resolveRide(ctx, tx, req.DepartureId, req.FromCode, req.ToCode)
+	if err != nil {
+		return nil, err
+	}
+	ride.Section, ride.Seat = req.Section, req.Seat
+	got, err := pickSeat(ctx, tx, "", ride)
+	if err != nil {
+		return nil, err
+	}
+
+	holdID := newHoldID()
+	expires := time.Now().Add(s.cfg.HoldTTL)
+	if err := holdSeat(ctx, tx, holdID, expires, got); err != nil {
+		return nil, err
+	}
+	from, to, err := stopCodes(ctx, tx, got)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "seat held",
+		"hold_id", holdID, "departure_id", got.DepartureID, "section", got.Section, "seat", got.Seat)
+	s.events.publish("SeatHeld", 0, got)
+	return &pb.Hold{HoldId: holdID, DepartureId: got.DepartureID, Section: got.Section, Seat: got.Seat,
+		ExpiresAt: timestamppb.New(expires), FromCode: from, ToCode: to}, nil
+}
+
+func newHoldID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *TicketReservationServer) GetSeatMap(ctx context.Context, req *pb.SeatMapRequest) (*pb.SeatMap, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ride, err := resolveRide(ctx, s.db, req.DepartureId, req.FromCode, req.ToCode)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := s.departureLayout(ctx, s.db, ride.DepartureID)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := stopCodes(ctx, s.db, ride)
+	if err != nil {
+		return nil, err
+	}
+
+	// A seat is shown as taken if it is taken on any leg of the ride.
+	rows, err := s.db.QueryContext(ctx, `SELECT section, seat,
+		CASE WHEN bool_or(blocked) THEN 3 WHEN bool_or(ticket_id IS NOT NULL) THEN 2 WHEN bool_or(held_until > now()) THEN 1 ELSE 0 END
+		FROM seats WHERE departure_id = $1 AND ($2 = '' OR section = $2) AND leg >= $3 AND leg < $4
+		GROUP BY section, seat ORDER BY section, seat`,
+		ride.DepartureID, req.Section, ride.From, ride.To)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+
+	m := &pb.SeatMap{DepartureId: ride.DepartureID, Layout: layout.Name, LayoutVersion: layout.Version,
+		FromCode: from, ToCode: to}
+	for rows.Next() {
+		var seat pb.SeatMap_Seat
+		if err := rows.Scan(&seat.Section, &seat.Seat, &seat.State); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		coach := layout.coach(seat.Section)
+		if p, ok := coach.places[seat.Seat]; ok {
+			seat.CoachClass, seat.QuietCoach = coach.Class, coach.Quiet
+			seat.Position, seat.Facing, seat.NearExit, seat.Accessible = p.Position, p.Facing, p.NearExit, p.Accessible
+			seat.Row = uint32(p.Row + 1)
+		}
+		m.Seats = append(m.Seats, &seat)
+	}
+	return m, nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventSink receives domain events from the relay. Deliver returns nil
+// only once the event is safely handed over; the relay then moves on to
+// the next one. Name identifies the sink's progress in the outbox and must
+// stay the same across restarts.
+type EventSink interface {
+	Name() string
+	Deliver(ctx context.Context, e outboxEvent) error
+}
+
+// newEventSinks builds the sinks of EVENT_SINKS, a comma-separated list of
+//
+//	stdout                         one JSON line per event on standard output
+//	file:/var/log/events.jsonl     one JSON line per event appended to the file
+//	https://example.com/hook       an HTTP POST of the event per event
+//	nats://localhost:4222/tickets  a NATS publish to tickets.<type>
+func newEventSinks(specs []string) ([]EventSink, error) {
+	var sinks []EventSink
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		switch {
+		case spec == "":
+		case spec == "stdout":
+			sinks = append(sinks, &writerSink{name: spec, w: os.Stdout})
+		case strings.HasPrefix(spec, "file:"):
+			sinks = append(sinks, &fileSink{name: spec, path: strings.TrimPrefix(spec, "file:")})
+		case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+			sinks = append(sinks, &webhookSink{url: spec, client: &http.Client{Timeout: 10 * time.Second}})
+		case strings.HasPrefix(spec, "nats://"):
+			u, err := url.Parse(spec)
+			if err != nil || u.Host == "" {
+				return nil, fmt.Errorf("event sink %q: want nats://HOST:PORT/SUBJECT", spec)
+			}
+			subject := strings.Trim(u.Path, "/")
+			if subject == "" {
+				subject = "tickets"
+			}
+			sinks = append(sinks, &natsSink{name: spec, addr: u.Host, subject: strings.ReplaceAll(subject, "/", ".")})
+		default:
+			return nil, fmt.Errorf("event sink %q: want stdout, file:PATH, an http(s) URL or a nats:// URL", spec)
+		}
+	}
+	return sinks, nil
+}
+
+// writerSink writes events as JSON lines to w.
+type writerSink struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+func (s *writerSink) Name() string { return s.name }
+
+func (s *writerSink) Deliver(ctx context.Context, e outboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(append(e.Payload, '\n'))
+	return err
+}
+
+// fileSink appends events as JSON lines to a file, synced to disk before
+// the event counts as delivered.
+type fileSink struct {
+	name, path string
+}
+
+func (s *fileSink) Name() string { return s.name }
+
+func (s *fileSink) Deliver(ctx context.Context, e outboxEvent) error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(e.Payload, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// webhookSink POSTs each event to a URL and counts any 2xx answer as
+// delivered.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSink) Name() string { return s.url }
+
+func (s *webhookSink) Deliver(ctx context.Context, e outboxEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(e.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", strconv.FormatUint(e.ID, 10))
+	req.Header.Set("X-Event-Type", e.Type)
+	req.Header.Set("X-Ticket-No", strconv.FormatUint(e.TicketNo, 10))
+	req.Header.Set("X-Event-Sequence", strconv.FormatUint(e.Sequence, 10))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// natsSink publishes events to a NATS server, or anything speaking its
+// client protocol, on subject.<type>. Each publish is followed by a PING,
+// so an event counts as delivered once the server has processed it. When
+// the server supports headers the event ID goes in Nats-Msg-Id, which
+// JetStream uses to drop redelivered events.
+type natsSink struct {
+	name, addr, subject string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	r       *bufio.Reader
+	headers bool
+}
+
+func (s *natsSink) Name() string { return s.name }
+
+func (s *natsSink) Deliver(ctx context.Context, e outboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	err := s.publish(e)
+	if err != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *natsSink) connect() error {
+	conn, err := net.DialTimeout("tcp", s.addr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	info, ok := strings.CutPrefix(strings.TrimSpace(line), "INFO ")
+	if !ok {
+		conn.Close()
+		return fmt.Errorf("nats %s: unexpected greeting %q", s.addr, line)
+	}
+	var server struct {
+		Headers bool `json:"headers"`
+	}
+	json.Unmarshal([]byte(info), &server)
+	connect := fmt.Sprintf(`CONNECT {"verbose":false,"pedantic":false,"name":"ticket-event-relay","lang":"go","version":"1","protocol":1,"headers":%t}`,
+		server.Headers)
+	if _, err := conn.Write([]byte(connect + "\r\n")); err != nil {
+		conn.Close()
+		return err
+	}
+	s.conn, s.r, s.headers = conn, r, server.Headers
+	return s.flush()
+}
+
+func (s *natsSink) publish(e outboxEvent) error {
+	s.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	subject := s.subject + "." + e.Type
+	var msg bytes.Buffer
+	if s.headers {
+		hdr := "NATS/1.0\r\nNats-Msg-Id: " + strconv.FormatUint(e.ID, 10) + "\r\n\r\n"
+		fmt.Fprintf(&msg, "HPUB %s %d %d\r\n%s", subject, len(hdr), len(hdr)+len(e.Payload), hdr)
+	} else {
+		fmt.Fprintf(&msg, "PUB %s %d\r\n", subject, len(e.Payload))
+	}
+	msg.Write(e.Payload)
+	msg.WriteString("\r\n")
+	if _, err := s.conn.Write(msg.Bytes()); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// flush sends a PING and waits for the PONG, which the server only sends
+// after processing everything before it.
+func (s *natsSink) flush() error {
+	if _, err := s.conn.Write([]byte("PING\r\n")); err != nil {
+		return err
+	}
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats %s: %s", s.addr, line)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	"github.com/Akash-private/Cloudbees_code/internal/tickettoken"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// A ticket token is valid from tokenValidBefore ahead of departure until
+// tokenValidAfter past arrival, so late trains do not void tickets.
+const (
+	tokenValidBefore = 12 * time.Hour
+	tokenValidAfter  = 2 * time.Hour
+)
+
+// issueTokens signs one token per passenger of t. Tokens only depend on the
+// ticket and its departure, so issuing them again gives the same tokens.
+func (c config) issueTokens(t *pb.ReservationResponse, dep departureInfo) []string {
+	var tokens []string
+	for i, p := range t.Passengers {
+		tokens = append(tokens, tickettoken.Sign(c.TicketKey, tickettoken.Claims{
+			TicketNo:    t.TicketNo,
+			DepartureID: t.DepartureId,
+			Position:    uint32(i),
+			Section:     p.Section,
+			Seat:        p.Seat,
+			NotBefore:   dep.Departs.Add(-tokenValidBefore).Unix(),
+			Expires:     dep.Arrives.Add(tokenValidAfter).Unix(),
+		}))
+	}
+	return tokens
+}
+
+// inspector names who validates a ticket: the request's inspector, else the
+// authenticated caller.
+func inspector(ctx context.Context, req *pb.ValidateTicketRequest) string {
+	if req.Inspector != "" {
+		return req.Inspector
+	}
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c.name()
+}
+
+func (s *TicketReservationServer) ValidateTicket(ctx context.Context, req *pb.ValidateTicketRequest) (*pb.TicketValidation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token required")
+	}
+	// Anyone may check a ticket, but only staff may mark it used.
+	if req.Consume {
+		if err := s.requireStaff(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	v, err := s.validate(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticketNo sql.NullInt64
+	if v.TicketNo != 0 {
+		ticketNo = sql.NullInt64{Int64: int64(v.TicketNo), Valid: true}
+	}
+	err = tx.QueryRowContext(ctx, `INSERT INTO ticket_inspections (ticket_no, position, result, inspector, consumed)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		ticketNo, v.Position, v.Result.String(), inspector(ctx, req), req.Consume && v.Result == pb.TicketValidation_VALID,
+	).Scan(&v.InspectionId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "ticket inspected",
+		"ticket_no", v.TicketNo, "position", v.Position, "result", v.Result.String(), "consume", req.Consume)
+	return v, nil
+}
+
+// validate works out the verdict for req. Only database failures are
+// returned as errors.
+func (s *TicketReservationServer) validate(ctx context.Context, tx *sql.Tx, req *pb.ValidateTicketRequest) (*pb.TicketValidation, error) {
+	v := &pb.TicketValidation{}
+	c, err := tickettoken.Verify(s.cfg.TicketKey.Public().(ed25519.PublicKey), req.Token)
+	switch {
+	case errors.Is(err, tickettoken.ErrMalformed):
+		v.Result, v.Message = pb.TicketValidation_MALFORMED, err.Error()
+		return v, nil
+	case err != nil:
+		v.Result, v.Message = pb.TicketValidation_BAD_SIGNATURE, err.Error()
+		return v, nil
+	}
+	v.TicketNo, v.DepartureId, v.Position = c.TicketNo, c.DepartureID, c.Position
+	v.Section, v.Seat = c.Section, c.Seat
+	v.ValidFrom, v.ValidUntil = timestamppb.New(c.ValidFrom()), timestamppb.New(c.ValidUntil())
+
+	if err := c.CheckTime(time.Now()); err != nil {
+		v.Result, v.Message = pb.TicketValidation_EXPIRED, err.Error()
+		if errors.Is(err, tickettoken.ErrNotYetValid) {
+			v.Result = pb.TicketValidation_NOT_YET_VALID
+		}
+		return v, nil
+	}
+
+	var first, last, section string
+	var seat uint32
+	var departure uint64
+	var usedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `SELECT p.first_name, p.last_name, p.section, p.seat, COALESCE(t.departure_id, 0), p.used_at
+		FROM ticket_passengers p JOIN tickets t ON t.id = p.ticket_id
+		WHERE p.ticket_id = $1 AND p.position = $2 FOR UPDATE OF p`, c.TicketNo, c.Position,
+	).Scan(&first, &last, &section, &seat, &departure, &usedAt)
+	if err == sql.ErrNoRows {
+		v.Result, v.Message = pb.TicketValidation_CANCELLED, "ticket "+strconv.FormatUint(c.TicketNo, 10)+" has been cancelled"
+		return v, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	v.PassengerName = first
+	if last != "" {
+		v.PassengerName += " " + last
+	}
+
+	switch {
+	case section != c.Section || seat != c.Seat || departure != c.DepartureID:
+		v.Result = pb.TicketValidation_SEAT_CHANGED
+		v.Message = "passenger now has seat " + section + "-" + strconv.FormatUint(uint64(seat), 10)
+	case usedAt.Valid:
+		v.Result, v.UsedAt = pb.TicketValidation_ALREADY_USED, timestamppb.New(usedAt.Time)
+		v.Message = "ticket was used at " + usedAt.Time.UTC().Format(time.RFC3339)
+	default:
+		v.Result, v.Message = pb.TicketValidation_VALID, "ticket is valid"
+		if req.Consume {
+			now := time.Now()
+			_, err := tx.ExecContext(ctx, "UPDATE ticket_passengers SET used_at = $1 WHERE ticket_id = $2 AND position = $3",
+				now, c.TicketNo, c.Position)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+			}
+			if err := s.recordEvent(ctx, tx, "TicketConsumed", c.TicketNo, nil); err != nil {
+				return nil, err
+			}
+			v.UsedAt = timestamppb.New(now)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,519 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// waitlistSelect reads waitlist entries with their place in line. Rows must
+// be read with scanWaitlist.
+const waitlistSelect = `SELECT w.id, w.departure_id, w.status, w.priority, w.passengers, w.joined_at, w.hold_expires_at,
+	COALESCE(w.ticket_id, 0), COALESCE(w.account_id, 0), w.request,
+	CASE WHEN w.status = 'WAITING' THEN (SELECT count(*) FROM waitlist o
+		WHERE o.departure_id = w.departure_id AND o.status = 'WAITING'
+			AND (o.priority > w.priority OR (o.priority = w.priority AND o.id <= w.id))) ELSE 0 END
+	FROM waitlist w`
+
+func scanWaitlist(row rowScanner) (*pb.WaitlistEntry, error) {
+	var e pb.WaitlistEntry
+	var st string
+	var joined time.Time
+	var expires sql.NullTime
+	var request []byte
+	err := row.Scan(&e.WaitlistId, &e.DepartureId, &st, &e.Priority, &e.PassengerCount, &joined, &expires,
+		&e.TicketNo, &e.AccountId, &request, &e.Position)
+	if err != nil {
+		return nil, err
+	}
+	e.Status = pb.WaitlistEntry_Status(pb.WaitlistEntry_Status_value[st])
+	e.JoinedAt = timestamppb.New(joined)
+	if expires.Valid {
+		e.HoldExpiresAt = timestamppb.New(expires.Time)
+	}
+	e.Request = &pb.ReservationRequest{}
+	if err := jsonIn.Unmarshal(request, e.Request); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (s *TicketReservationServer) waitlistEntry(ctx context.Context, id uint64) (*pb.WaitlistEntry, error) {
+	e, err := scanWaitlist(s.db.QueryRowContext(ctx, waitlistSelect+" WHERE w.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "waitlist entry %d not found", id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return e, nil
+}
+
+func (s *TicketReservationServer) JoinWaitlist(ctx context.Context, req *pb.JoinWaitlistRequest) (*pb.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := req.Request
+	if r == nil || len(r.Passengers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one passenger required")
+	}
+	if len(r.Passengers) > maxPassengers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d passengers per booking", maxPassengers)
+	}
+	if r.HoldId != "" {
+		return nil, status.Error(codes.InvalidArgument, "a waitlisted booking cannot use a hold")
+	}
+	// A section alone is only a preference, which the allocator can honour
+	// when the entry is promoted.
+	for _, p := range r.Passengers {
+		if p.Seat != 0 {
+			return nil, status.Error(codes.InvalidArgument, "a waitlisted booking cannot pick seats")
+		}
+	}
+	if _, err := s.cfg.fareType(r.FareType); err != nil {
+		return nil, err
+	}
+	account, role, err := s.principalRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Priority != 0 && role != roleStaff && role != roleAdmin {
+		return nil, status.Error(codes.PermissionDenied, "only staff can set a waitlist priority")
+	}
+
+	ride, err := resolveRide(ctx, s.db, r.DepartureId, r.FromCode, r.ToCode)
+	if err != nil {
+		return nil, err
+	}
+	departure := ride.DepartureID
+	var departed bool
+	err = s.db.QueryRowContext(ctx, "SELECT departs_at <= now() FROM departure_stops WHERE departure_id = $1 AND stop = $2",
+		departure, ride.From).Scan(&departed)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if departed {
+		return nil, status.Errorf(codes.FailedPrecondition, "departure %d has left", departure)
+	}
+
+	r = proto.Clone(r).(*pb.ReservationRequest)
+	r.DepartureId = departure
+	body, err := protojson.Marshal(r)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encoding request: %v", err)
+	}
+	var accountID sql.NullInt64
+	if account != 0 {
+		accountID = sql.NullInt64{Int64: int64(account), Valid: true}
+	}
+	var id uint64
+	err = s.db.QueryRowContext(ctx, `INSERT INTO waitlist (departure_id, account_id, request, passengers, priority, from_stop, to_stop)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		departure, accountID, body, len(r.Passengers), req.Priority, ride.From, ride.To).Scan(&id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "joined waitlist",
+		"waitlist_id", id, "departure_id", departure, "passengers", len(r.Passengers), "priority", req.Priority)
+
+	// Seats may be free already, e.g. when only part of a large party
+	// fitted; the entry is then promoted straight away.
+	if err := s.promoteWaitlist(ctx, departure); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "waitlist promotion failed", "departure_id", departure, "error", err)
+	}
+	return s.waitlistEntry(ctx, id)
+}
+
+func (s *TicketReservationServer) ListWaitlist(ctx context.Context, req *pb.WaitlistQuery) (*pb.WaitlistEntries, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows *sql.Rows
+	var err error
+	if req.DepartureId != 0 {
+		if err := s.requireStaff(ctx); err != nil {
+			return nil, err
+		}
+		rows, err = s.db.QueryContext(ctx, waitlistSelect+` WHERE w.departure_id = $1
+			ORDER BY w.status = 'WAITING', w.priority DESC, w.id`, req.DepartureId)
+	} else {
+		account := principalAccount(ctx)
+		if account == 0 {
+			return nil, status.Error(codes.InvalidArgument, "departure_id required")
+		}
+		rows, err = s.db.QueryContext(ctx, waitlistSelect+" WHERE w.account_id = $1 ORDER BY w.id DESC", account)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+
+	list := &pb.WaitlistEntries{}
+	for rows.Next() {
+		e, err := scanWaitlist(rows)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		list.Entries = append(list.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return list, nil
+}
+
+func (s *TicketReservationServer) ClaimWaitlist(ctx context.Context, req *pb.ClaimWaitlistRequest) (*pb.ReservationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.WaitlistId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "waitlist_id required")
+	}
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	var st, prefix string
+	var expires sql.NullTime
+	var account sql.NullInt64
+	var body []byte
+	err = tx.QueryRowContext(ctx, `SELECT status, COALESCE(hold_prefix, ''), hold_expires_at, account_id, request
+		FROM waitlist WHERE id = $1 AND ($2 = 0 OR account_id = $2) FOR UPDATE`,
+		req.WaitlistId, owner).Scan(&st, &prefix, &expires, &account, &body)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "waitlist entry %d not found", req.WaitlistId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	switch {
+	case st == "WAITING":
+		return nil, status.Errorf(codes.FailedPrecondition, "waitlist entry %d is still waiting for seats", req.WaitlistId)
+	case st != "PROMOTED":
+		return nil, status.Errorf(codes.FailedPrecondition, "waitlist entry %d is %s", req.WaitlistId, st)
+	case !expires.Valid || !expires.Time.After(time.Now()):
+		return nil, status.Errorf(codes.FailedPrecondition, "the seats held for waitlist entry %d were released at %s",
+			req.WaitlistId, expires.Time.UTC().Format(windowTime))
+	}
+
+	r := &pb.ReservationRequest{}
+	if err := jsonIn.Unmarshal(body, r); err != nil {
+		return nil, status.Errorf(codes.Internal, "decoding request: %v", err)
+	}
+	r.PricePaid = req.PricePaid
+	holds := make([]string, len(r.Passengers))
+	for i := range holds {
+		holds[i] = holdName(prefix, i)
+	}
+	id, seats, err := s.reserveTx(ctx, tx, r, holds)
+	if err != nil {
+		return nil, err
+	}
+	unmet, err := s.unmetPreferences(ctx, tx, r.Passengers, seats)
+	if err != nil {
+		return nil, err
+	}
+	// The ticket belongs to whoever joined the waitlist, even when staff
+	// claim it for them.
+	if account.Valid {
+		if _, err := tx.ExecContext(ctx, "UPDATE tickets SET account_id = $1 WHERE id = $2", account, id); err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE waitlist SET status = 'BOOKED', ticket_id = $1 WHERE id = $2", id, req.WaitlistId); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	order := ticketOrder(id)
+	if err := s.expectPayment(ctx, tx, order, id); err != nil {
+		return nil, err
+	}
+	if err := s.announceBooking(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "waitlist entry booked", "waitlist_id", req.WaitlistId, "ticket_no", id)
+	for _, seat := range seats {
+		s.events.publish("TicketReserved", id, seat)
+	}
+	// A declined payment puts the seats back on hold for the entry; see
+	// settlePayment.
+	if err := s.pay(ctx, order, req.PaymentToken); err != nil {
+		return nil, err
+	}
+	resp, err := s.loadTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp.UnmetPreferences = unmet
+	return resp, nil
+}
+
+func (s *TicketReservationServer) LeaveWaitlist(ctx context.Context, req *pb.WaitlistQuery) (*pb.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.WaitlistId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "waitlist_id required")
+	}
+	owner, err := s.ticketOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Error: %v", err)
+	}
+	defer tx.Rollback()
+
+	var st, prefix string
+	var departure uint64
+	err = tx.QueryRowContext(ctx, `SELECT status, COALESCE(hold_prefix, ''), departure_id
+		FROM waitlist WHERE id = $1 AND ($2 = 0 OR account_id = $2) FOR UPDATE`,
+		req.WaitlistId, owner).Scan(&st, &prefix, &departure)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "waitlist entry %d not found", req.WaitlistId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if st != "WAITING" && st != "PROMOTED" {
+		return nil, status.Errorf(codes.FailedPrecondition, "waitlist entry %d is %s", req.WaitlistId, st)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE waitlist SET status = 'LEFT' WHERE id = $1", req.WaitlistId); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	if prefix != "" {
+		if err := releaseHolds(ctx, tx, prefix); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Commit Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "left waitlist", "waitlist_id", req.WaitlistId, "departure_id", departure)
+
+	if prefix != "" {
+		if err := s.promoteWaitlist(ctx, departure); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "waitlist promotion failed", "departure_id", departure, "error", err)
+		}
+	}
+	return s.waitlistEntry(ctx, req.WaitlistId)
+}
+
+// holdName is the hold ID of passenger i of a promoted waitlist entry.
+func holdName(prefix string, i int) string {
+	return prefix + "." + strconv.Itoa(i)
+}
+
+// releaseHolds frees every seat held for a promoted waitlist entry.
+func releaseHolds(ctx context.Context, tx *sql.Tx, prefix string) error {
+	_, err := tx.ExecContext(ctx, "UPDATE seats SET hold_id = NULL, held_until = NULL WHERE hold_id LIKE $1 || '.%'", prefix)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	return nil
+}
+
+// waitingEntry is a waitlist entry considered for promotion.
+type waitingEntry struct {
+	ID         uint64
+	Passengers int
+	Request    []byte
+	// Ride is the departure and stops the entry is for, and Departs when
+	// the train leaves the first of them.
+	Ride    seatRef
+	Departs time.Time
+}
+
+// promoteWaitlist holds free seats of the departure for the waiting entries,
+// in priority order. An entry that needs more seats than are free between
+// its stops is skipped, so a large party does not keep smaller ones behind
+// it from travelling. Callers must hold s.mu.
+func (s *TicketReservationServer) promoteWaitlist(ctx context.Context, departureID uint64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var free bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM seats WHERE departure_id = $1 AND "+seatFree+")", departureID).Scan(&free)
+	if err != nil {
+		return err
+	}
+	if !free {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT w.id, w.passengers, w.request, w.from_stop, w.to_stop, d.departs_at
+		FROM waitlist w JOIN departure_stops d ON d.departure_id = w.departure_id AND d.stop = w.from_stop
+		WHERE w.departure_id = $1 AND w.status = 'WAITING' AND d.departs_at > now()
+		ORDER BY w.priority DESC, w.id FOR UPDATE OF w`, departureID)
+	if err != nil {
+		return err
+	}
+	var waiting []waitingEntry
+	for rows.Next() {
+		e := waitingEntry{Ride: seatRef{DepartureID: departureID}}
+		if err := rows.Scan(&e.ID, &e.Passengers, &e.Request, &e.Ride.From, &e.Ride.To, &e.Departs); err != nil {
+			rows.Close()
+			return err
+		}
+		waiting = append(waiting, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var promoted []waitingEntry
+	var expiries []time.Time
+	for _, e := range waiting {
+		// The seats are held where the booking would have put them, so the
+		// party is kept together and seated by preference.
+		r := &pb.ReservationRequest{}
+		if err := jsonIn.Unmarshal(e.Request, r); err != nil {
+			return fmt.Errorf("decoding waitlist entry %d: %w", e.ID, err)
+		}
+		seats, err := s.allocateSeats(ctx, tx, e.Ride, r.Passengers, nil)
+		if status.Code(err) == codes.ResourceExhausted {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// Seats are never held past departure.
+		expires := time.Now().Add(s.cfg.WaitlistClaimTTL)
+		if expires.After(e.Departs) {
+			expires = e.Departs
+		}
+		prefix := newHoldID()
+		for i, got := range seats {
+			if err := holdSeat(ctx, tx, holdName(prefix, i), expires, got); err != nil {
+				return err
+			}
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE waitlist SET status = 'PROMOTED', hold_prefix = $1, hold_expires_at = $2 WHERE id = $3",
+			prefix, expires, e.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.notifyPromotion(ctx, tx, e, r.Passengers[0], expires); err != nil {
+			return err
+		}
+		promoted, expiries = append(promoted, e), append(expiries, expires)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for i, e := range promoted {
+		s.notifyPromoted(ctx, departureID, e, expiries[i])
+	}
+	return nil
+}
+
+// notifyPromoted announces a committed promotion as an event for live
+// clients and in the log; the customer's email is queued with the seats by
+// notifyPromotion.
+func (s *TicketReservationServer) notifyPromoted(ctx context.Context, departureID uint64, e waitingEntry, expires time.Time) {
+	var email string
+	r := &pb.ReservationRequest{}
+	if jsonIn.Unmarshal(e.Request, r) == nil && len(r.Passengers) > 0 {
+		email = r.Passengers[0].Email
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "waitlist entry promoted",
+		"waitlist_id", e.ID, "departure_id", departureID, "seats", e.Passengers, "claim_by", expires, logging.Email("email", email))
+	s.events.send(&pb.Event{Type: "WaitlistPromoted", DepartureId: departureID, WaitlistId: e.ID, At: timestamppb.Now()})
+}
+
+// runWaitlist expires unclaimed promotions and promotes the next entries in
+// line every minute while the server runs.
+func (s *TicketReservationServer) runWaitlist(logger *slog.Logger) {
+	for range time.Tick(time.Minute) {
+		s.mu.Lock()
+		err := s.sweepWaitlist(context.Background(), logger)
+		s.mu.Unlock()
+		if err != nil {
+			logger.Error("waitlist sweep failed", "error", err)
+		}
+	}
+}
+
+// sweepWaitlist expires promotions nobody claimed in time and entries whose
+// train has left, then promotes waiting entries wherever seats are free,
+// e.g. because a hold lapsed.
+func (s *TicketReservationServer) sweepWaitlist(ctx context.Context, logger *slog.Logger) error {
+	rows, err := s.db.QueryContext(ctx, `UPDATE waitlist w SET status = 'EXPIRED' FROM departure_stops d
+		WHERE d.departure_id = w.departure_id AND d.stop = w.from_stop AND ((w.status = 'PROMOTED' AND w.hold_expires_at <= now())
+			OR (w.status IN ('WAITING', 'PROMOTED') AND d.departs_at <= now()))
+		RETURNING w.id, w.departure_id`)
+	if err != nil {
+		return err
+	}
+	var expired []*pb.Event
+	for rows.Next() {
+		e := &pb.Event{Type: "WaitlistExpired", At: timestamppb.Now()}
+		if err := rows.Scan(&e.WaitlistId, &e.DepartureId); err != nil {
+			rows.Close()
+			return err
+		}
+		expired = append(expired, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(expired) > 0 {
+		logger.Info("waitlist entries expired", "count", len(expired))
+	}
+	for _, e := range expired {
+		s.events.send(e)
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT DISTINCT w.departure_id FROM waitlist w
+		JOIN departure_stops d ON d.departure_id = w.departure_id AND d.stop = w.from_stop WHERE w.status = 'WAITING' AND d.departs_at > now()`)
+	if err != nil {
+		return err
+	}
+	var departures []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		departures = append(departures, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range departures {
+		if err := s.promoteWaitlist(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
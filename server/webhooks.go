@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Partner webhooks are fed by the event relay like any other sink: the
+// webhookFanout sink turns every domain event into one delivery per
+// matching subscription, and deliverWebhooks sends those on their own
+// schedule. A partner that is down therefore only delays its own
+// deliveries, never the relay or the other partners.
+
+func (a *TicketAdminServer) CreateWebhook(ctx context.Context, req *pb.Webhook) (*pb.Webhook, error) {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "url must be an http or https URL")
+	}
+	for _, typ := range req.EventTypes {
+		if !slices.Contains(domainEventTypes, typ) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type %q, want one of %v", typ, domainEventTypes)
+		}
+	}
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		secret = "whsec_" + hex.EncodeToString(b)
+	}
+
+	a.srv.mu.Lock()
+	defer a.srv.mu.Unlock()
+
+	// Events recorded before the subscription existed are not delivered;
+	// every transaction that records one holds s.mu, so max(id) is exact.
+	w := &pb.Webhook{Url: req.Url, EventTypes: req.EventTypes, AccountId: req.AccountId, Secret: secret}
+	var created time.Time
+	err = a.srv.db.QueryRowContext(ctx, `INSERT INTO webhooks (url, event_types, account_id, secret, from_event_id)
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(max(id), 0) FROM event_outbox)) RETURNING id, created_at`,
+		w.Url, pq.Array(w.EventTypes), w.AccountId, w.Secret,
+	).Scan(&w.WebhookId, &created)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Insert Error: %v", err)
+	}
+	w.CreatedAt = timestamppb.New(created)
+	logging.FromContext(ctx).InfoContext(ctx, "webhook created", "webhook_id", w.WebhookId, "url", w.Url, "event_types", w.EventTypes)
+	return w, nil
+}
+
+func (a *TicketAdminServer) ListWebhooks(ctx context.Context, req *pb.EmptyRequest) (*pb.WebhookList, error) {
+	rows, err := a.srv.db.QueryContext(ctx, "SELECT id, url, event_types, account_id, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.WebhookList{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		resp.Webhooks = append(resp.Webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return resp, nil
+}
+
+func (a *TicketAdminServer) DeleteWebhook(ctx context.Context, req *pb.WebhookQuery) (*pb.Webhook, error) {
+	w, err := scanWebhook(a.srv.db.QueryRowContext(ctx,
+		"DELETE FROM webhooks WHERE id = $1 RETURNING id, url, event_types, account_id, created_at", req.WebhookId))
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "webhook not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "webhook deleted", "webhook_id", w.WebhookId, "url", w.Url)
+	return w, nil
+}
+
+func scanWebhook(row interface{ Scan(...any) error }) (*pb.Webhook, error) {
+	w := &pb.Webhook{}
+	var created time.Time
+	if err := row.Scan(&w.WebhookId, &w.Url, pq.Array(&w.EventTypes), &w.AccountId, &created); err != nil {
+		return nil, err
+	}
+	w.CreatedAt = timestamppb.New(created)
+	return w, nil
+}
+
+const deliverySelect = `SELECT d.id, d.webhook_id, w.url, d.event_id, d.event_type, d.ticket_no, d.status, d.attempts,
+	d.last_status_code, d.last_error, d.created_at, d.next_attempt_at, d.delivered_at
+	FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id`
+
+func (a *TicketAdminServer) ListWebhookDeliveries(ctx context.Context, req *pb.WebhookDeliveryQuery) (*pb.WebhookDeliveryList, error) {
+	limit := req.Limit
+	switch {
+	case limit == 0:
+		limit = 50
+	case limit > 500:
+		limit = 500
+	}
+	st := ""
+	if req.Status != nil {
+		st = req.Status.String()
+	}
+	rows, err := a.srv.db.QueryContext(ctx, deliverySelect+`
+		WHERE ($1 = 0 OR d.webhook_id = $1) AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC LIMIT $3`, req.WebhookId, st, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.WebhookDeliveryList{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DB Scan Error: %v", err)
+		}
+		resp.Deliveries = append(resp.Deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	return resp, nil
+}
+
+func (a *TicketAdminServer) RetryWebhookDelivery(ctx context.Context, req *pb.WebhookDeliveryQuery) (*pb.WebhookDelivery, error) {
+	res, err := a.srv.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = 'PENDING', attempts = 0, next_attempt_at = now()
+		WHERE id = $1 AND status <> 'DELIVERED'`, req.DeliveryId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Update Error: %v", err)
+	}
+	d, err := scanDelivery(a.srv.db.QueryRowContext(ctx, deliverySelect+" WHERE d.id = $1", req.DeliveryId))
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "delivery not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DB Query Error: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "delivery %d was already delivered", d.DeliveryId)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "webhook delivery requeued", "delivery_id", d.DeliveryId, "webhook_id", d.WebhookId)
+	return d, nil
+}
+
+func scanDelivery(row interface{ Scan(...any) error }) (*pb.WebhookDelivery, error) {
+	d := &pb.WebhookDelivery{}
+	var st string
+	var created, next time.Time
+	var delivered sql.NullTime
+	err := row.Scan(&d.DeliveryId, &d.WebhookId, &d.Url, &d.EventId, &d.EventType, &d.TicketNo, &st, &d.Attempts,
+		&d.LastStatusCode, &d.LastError, &created, &next, &delivered)
+	if err != nil {
+		return nil, err
+	}
+	d.Status = pb.WebhookDelivery_Status(pb.WebhookDelivery_Status_value[st])
+	d.CreatedAt = timestamppb.New(created)
+	if d.Status == pb.WebhookDelivery_PENDING {
+		d.NextAttemptAt = timestamppb.New(next)
+	}
+	if delivered.Valid {
+		d.DeliveredAt = timestamppb.New(delivered.Time)
+	}
+	return d, nil
+}
+
+// webhookFanout is the event sink that queues a delivery of each event for
+// every subscription that wants it. Queueing is idempotent, so an event the
+// relay hands over twice is still delivered once per subscription.
+type webhookFanout struct {
+	db *sql.DB
+}
+
+func (f *webhookFanout) Name() string { return "webhooks" }
+
+func (f *webhookFanout) Deliver(ctx context.Context, e outboxEvent) error {
+	var event pb.DomainEvent
+	if err := jsonIn.Unmarshal(e.Payload, &event); err != nil {
+		return fmt.Errorf("event %d: %w", e.ID, err)
+	}
+	_, err := f.db.ExecContext(ctx, `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, ticket_no)
+		SELECT id, $1, $2, $3 FROM webhooks
+		WHERE from_event_id < $1
+			AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+			AND (account_id = 0 OR account_id = $4)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`,
+		e.ID, e.Type, e.TicketNo, event.GetTicket().GetAccountId())
+	return err
+}
+
+// deliverWebhooks sends the due webhook deliveries every few seconds while
+// the server runs. Like sendMail it does not touch tickets or seats and
+// runs without s.mu.
+func (s *TicketReservationServer) deliverWebhooks(logger *slog.Logger) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	for range time.Tick(5 * time.Second) {
+		if err := s.sendWebhooks(context.Background(), logger, client); err != nil {
+			logger.Error("webhook delivery failed", "error", err)
+		}
+	}
+}
+
+// dueDelivery is a webhook delivery with what it takes to send it.
+type dueDelivery struct {
+	ID, WebhookID, EventID uint64
+	Attempts               int
+	URL, Secret, Type      string
+	Payload                []byte
+}
+
+// sendWebhooks POSTs the deliveries that are due. A delivery counts once
+// the partner answers 2xx; otherwise it is retried with exponential
+// backoff from WEBHOOK_RETRY_BASE and declared dead after
+// WEBHOOK_MAX_ATTEMPTS attempts.
+func (s *TicketReservationServer) sendWebhooks(ctx context.Context, logger *slog.Logger, client *http.Client) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT d.id, d.webhook_id, d.event_id, d.attempts, w.url, w.secret, d.event_type, e.payload
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id JOIN event_outbox e ON e.id = d.event_id
+		WHERE d.status = 'PENDING' AND d.next_attempt_at <= now() ORDER BY d.id LIMIT 50`)
+	if err != nil {
+		return err
+	}
+	var due []dueDelivery
+	for rows.Next() {
+		var d dueDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Attempts, &d.URL, &d.Secret, &d.Type, &d.Payload); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, d := range due {
+		code, err := postWebhook(ctx, client, d)
+		attempts := d.Attempts + 1
+		if err == nil {
+			_, err = s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = 'DELIVERED', attempts = $1, last_status_code = $2,
+				last_error = '', delivered_at = now() WHERE id = $3`, attempts, code, d.ID)
+			if err != nil {
+				return err
+			}
+			logger.Info("webhook delivered", "delivery_id", d.ID, "webhook_id", d.WebhookID, "event_id", d.EventID)
+			continue
+		}
+
+		st, retry := "PENDING", retryBackoff(s.cfg.WebhookRetryBase, attempts)
+		if attempts >= s.cfg.WebhookMaxAttempts {
+			st = "DEAD"
+		}
+		logger.Warn("webhook not delivered", "delivery_id", d.ID, "webhook_id", d.WebhookID, "attempts", attempts,
+			"status", st, "retry_in", retry, "error", err)
+		_, err = s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3,
+			last_status_code = $4, last_error = $5 WHERE id = $6`,
+			st, attempts, time.Now().Add(retry), code, err.Error(), d.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postWebhook sends one delivery and returns the HTTP status it got, zero
+// if none. The X-Signature header is "t=<unix time>,v1=<hex HMAC-SHA256 of
+// "<unix time>.<body>" keyed with the secret>"; the time lets partners
+// reject replays.
+func postWebhook(ctx context.Context, client *http.Client, d dueDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", "t="+ts+",v1="+signWebhook(d.Secret, ts, d.Payload))
+	req.Header.Set("X-Webhook-Id", strconv.FormatUint(d.WebhookID, 10))
+	req.Header.Set("X-Delivery-Id", strconv.FormatUint(d.ID, 10))
+	req.Header.Set("X-Event-Id", strconv.FormatUint(d.EventID, 10))
+	req.Header.Set("X-Event-Type", d.Type)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("partner answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func signWebhook(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requireSignIn returns the caller's session, or redirects to the sign-in
+// page and returns nil.
+func requireSignIn(w http.ResponseWriter, r *http.Request) *session {
+	s := auth.get(r)
+	if s == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+	return s
+}
+
+func showLogin(w http.ResponseWriter, r *http.Request) {
+	render(w, r, "login.html", page{})
+}
+
+func handleLogin(w http.ResponseWriter, r *http.Request) {
+	f := newForm(r, "login")
+	email := f.email("email")
+	password := f.values.Get("password")
+	if password == "" {
+		f.fail("password", "Password is required.")
+	}
+	if !f.ok() {
+		renderForm(w, r, "login.html", http.StatusBadRequest, nil, f)
+		return
+	}
+
+	acc, err := client.SignIn(rpcContext(r, nil), &pb.SignInRequest{Email: email, Password: password})
+	switch status.Code(err) {
+	case codes.OK:
+		auth.start(w, acc)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	case codes.Unauthenticated:
+		f.fail("password", "Wrong email or password.")
+	default:
+		f.fail("email", "Sign-in failed, please try again.")
+	}
+	renderForm(w, r, "login.html", http.StatusUnauthorized, nil, f)
+}
+
+func showRegister(w http.ResponseWriter, r *http.Request) {
+	render(w, r, "register.html", page{})
+}
+
+func handleRegister(w http.ResponseWriter, r *http.Request) {
+	f := newForm(r, "register")
+	email := f.email("email")
+	name := f.value("name")
+	if len(name) > 200 {
+		f.fail("name", "Name is too long.")
+	}
+	password := f.values.Get("password")
+	switch {
+	case len(password) < 8:
+		f.fail("password", "Use at least 8 characters.")
+	case len(password) > 72:
+		f.fail("password", "Use at most 72 characters.")
+	case f.values.Get("confirm") != password:
+		f.fail("confirm", "Passwords do not match.")
+	}
+	if !f.ok() {
+		renderForm(w, r, "register.html", http.StatusBadRequest, nil, f)
+		return
+	}
+
+	acc, err := client.CreateAccount(rpcContext(r, nil), &pb.CreateAccountRequest{Email: email, Name: name, Password: password})
+	switch status.Code(err) {
+	case codes.OK:
+		auth.start(w, acc)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	case codes.AlreadyExists:
+		f.fail("email", "An account with this email already exists.")
+	case codes.InvalidArgument:
+		f.fail("email", status.Convert(err).Message())
+	default:
+		f.fail("email", "Registration failed, please try again.")
+	}
+	renderForm(w, r, "register.html", http.StatusBadRequest, nil, f)
+}
+
+func handleLogout(w http.ResponseWriter, r *http.Request) {
+	auth.end(w)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// handleMyBookings lists the tickets and waitlist entries of the signed-in
+// account.
+func handleMyBookings(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	renderBookings(w, r, http.StatusOK, s, "")
+}
+
+// renderBookings shows My bookings, with msg about an action that failed.
+func renderBookings(w http.ResponseWriter, r *http.Request, code int, s *session, msg string) {
+	resp, err := client.SearchTickets(rpcContext(r, s), &pb.SearchRequest{AccountId: s.AccountID})
+	if status.Code(err) == codes.Unavailable {
+		renderDegraded(w, r)
+		return
+	}
+	data := page{Session: s, Error: msg}
+	if err != nil {
+		data.Error = "Could not load your bookings."
+	} else {
+		data.Tickets = resp.Tickets
+	}
+	data.Waitlist = waitlistRows(r, s)
+	ui.render(w, r, code, "bookings.html", data)
+}
+
+// handleTicketPDF downloads the printable ticket. The server only renders
+// tickets of the signed-in account, or any ticket for admins.
+func handleTicketPDF(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	tNo, err := strconv.ParseUint(r.PathValue("ticket_no"), 10, 64)
+	if err != nil || tNo == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	doc, err := client.GetTicketDocument(rpcContext(r, s), &pb.ReservationRequest{TicketNo: &tNo})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	case codes.NotFound:
+		http.NotFound(w, r)
+		return
+	default:
+		ui.render(w, r, http.StatusBadGateway, "result.html", page{Session: s, Title: "Error", Error: status.Convert(err).Message()})
+		return
+	}
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Filename))
+	w.Write(doc.Content)
+}
+
+// handleAdmin shows every booking. Only admin accounts may see it.
+func handleAdmin(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	if !s.Admin() {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	resp, err := client.GetAllTickets(rpcContext(r, s), &pb.EmptyRequest{})
+	if status.Code(err) == codes.Unavailable {
+		renderDegraded(w, r)
+		return
+	}
+	data := page{Session: s}
+	if err != nil {
+		data.Error = "Could not load bookings."
+	} else {
+		data.Tickets = resp.Tickets
+	}
+	render(w, r, "admin.html", data)
+}
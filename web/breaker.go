@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// breaker is a circuit breaker around the gRPC connection. After threshold
+// consecutive calls fail because the server is unreachable or too slow it
+// opens and fails every call immediately for cooldown. The first call after
+// the cooldown is let through as a probe: success closes the breaker, failure
+// opens it again.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+	probing  bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// errOpen is returned for calls rejected by an open breaker.
+var errOpen = status.Error(codes.Unavailable, "ticket service unavailable (circuit open)")
+
+// Open reports whether calls are currently being rejected.
+func (b *breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.openedAt.IsZero() && (b.probing || time.Since(b.openedAt) < b.cooldown)
+}
+
+// RetryAfter is how long until the breaker lets a probe through.
+func (b *breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return 0
+	}
+	return max(b.cooldown-time.Since(b.openedAt), time.Second)
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.openedAt.IsZero():
+		return true
+	case b.probing || time.Since(b.openedAt) < b.cooldown:
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		b.failures++
+		if b.failures >= b.threshold || !b.openedAt.IsZero() {
+			b.openedAt = time.Now()
+		}
+	default:
+		// Any answer from the server, including an application error,
+		// shows that it is up.
+		b.failures = 0
+		b.openedAt = time.Time{}
+	}
+}
+
+// UnaryClientInterceptor applies the breaker to every unary call.
+func (b *breaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.allow() {
+			return errOpen
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if ctx.Err() == context.Canceled {
+			// The browser went away; that says nothing about the server.
+			b.mu.Lock()
+			b.probing = false
+			b.mu.Unlock()
+			return err
+		}
+		b.record(err)
+		return err
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	csrfCookie = "csrf"
+	csrfField  = "csrf_token"
+
+	maxFormBytes = 64 << 10
+)
+
+type csrfCtxKey struct{}
+
+// protect adds the security headers to every response and rejects POST
+// requests whose csrf_token field does not match the browser's CSRF cookie.
+// The cookie holds a random seed and the form carries its HMAC, so a token
+// cannot be made up without the session key.
+func (m *sessions) protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", "default-src 'self'; form-action 'self'; frame-ancestors 'none'; base-uri 'none'")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "same-origin")
+
+		var seed string
+		if c, err := r.Cookie(csrfCookie); err == nil && c.Value != "" {
+			seed = c.Value
+		} else {
+			b := make([]byte, 32)
+			rand.Read(b)
+			seed = base64.RawURLEncoding.EncodeToString(b)
+			http.SetCookie(w, &http.Cookie{
+				Name:     csrfCookie,
+				Value:    seed,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   m.secure,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+		token := m.sign("csrf:" + seed)
+
+		if r.Method == http.MethodPost {
+			r.Body = http.MaxBytesReader(w, r.Body, maxFormBytes)
+			if !hmac.Equal([]byte(r.PostFormValue(csrfField)), []byte(token)) {
+				http.Error(w, "Invalid or missing CSRF token. Reload the page and try again.", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfCtxKey{}, token)))
+	})
+}
+
+func csrfToken(r *http.Request) string {
+	t, _ := r.Context().Value(csrfCtxKey{}).(string)
+	return t
+}
+
+// form validates one submitted form. Each check records a message for the
+// field it fails on; the form is re-rendered with those messages next to the
+// fields and the values the user typed.
+type form struct {
+	Name   string
+	values url.Values
+	errs   map[string]string
+}
+
+func newForm(r *http.Request, name string) *form {
+	return &form{Name: name, values: r.PostForm, errs: map[string]string{}}
+}
+
+func (f *form) value(field string) string { return strings.TrimSpace(f.values.Get(field)) }
+
+func (f *form) fail(field, msg string) {
+	if _, ok := f.errs[field]; !ok {
+		f.errs[field] = msg
+	}
+}
+
+func (f *form) ok() bool { return len(f.errs) == 0 }
+
+func (f *form) required(field, label string) string {
+	v := f.value(field)
+	if v == "" {
+		f.fail(field, label+" is required.")
+	}
+	if len(v) > 200 {
+		f.fail(field, label+" is too long.")
+	}
+	return v
+}
+
+// maxLen fails field if it is longer than n bytes.
+func (f *form) maxLen(field, label string, n int) {
+	if len(f.value(field)) > n {
+		f.fail(field, label+" must be at most "+strconv.Itoa(n)+" characters.")
+	}
+}
+
+func (f *form) email(field string) string {
+	v := f.required(field, "Email")
+	if v != "" {
+		if a, err := mail.ParseAddress(v); err != nil || a.Address != v {
+			f.fail(field, "Enter a valid email address.")
+		}
+	}
+	return v
+}
+
+// number parses a positive integer no larger than max.
+func (f *form) number(field, label string, max uint64) uint64 {
+	v := f.required(field, label)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || n == 0 || n > max {
+		f.fail(field, label+" must be a whole number between 1 and "+strconv.FormatUint(max, 10)+".")
+		return 0
+	}
+	return n
+}
+
+var sectionPattern = regexp.MustCompile(`^[A-Z0-9]{1,8}$`)
+
+func (f *form) section(field string) string {
+	v := strings.ToUpper(f.required(field, "Section"))
+	if v != "" && !sectionPattern.MatchString(v) {
+		f.fail(field, "Section must be letters or digits, e.g. A.")
+	}
+	return v
+}
+
+// page returns the template data for re-rendering the form.
+func (f *form) page() formState {
+	return formState{Name: f.Name, Values: f.values, Errors: f.errs}
+}
+
+// formState is the submitted form shown back to the user.
+type formState struct {
+	Name   string
+	Values url.Values
+	Errors map[string]string
+}
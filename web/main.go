@@ -1,127 +1,210 @@
 package main
 
 import (
-	"context"
-	"fmt"
-	"html/template"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	"github.com/Akash-private/Cloudbees_code/internal/rpcclient"
 	pb "github.com/Akash-private/Cloudbees_code/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
-var client pb.TicketReservationClient
+var (
+	client pb.TicketReservationClient
+	// apiKey is the bridge's key for the TicketReservation service.
+	apiKey string
+	// admin is only set when ADMIN_TOKEN is, for the admin webhook pages.
+	admin      pb.TicketAdminClient
+	adminToken string
+	cb         *breaker
+	auth       *sessions
+	ui         *views
+)
 
 func main() {
+	logger := logging.New("web-ui")
+	slog.SetDefault(logger)
+
+	rc, err := rpcclient.LoadConfig()
+	if err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	auth = newSessions(logger)
+	ui, err = newViews(logger)
+	if err != nil {
+		logger.Error("could not parse templates", "error", err)
+		os.Exit(1)
+	}
+	cb = newBreaker(getenvInt("BREAKER_THRESHOLD", 5), getenvDuration("BREAKER_COOLDOWN", 30*time.Second))
+
 	// Connect to gRPC server using the Docker service name
-	conn, err := grpc.Dial("grpc-server:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts := append(rpcclient.DialOptions(rc, cb.UnaryClientInterceptor()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(getenv("GRPC_SERVER", "grpc-server:50051"), opts...)
 	if err != nil {
-		log.Fatalf("gRPC connection failed: %v", err)
+		logger.Error("gRPC connection failed", "error", err)
+		os.Exit(1)
 	}
 	defer conn.Close()
 	client = pb.NewTicketReservationClient(conn)
-
-	// Routes
-	http.HandleFunc("/", handleHome)
-	http.HandleFunc("/book", handleBook)
-	http.HandleFunc("/modify", handleModify)
-	http.HandleFunc("/cancel", handleCancel)
-
-	fmt.Println("🌐 Web UI starting on http://localhost:8888")
-	log.Fatal(http.ListenAndServe(":8888", nil))
-}
-
-func handleBook(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		return
+	if apiKey = os.Getenv("API_KEY"); apiKey == "" {
+		logger.Error("API_KEY not set; the server rejects calls without one")
+		os.Exit(1)
+	}
+	if adminToken = os.Getenv("ADMIN_TOKEN"); adminToken != "" {
+		admin = pb.NewTicketAdminClient(conn)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	resp, err := client.ReserveTicket(ctx, &pb.ReservationRequest{
-		FromCode: "London",
-		ToCode:   "Paris",
-		Passengers: []*pb.UserDetails{
-			{
-				FirstName: r.FormValue("first_name"),
-				Email:     r.FormValue("email"),
-			},
-		},
-	})
-
-	renderResult(w, "Booking Result", resp, err)
+	// Routes. Patterns carry the method, so anything else gets a 405 with
+	// an Allow header.
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", handleHome)
+	mux.HandleFunc("GET /book", handleResume)
+	mux.HandleFunc("GET /book/search", showSearch)
+	mux.HandleFunc("POST /book/search", handleSearch)
+	mux.HandleFunc("GET /book/departure", showDeparture)
+	mux.HandleFunc("POST /book/departure", handleDeparture)
+	mux.HandleFunc("GET /book/passengers", showPassengers)
+	mux.HandleFunc("POST /book/passengers", handlePassengers)
+	mux.HandleFunc("GET /book/seats", showSeats)
+	mux.HandleFunc("POST /book/seats", handleSeats)
+	mux.HandleFunc("GET /book/review", showReview)
+	mux.HandleFunc("POST /book/review", handleConfirm)
+	mux.HandleFunc("POST /book/waitlist", handleJoinWaitlist)
+	mux.HandleFunc("POST /modify", handleModify)
+	mux.HandleFunc("POST /cancel", handleCancel)
+	mux.HandleFunc("GET /login", showLogin)
+	mux.HandleFunc("POST /login", handleLogin)
+	mux.HandleFunc("GET /register", showRegister)
+	mux.HandleFunc("POST /register", handleRegister)
+	mux.HandleFunc("POST /logout", handleLogout)
+	mux.HandleFunc("GET /bookings", handleMyBookings)
+	mux.HandleFunc("GET /bookings/{ticket_no}/ticket.pdf", handleTicketPDF)
+	mux.HandleFunc("POST /checkin", handleCheckIn)
+	mux.HandleFunc("POST /waitlist/claim", handleClaimWaitlist)
+	mux.HandleFunc("POST /waitlist/leave", handleLeaveWaitlist)
+	mux.HandleFunc("GET /admin", handleAdmin)
+	mux.HandleFunc("GET /admin/webhooks", handleWebhooks)
+	mux.HandleFunc("POST /admin/webhooks/retry", handleRetryWebhook)
+	mux.HandleFunc("GET /station", handleStation)
+	mux.HandleFunc("GET /station/departures/{departure_id}", showManifest)
+	mux.HandleFunc("POST /station/checkin", handleStaffCheckIn)
+	mux.HandleFunc("POST /station/board", handleBoard)
+	mux.Handle("GET /static/", ui.static())
+
+	logger.Info("web UI listening", "addr", ":8888")
+	if err := http.ListenAndServe(":8888", logging.Middleware(logger, auth.protect(mux))); err != nil {
+		logger.Error("web UI stopped", "error", err)
+		os.Exit(1)
+	}
 }
 
 func handleModify(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	sess := requireSignIn(w, r)
+	if sess == nil {
+		return
+	}
+	f := newForm(r, "modify")
+	tNo := f.number("ticket_no", "Ticket number", math.MaxInt32)
+	section := f.section("section")
+	seat := f.number("seat", "Seat", 999)
+	if !f.ok() {
+		renderForm(w, r, "index.html", http.StatusBadRequest, sess, f)
 		return
 	}
 
-	tNo, _ := strconv.ParseUint(r.FormValue("ticket_no"), 10, 64)
-	seat, _ := strconv.ParseUint(r.FormValue("seat"), 10, 32)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	resp, err := client.ModifyTicket(ctx, &pb.ReservationRequest{
+	resp, err := client.ModifyTicket(rpcContext(r, sess), &pb.ReservationRequest{
 		TicketNo: &tNo,
 		Passengers: []*pb.UserDetails{
-			{Section: r.FormValue("section"), Seat: uint32(seat)},
+			{Section: section, Seat: uint32(seat)},
 		},
 	})
 
-	renderResult(w, "Modification Result", resp, err)
+	renderResult(w, r, sess, f, "Modification Result", resp, err)
 }
 
 func handleCancel(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	sess := requireSignIn(w, r)
+	if sess == nil {
+		return
+	}
+	f := newForm(r, "cancel")
+	tNo := f.number("ticket_no", "Ticket number", math.MaxInt32)
+	if !f.ok() {
+		renderForm(w, r, "index.html", http.StatusBadRequest, sess, f)
 		return
 	}
 
-	tNo, _ := strconv.ParseUint(r.FormValue("ticket_no"), 10, 64)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	resp, err := client.CancelTicket(ctx, &pb.ReservationRequest{
+	resp, err := client.CancelTicket(rpcContext(r, sess), &pb.ReservationRequest{
 		TicketNo: &tNo,
 	})
 
-	renderResult(w, "Cancellation Result", resp, err)
+	renderResult(w, r, sess, f, "Cancellation Result", resp, err)
 }
 
-func renderResult(w http.ResponseWriter, title string, resp *pb.ReservationResponse, err error) {
-	if err != nil {
-		fmt.Fprintf(w, "<h2>Error</h2><p>%v</p><a href='/'>Go Back</a>", err)
+// renderResult shows the outcome of a form submission. Errors the user can
+// fix are shown next to the offending field of the form.
+func renderResult(w http.ResponseWriter, r *http.Request, sess *session, f *form, title string, resp *pb.ReservationResponse, err error) {
+	switch status.Code(err) {
+	case codes.OK:
+		render(w, r, "result.html", page{Session: sess, Title: title, Ticket: resp})
+		return
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	case codes.NotFound:
+		f.fail("ticket_no", "No ticket with this number in your bookings.")
+	case codes.FailedPrecondition, codes.ResourceExhausted:
+		f.fail("seat", status.Convert(err).Message())
+	case codes.InvalidArgument:
+		f.fail("ticket_no", status.Convert(err).Message())
+	default:
+		ui.render(w, r, http.StatusBadGateway, "result.html", page{Session: sess, Title: "Error", Error: status.Convert(err).Message()})
 		return
 	}
-	fmt.Fprintf(w, "<h2>%s</h2><p>Ticket No: %d</p><p>Status: %s</p><a href='/'>Go Back</a>", title, resp.TicketNo, resp.Status)
+	renderForm(w, r, "index.html", http.StatusConflict, sess, f)
 }
 
 func handleHome(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	sess := requireSignIn(w, r)
+	if sess == nil {
+		return
+	}
+	if cb.Open() {
+		renderDegraded(w, r)
+		return
+	}
+	render(w, r, "index.html", page{Session: sess, Booking: loadBooking(r, sess)})
+}
 
-	// 1. Fetch all tickets from gRPC server to display in the table
-	resp, err := client.GetAllTickets(ctx, &pb.EmptyRequest{})
-	if err != nil {
-		// If the server is down or DB is empty, we handle it gracefully
-		log.Printf("Could not fetch tickets: %v", err)
-		// We can still show the page even if the list is empty
-		resp = &pb.AllTicketsResponse{}
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getenvInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n < 1 {
+		return def
 	}
+	return n
+}
 
-	tmpl, err := template.ParseFiles("index.html")
+func getenvDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
 	if err != nil {
-		http.Error(w, "Template index.html not found", 500)
-		return
+		return def
 	}
-
-	// 2. Pass the list of tickets to the HTML template
-	tmpl.Execute(w, resp.Tickets)
+	return d
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akash-private/Cloudbees_code/internal/logging"
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+const sessionCookie = "session"
+
+// session is the signed-in user. It is kept entirely in a cookie, signed
+// with HMAC-SHA256 so it cannot be forged or altered by the browser.
+type session struct {
+	AccountID uint64 `json:"id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Expires   int64  `json:"exp"`
+}
+
+func (s *session) Admin() bool { return s != nil && s.Role == "admin" }
+
+// Staff reports whether s may use the station pages. Admins can too.
+func (s *session) Staff() bool { return s != nil && (s.Role == "staff" || s.Role == "admin") }
+
+// sessions issues and verifies session cookies.
+type sessions struct {
+	key    []byte
+	ttl    time.Duration
+	secure bool
+}
+
+// newSessions uses SESSION_KEY to sign cookies. Without it a random key is
+// generated, which signs everybody out whenever the web bridge restarts.
+func newSessions(logger *slog.Logger) *sessions {
+	key := []byte(os.Getenv("SESSION_KEY"))
+	if len(key) == 0 {
+		logger.Warn("SESSION_KEY not set, sessions will not survive a restart")
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &sessions{
+		key:    key,
+		ttl:    getenvDuration("SESSION_TTL", 12*time.Hour),
+		secure: getenv("COOKIE_SECURE", "true") == "true",
+	}
+}
+
+func (m *sessions) sign(payload string) string {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// start signs the account in by setting the session cookie.
+func (m *sessions) start(w http.ResponseWriter, a *pb.Account) {
+	b, _ := json.Marshal(session{
+		AccountID: a.AccountId,
+		Email:     a.Email,
+		Name:      a.Name,
+		Role:      a.Role,
+		Expires:   time.Now().Add(m.ttl).Unix(),
+	})
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    payload + "." + m.sign(payload),
+		Path:     "/",
+		MaxAge:   int(m.ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (m *sessions) end(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// get returns the session of the request, or nil if the user is not signed
+// in or the cookie is invalid or expired.
+func (m *sessions) get(r *http.Request) *session {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	payload, sig, ok := strings.Cut(c.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(m.sign(payload))) {
+		return nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+	var s session
+	if json.Unmarshal(b, &s) != nil || time.Now().Unix() > s.Expires {
+		return nil
+	}
+	return &s
+}
+
+// rpcContext returns the context for calls made on behalf of s, or of a
+// guest if s is nil. Every call carries the bridge's API key; the account is
+// sent as the x-principal metadata so the server can attribute bookings and
+// check ownership.
+func rpcContext(r *http.Request, s *session) context.Context {
+	ctx := metadata.AppendToOutgoingContext(r.Context(), logging.APIKeyKey, apiKey)
+	if s == nil {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, logging.PrincipalKey, "account:"+strconv.FormatUint(s.AccountID, 10))
+}
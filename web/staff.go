@@ -0,0 +1,182 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// manifest is the boarding manifest of a departure grouped by coach.
+type manifest struct {
+	*pb.BoardingManifest
+	Coaches                            []coach
+	Booked, CheckedIn, Boarded, NoShow int
+}
+
+type coach struct {
+	Section    string
+	Passengers []*pb.BoardingManifest_Passenger
+}
+
+func newManifest(m *pb.BoardingManifest) *manifest {
+	v := &manifest{BoardingManifest: m}
+	for _, p := range m.Passengers {
+		if n := len(v.Coaches); n == 0 || v.Coaches[n-1].Section != p.Section {
+			v.Coaches = append(v.Coaches, coach{Section: p.Section})
+		}
+		c := &v.Coaches[len(v.Coaches)-1]
+		c.Passengers = append(c.Passengers, p)
+		switch p.State {
+		case pb.BoardingManifest_BOOKED:
+			v.Booked++
+		case pb.BoardingManifest_CHECKED_IN:
+			v.CheckedIn++
+		case pb.BoardingManifest_BOARDED:
+			v.Boarded++
+		case pb.BoardingManifest_NO_SHOW:
+			v.NoShow++
+		}
+	}
+	return v
+}
+
+// requireStaff returns the session of a staff or admin account. Others are
+// sent to sign in or refused.
+func requireStaff(w http.ResponseWriter, r *http.Request) *session {
+	s := requireSignIn(w, r)
+	if s != nil && !s.Staff() {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	return s
+}
+
+// handleStation lists the day's departures for station staff.
+func handleStation(w http.ResponseWriter, r *http.Request) {
+	s := requireStaff(w, r)
+	if s == nil {
+		return
+	}
+	date := r.URL.Query().Get("date")
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		date = time.Now().UTC().Format(time.DateOnly)
+	}
+	resp, err := client.ListDepartures(rpcContext(r, s), &pb.DeparturesRequest{Date: date})
+	if status.Code(err) == codes.Unavailable {
+		renderDegraded(w, r)
+		return
+	}
+	data := page{Session: s, Title: date}
+	if err != nil {
+		data.Error = "Could not load departures."
+	} else {
+		data.Departures = resp.Departures
+	}
+	render(w, r, "station.html", data)
+}
+
+func showManifest(w http.ResponseWriter, r *http.Request) {
+	s := requireStaff(w, r)
+	if s == nil {
+		return
+	}
+	id, err := strconv.ParseUint(r.PathValue("departure_id"), 10, 64)
+	if err != nil || id == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	renderManifest(w, r, http.StatusOK, s, id, "")
+}
+
+func renderManifest(w http.ResponseWriter, r *http.Request, code int, s *session, id uint64, msg string) {
+	m, err := client.GetBoardingManifest(rpcContext(r, s), &pb.ManifestRequest{DepartureId: id})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	case codes.NotFound:
+		http.NotFound(w, r)
+		return
+	default:
+		ui.render(w, r, http.StatusBadGateway, "result.html", page{Session: s, Title: "Error", Error: status.Convert(err).Message()})
+		return
+	}
+	ui.render(w, r, code, "manifest.html", page{Session: s, Manifest: newManifest(m), Error: msg})
+}
+
+// handleStaffCheckIn and handleBoard act on one row of the manifest and
+// show the manifest again, with the server's reason if the action failed.
+func handleStaffCheckIn(w http.ResponseWriter, r *http.Request) {
+	s := requireStaff(w, r)
+	if s == nil {
+		return
+	}
+	f := newForm(r, "checkin")
+	dep := f.number("departure_id", "Departure", 1<<53)
+	tNo := f.number("ticket_no", "Ticket number", math.MaxInt32)
+	if !f.ok() {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	_, err := client.CheckIn(rpcContext(r, s), &pb.ReservationRequest{TicketNo: &tNo})
+	afterStaffAction(w, r, s, dep, err)
+}
+
+func handleBoard(w http.ResponseWriter, r *http.Request) {
+	s := requireStaff(w, r)
+	if s == nil {
+		return
+	}
+	f := newForm(r, "board")
+	dep := f.number("departure_id", "Departure", 1<<53)
+	tNo := f.number("ticket_no", "Ticket number", math.MaxInt32)
+	pos, err := strconv.ParseUint(f.value("position"), 10, 32)
+	if !f.ok() || err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	_, err = client.Board(rpcContext(r, s), &pb.BoardRequest{TicketNo: tNo, Position: uint32(pos)})
+	afterStaffAction(w, r, s, dep, err)
+}
+
+func afterStaffAction(w http.ResponseWriter, r *http.Request, s *session, dep uint64, err error) {
+	switch status.Code(err) {
+	case codes.OK:
+		http.Redirect(w, r, "/station/departures/"+strconv.FormatUint(dep, 10), http.StatusSeeOther)
+	case codes.Unavailable:
+		renderDegraded(w, r)
+	default:
+		renderManifest(w, r, http.StatusConflict, s, dep, status.Convert(err).Message())
+	}
+}
+
+// handleCheckIn checks in one of the signed-in customer's own tickets from
+// My bookings.
+func handleCheckIn(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	f := newForm(r, "checkin")
+	tNo := f.number("ticket_no", "Ticket number", math.MaxInt32)
+	if !f.ok() {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	_, err := client.CheckIn(rpcContext(r, s), &pb.ReservationRequest{TicketNo: &tNo})
+	switch status.Code(err) {
+	case codes.OK:
+		http.Redirect(w, r, "/bookings", http.StatusSeeOther)
+		return
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	}
+	renderBookings(w, r, http.StatusConflict, s, "Ticket "+strconv.FormatUint(tNo, 10)+": "+status.Convert(err).Message())
+}
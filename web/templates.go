@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+)
+
+// The templates and static files are compiled into the binary. Every page in
+// templates/ defines a "title" and a "content" block that layout.html wraps.
+//
+//go:embed templates static
+var assets embed.FS
+
+// page is the data passed to every template.
+type page struct {
+	Session *session
+	CSRF    string
+	Title   string
+	// Error is a message about the page as a whole; field errors are in Form.
+	Error      string
+	Form       formState
+	Tickets    []*pb.ReservationResponse
+	Ticket     *pb.ReservationResponse
+	RetryAfter int
+
+	// Booking wizard: the step being shown and the state so far.
+	Step       string
+	Booking    *booking
+	Departures []*pb.Departure
+	Sections   []seatSection
+	Fare       *pb.Fare
+	// SoldOut offers to join the waitlist instead.
+	SoldOut bool
+
+	// My bookings: waitlist entries still waiting or holding seats.
+	Waitlist []waitlistRow
+
+	// Station pages.
+	Manifest *manifest
+
+	// Admin webhook pages.
+	Webhooks   []*pb.Webhook
+	Deliveries []*pb.WebhookDelivery
+}
+
+// Value returns what the user typed into field of the named form, if that
+// form is being shown again.
+func (p page) Value(form, field string) string {
+	if p.Form.Name != form {
+		return ""
+	}
+	return p.Form.Values.Get(field)
+}
+
+// FieldError returns the validation message for field of the named form.
+func (p page) FieldError(form, field string) string {
+	if p.Form.Name != form {
+		return ""
+	}
+	return p.Form.Errors[field]
+}
+
+// views holds the parsed pages. In dev mode (WEB_DEV=true) templates and
+// static files are read from the web/ directory instead, and templates are
+// parsed again on every request so edits show up on reload.
+type views struct {
+	fsys  fs.FS
+	dev   bool
+	pages map[string]*template.Template
+}
+
+func newViews(logger *slog.Logger) (*views, error) {
+	v := &views{fsys: assets}
+	if getenv("WEB_DEV", "false") == "true" {
+		dir := getenv("WEB_DEV_DIR", "web")
+		logger.Warn("dev mode: serving templates and static files from disk", "dir", dir)
+		v.fsys, v.dev = os.DirFS(dir), true
+	}
+	pages, err := parsePages(v.fsys)
+	if err != nil {
+		return nil, err
+	}
+	v.pages = pages
+	return v, nil
+}
+
+// funcs are the helpers available to every template.
+var funcs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// parsePages parses each page of templates/ together with its own copy of
+// the layout.
+func parsePages(fsys fs.FS) (map[string]*template.Template, error) {
+	layout, err := template.New("layout.html").Funcs(funcs).ParseFS(fsys, "templates/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	files, err := fs.Glob(fsys, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	pages := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := path.Base(file)
+		if name == "layout.html" {
+			continue
+		}
+		t, err := template.Must(layout.Clone()).ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		pages[name] = t
+	}
+	return pages, nil
+}
+
+// static serves the files under static/.
+func (v *views) static() http.Handler {
+	sub, _ := fs.Sub(v.fsys, "static")
+	return http.StripPrefix("/static/", http.FileServer(http.FS(sub)))
+}
+
+// render executes the named page into a buffer first, so a template error
+// becomes a clean 500 instead of half a page.
+func (v *views) render(w http.ResponseWriter, r *http.Request, code int, name string, data page) {
+	pages := v.pages
+	if v.dev {
+		var err error
+		if pages, err = parsePages(v.fsys); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	t, ok := pages[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("template %s not found", name), http.StatusInternalServerError)
+		return
+	}
+	data.CSRF = csrfToken(r)
+
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout.html", data); err != nil {
+		slog.ErrorContext(r.Context(), "template failed", "template", name, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	buf.WriteTo(w)
+}
+
+func render(w http.ResponseWriter, r *http.Request, name string, data page) {
+	ui.render(w, r, http.StatusOK, name, data)
+}
+
+// renderForm shows file again with the problems of f, answering with code.
+func renderForm(w http.ResponseWriter, r *http.Request, file string, code int, sess *session, f *form) {
+	data := page{Session: sess, Form: f.page()}
+	if sess != nil {
+		data.Booking = loadBooking(r, sess)
+	}
+	ui.render(w, r, code, file, data)
+}
+
+// renderDegraded answers with 503 and a static page while the ticket service
+// is down, instead of an error message per request.
+func renderDegraded(w http.ResponseWriter, r *http.Request) {
+	wait := int(cb.RetryAfter().Seconds())
+	if wait == 0 {
+		wait = 5
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(wait))
+	ui.render(w, r, http.StatusServiceUnavailable, "degraded.html", page{Session: auth.get(r), RetryAfter: wait})
+}
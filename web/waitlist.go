@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// waitlistRow is a waitlist entry on My bookings. Promoted entries carry
+// the fare to pay when claiming the held seats.
+type waitlistRow struct {
+	*pb.WaitlistEntry
+	Fare *pb.Fare
+}
+
+// waitlistRows returns the account's entries that are waiting or holding
+// seats. Finished entries are left out; their tickets are listed anyway.
+func waitlistRows(r *http.Request, s *session) []waitlistRow {
+	resp, err := client.ListWaitlist(rpcContext(r, s), &pb.WaitlistQuery{})
+	if err != nil {
+		return nil
+	}
+	var rows []waitlistRow
+	for _, e := range resp.Entries {
+		switch e.Status {
+		case pb.WaitlistEntry_WAITING:
+			rows = append(rows, waitlistRow{WaitlistEntry: e})
+		case pb.WaitlistEntry_PROMOTED:
+			row := waitlistRow{WaitlistEntry: e}
+			if fare, err := client.QuoteFare(rpcContext(r, s), e.Request); err == nil {
+				row.Fare = fare
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// handleJoinWaitlist queues the wizard's booking when the departure sold
+// out. Seat choices are dropped; promoted entries get whatever is free.
+func handleJoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "review")
+	if s == nil {
+		return
+	}
+	req := b.request()
+	for _, p := range req.Passengers {
+		p.Section, p.Seat = "", 0
+	}
+	_, err := client.JoinWaitlist(rpcContext(r, s), &pb.JoinWaitlistRequest{Request: req})
+	switch status.Code(err) {
+	case codes.OK:
+		clearBooking(w)
+		http.Redirect(w, r, "/bookings", http.StatusSeeOther)
+	case codes.Unavailable:
+		renderDegraded(w, r)
+	default:
+		renderReview(w, r, http.StatusConflict, s, b, status.Convert(err).Message())
+	}
+}
+
+func handleClaimWaitlist(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	f := newForm(r, "claim")
+	id := f.number("waitlist_id", "Waitlist entry", 1<<53)
+	price := f.number("price_paid", "Fare", 1<<53)
+	currency := f.value("currency")
+	if !f.ok() {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	resp, err := client.ClaimWaitlist(rpcContext(r, s), &pb.ClaimWaitlistRequest{WaitlistId: id, PricePaid: price,
+		PaymentToken: f.value("payment_token")})
+	switch status.Code(err) {
+	case codes.OK:
+		render(w, r, "result.html", page{Session: s, Title: bookedTitle(resp), Ticket: resp, Fare: &pb.Fare{Total: resp.PricePaid, Currency: currency}})
+	case codes.Unavailable:
+		renderDegraded(w, r)
+	case codes.Aborted:
+		renderBookings(w, r, http.StatusPaymentRequired, s, status.Convert(err).Message()+". Please try another payment method.")
+	case codes.DeadlineExceeded:
+		renderBookings(w, r, http.StatusGatewayTimeout, s, "The payment did not go through and you have not been charged. Please try again.")
+	default:
+		renderBookings(w, r, http.StatusConflict, s, status.Convert(err).Message())
+	}
+}
+
+func handleLeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	f := newForm(r, "leave")
+	id := f.number("waitlist_id", "Waitlist entry", 1<<53)
+	if !f.ok() {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	_, err := client.LeaveWaitlist(rpcContext(r, s), &pb.WaitlistQuery{WaitlistId: id})
+	switch status.Code(err) {
+	case codes.OK:
+		http.Redirect(w, r, "/bookings", http.StatusSeeOther)
+	case codes.Unavailable:
+		renderDegraded(w, r)
+	default:
+		renderBookings(w, r, http.StatusConflict, s, status.Convert(err).Message())
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// adminContext is rpcContext for TicketAdmin calls, which also need the
+// admin token. They are not in the client's timeout table, so it sets its
+// own deadline.
+func adminContext(r *http.Request, s *session) (context.Context, context.CancelFunc) {
+	ctx := metadata.AppendToOutgoingContext(rpcContext(r, s), "authorization", "Bearer "+adminToken)
+	return context.WithTimeout(ctx, 5*time.Second)
+}
+
+// requireAdmin checks with the server that the signed-in account is still an
+// admin before a page acts with the web UI's ADMIN_TOKEN: the role in the
+// session cookie may be hours old and outlive a demotion.
+func requireAdmin(w http.ResponseWriter, r *http.Request, s *session) bool {
+	if !s.Admin() {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	acc, err := client.GetAccount(rpcContext(r, s), &pb.EmptyRequest{})
+	switch {
+	case status.Code(err) == codes.Unavailable:
+		renderDegraded(w, r)
+		return false
+	case err != nil || acc.Role != "admin":
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// handleWebhooks shows the partner webhook subscriptions and their delivery
+// log, optionally only the deliveries with one status, e.g. DEAD for the
+// dead letters.
+func handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	if !requireAdmin(w, r, s) {
+		return
+	}
+	renderWebhooks(w, r, http.StatusOK, s, "")
+}
+
+func renderWebhooks(w http.ResponseWriter, r *http.Request, code int, s *session, msg string) {
+	filter := r.URL.Query().Get("status")
+	data := page{Session: s, Title: filter, Error: msg}
+	if admin == nil {
+		data.Error = "Webhooks cannot be shown: ADMIN_TOKEN is not configured for the web UI."
+		ui.render(w, r, code, "webhooks.html", data)
+		return
+	}
+	ctx, cancel := adminContext(r, s)
+	defer cancel()
+
+	q := &pb.WebhookDeliveryQuery{Limit: 100}
+	if v, ok := pb.WebhookDelivery_Status_value[filter]; ok {
+		st := pb.WebhookDelivery_Status(v)
+		q.Status = &st
+	}
+	hooks, err := admin.ListWebhooks(ctx, &pb.EmptyRequest{})
+	var log *pb.WebhookDeliveryList
+	if err == nil {
+		log, err = admin.ListWebhookDeliveries(ctx, q)
+	}
+	switch status.Code(err) {
+	case codes.OK:
+		data.Webhooks, data.Deliveries = hooks.Webhooks, log.Deliveries
+	case codes.Unavailable:
+		renderDegraded(w, r)
+		return
+	default:
+		data.Error = "Could not load webhooks."
+	}
+	ui.render(w, r, code, "webhooks.html", data)
+}
+
+// handleRetryWebhook queues a delivery to be sent again and goes back to
+// the log it was retried from.
+func handleRetryWebhook(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	if admin == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if !requireAdmin(w, r, s) {
+		return
+	}
+	f := newForm(r, "retry")
+	id := f.number("delivery_id", "Delivery", 1<<53)
+	if !f.ok() {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := adminContext(r, s)
+	defer cancel()
+	_, err := admin.RetryWebhookDelivery(ctx, &pb.WebhookDeliveryQuery{DeliveryId: id})
+	switch status.Code(err) {
+	case codes.OK:
+		http.Redirect(w, r, "/admin/webhooks?status="+url.QueryEscape(f.value("status")), http.StatusSeeOther)
+	case codes.Unavailable:
+		renderDegraded(w, r)
+	default:
+		r.URL.RawQuery = url.Values{"status": {f.value("status")}}.Encode()
+		renderWebhooks(w, r, http.StatusConflict, s, status.Convert(err).Message())
+	}
+}
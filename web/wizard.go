@@ -0,0 +1,619 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/Akash-private/Cloudbees_code/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The booking wizard walks through these steps in order. Every step is a
+// GET page and a POST handler that saves the step and redirects to the
+// next one, so the browser's back button and the "Back" links always land
+// on a page showing what was entered before.
+var steps = []string{"search", "departure", "passengers", "seats", "review"}
+
+const (
+	bookingCookie = "booking"
+	bookingTTL    = time.Hour
+	// maxPassengers matches the limit of ReserveTicket.
+	maxPassengers = 9
+	// idempotencyMDKey makes a repeated confirmation return the ticket of
+	// the first one instead of booking again.
+	idempotencyMDKey = "idempotency-key"
+)
+
+// booking is the wizard state. It lives in a signed, compressed cookie, so
+// the JSON names are kept short and the text fields are length-limited to
+// stay under the browser's 4 KB cookie limit.
+type booking struct {
+	AccountID   uint64      `json:"u"`
+	Key         string      `json:"k"`
+	From        string      `json:"f"`
+	To          string      `json:"t"`
+	Date        string      `json:"d"`
+	Count       int         `json:"n"`
+	DepartureID uint64      `json:"dep,omitempty"`
+	Passengers  []traveller `json:"p,omitempty"`
+	// Seats are "SECTION-SEAT" in passenger order. Empty means the server
+	// assigns them; SeatsDone records that the seat step was passed.
+	Seats     []string `json:"s,omitempty"`
+	SeatsDone bool     `json:"sd,omitempty"`
+	// Prefer is what assigned seats should be, e.g. "window" or "quiet",
+	// for every passenger.
+	Prefer  []string `json:"pr,omitempty"`
+	Expires int64    `json:"exp"`
+}
+
+type traveller struct {
+	FirstName string `json:"f"`
+	LastName  string `json:"l"`
+	Email     string `json:"e,omitempty"`
+	Address   string `json:"a,omitempty"`
+}
+
+// step returns the first step that still needs input.
+func (b *booking) step() string {
+	switch {
+	case b.From == "":
+		return "search"
+	case b.DepartureID == 0:
+		return "departure"
+	case len(b.Passengers) != b.Count:
+		return "passengers"
+	case !b.SeatsDone:
+		return "seats"
+	}
+	return "review"
+}
+
+// stepLink is one entry of the progress bar above the wizard pages.
+type stepLink struct {
+	Label, Path string
+	Current     bool
+	// Reachable steps link back to their page.
+	Reachable bool
+}
+
+// Steps returns the progress bar for the wizard page being shown.
+func (p page) Steps() []stepLink {
+	labels := []string{"Journey", "Departure", "Passengers", "Seats", "Review"}
+	last := 0
+	if p.Booking != nil {
+		last = slices.Index(steps, p.Booking.step())
+	}
+	links := make([]stepLink, len(steps))
+	for i, step := range steps {
+		links[i] = stepLink{Label: labels[i], Path: "/book/" + step, Current: step == p.Step, Reachable: i <= last}
+	}
+	return links
+}
+
+// Passenger returns a field of passenger i as last submitted, or as stored
+// in the booking. The lead passenger defaults to the signed-in user.
+func (p page) Passenger(i int, field string) string {
+	if p.Form.Name == "passengers" {
+		return p.Form.Values.Get(field + "_" + strconv.Itoa(i))
+	}
+	if p.Booking != nil && i < len(p.Booking.Passengers) {
+		t := p.Booking.Passengers[i]
+		return map[string]string{"first_name": t.FirstName, "last_name": t.LastName, "email": t.Email, "address": t.Address}[field]
+	}
+	if i == 0 && p.Session != nil {
+		switch field {
+		case "first_name":
+			first, _, _ := strings.Cut(p.Session.Name, " ")
+			return first
+		case "last_name":
+			_, last, _ := strings.Cut(p.Session.Name, " ")
+			return last
+		case "email":
+			return p.Session.Email
+		}
+	}
+	return ""
+}
+
+// Today is the earliest date a journey can be searched for.
+func (p page) Today() string { return time.Now().UTC().Format(time.DateOnly) }
+
+// Money formats an amount in minor units, e.g. "GBP 45.00".
+func (p page) Money(amount uint64, currency string) string {
+	return fmt.Sprintf("%s %d.%02d", currency, amount/100, amount%100)
+}
+
+// Prefers reports whether the seat step has pref ticked, as last submitted
+// or as stored in the booking.
+func (p page) Prefers(pref string) bool {
+	if p.Form.Name == "seats" {
+		return slices.Contains(p.Form.Values["prefer"], pref)
+	}
+	return p.Booking != nil && slices.Contains(p.Booking.Prefer, pref)
+}
+
+// seatPreferences are the choices of the seat step's "prefer" field. Of
+// window and aisle, and of forward and backward, only one may be picked.
+var seatPreferences = []string{"window", "aisle", "forward", "backward", "quiet", "exit"}
+
+// preferences turns the booking's seat preferences into their request form.
+func (b *booking) preferences() *pb.SeatPreferences {
+	if len(b.Prefer) == 0 {
+		return nil
+	}
+	pref := &pb.SeatPreferences{}
+	for _, v := range b.Prefer {
+		switch v {
+		case "window":
+			pref.Position = pb.SeatPreferences_WINDOW
+		case "aisle":
+			pref.Position = pb.SeatPreferences_AISLE
+		case "forward":
+			pref.Facing = pb.SeatPreferences_FORWARD
+		case "backward":
+			pref.Facing = pb.SeatPreferences_BACKWARD
+		case "quiet":
+			pref.QuietCoach = true
+		case "exit":
+			pref.NearExit = true
+		}
+	}
+	return pref
+}
+
+// request builds the ReserveTicket/QuoteFare request for the booking.
+func (b *booking) request() *pb.ReservationRequest {
+	req := &pb.ReservationRequest{DepartureId: b.DepartureID, FromCode: b.From, ToCode: b.To, PassengerCount: uint64(len(b.Passengers))}
+	for i, t := range b.Passengers {
+		p := &pb.UserDetails{FirstName: t.FirstName, LastName: t.LastName, Email: t.Email, Address: t.Address}
+		if i < len(b.Seats) {
+			p.Section, p.Seat = splitSeat(b.Seats[i])
+		} else {
+			p.Preferences = b.preferences()
+		}
+		req.Passengers = append(req.Passengers, p)
+	}
+	return req
+}
+
+var seatPattern = regexp.MustCompile(`^[A-Z0-9]{1,8}-[0-9]{1,3}$`)
+
+func splitSeat(id string) (string, uint32) {
+	section, seat, _ := strings.Cut(id, "-")
+	n, _ := strconv.ParseUint(seat, 10, 32)
+	return section, uint32(n)
+}
+
+// loadBooking returns the wizard state of the signed-in user, or a fresh
+// one if there is none, it is invalid or it belongs to someone else.
+func loadBooking(r *http.Request, s *session) *booking {
+	fresh := &booking{AccountID: s.AccountID, Count: 1}
+	c, err := r.Cookie(bookingCookie)
+	if err != nil {
+		return fresh
+	}
+	payload, sig, ok := strings.Cut(c.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(auth.sign("booking:"+payload))) {
+		return fresh
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return fresh
+	}
+	b, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(raw)), 16<<10))
+	if err != nil {
+		return fresh
+	}
+	var bk booking
+	if json.Unmarshal(b, &bk) != nil || bk.AccountID != s.AccountID || time.Now().Unix() > bk.Expires {
+		return fresh
+	}
+	return &bk
+}
+
+func saveBooking(w http.ResponseWriter, b *booking) error {
+	if b.Key == "" {
+		k := make([]byte, 16)
+		rand.Read(k)
+		b.Key = base64.RawURLEncoding.EncodeToString(k)
+	}
+	b.Expires = time.Now().Add(bookingTTL).Unix()
+	raw, _ := json.Marshal(b)
+	var buf bytes.Buffer
+	zw, _ := flate.NewWriter(&buf, flate.BestCompression)
+	zw.Write(raw)
+	zw.Close()
+	payload := base64.RawURLEncoding.EncodeToString(buf.Bytes())
+	value := payload + "." + auth.sign("booking:"+payload)
+	if len(value) > 4000 {
+		return fmt.Errorf("booking details are too long")
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     bookingCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(bookingTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   auth.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+func clearBooking(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     bookingCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   auth.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// wizardStep checks that the user is signed in and may see step. Steps
+// after the first incomplete one redirect back to it.
+func wizardStep(w http.ResponseWriter, r *http.Request, step string) (*session, *booking) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return nil, nil
+	}
+	b := loadBooking(r, s)
+	if slices.Index(steps, step) > slices.Index(steps, b.step()) {
+		http.Redirect(w, r, "/book/"+b.step(), http.StatusSeeOther)
+		return nil, nil
+	}
+	return s, b
+}
+
+// next stores b and moves on to the following step.
+// Only the passengers step can realistically overflow the cookie.
+func next(w http.ResponseWriter, r *http.Request, s *session, b *booking, f *form, file, step string) {
+	if err := saveBooking(w, b); err != nil {
+		ui.render(w, r, http.StatusBadRequest, file, page{Session: s, Step: step, Booking: b, Form: f.page(), Error: err.Error()})
+		return
+	}
+	http.Redirect(w, r, "/book/"+steps[slices.Index(steps, step)+1], http.StatusSeeOther)
+}
+
+// handleResume continues the booking where the user left it.
+func handleResume(w http.ResponseWriter, r *http.Request) {
+	s := requireSignIn(w, r)
+	if s == nil {
+		return
+	}
+	http.Redirect(w, r, "/book/"+loadBooking(r, s).step(), http.StatusSeeOther)
+}
+
+func showSearch(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "search")
+	if s == nil {
+		return
+	}
+	render(w, r, "book_search.html", page{Session: s, Step: "search", Booking: b})
+}
+
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "search")
+	if s == nil {
+		return
+	}
+	f := newForm(r, "search")
+	from := f.required("from", "From")
+	to := f.required("to", "To")
+	date := f.required("date", "Date")
+	if date != "" {
+		if d, err := time.Parse(time.DateOnly, date); err != nil {
+			f.fail("date", "Enter a date as YYYY-MM-DD.")
+		} else if d.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+			f.fail("date", "Pick today or a later date.")
+		}
+	}
+	count := f.number("passengers", "Passengers", maxPassengers)
+	if !f.ok() {
+		ui.render(w, r, http.StatusBadRequest, "book_search.html", page{Session: s, Step: "search", Booking: b, Form: f.page()})
+		return
+	}
+
+	// A different journey invalidates the departure and the seats on it;
+	// passengers already entered are kept.
+	if from != b.From || to != b.To || date != b.Date {
+		b.DepartureID, b.Seats, b.SeatsDone = 0, nil, false
+	}
+	b.From, b.To, b.Date = from, to, date
+	b.Count = int(count)
+	if len(b.Passengers) > b.Count {
+		b.Passengers = b.Passengers[:b.Count]
+	}
+	if len(b.Seats) != 0 && len(b.Seats) != b.Count {
+		b.Seats, b.SeatsDone = nil, false
+	}
+	next(w, r, s, b, f, "book_search.html", "search")
+}
+
+// listDepartures returns the departures matching the booking's search.
+func listDepartures(r *http.Request, s *session, b *booking) ([]*pb.Departure, error) {
+	resp, err := client.ListDepartures(rpcContext(r, s), &pb.DeparturesRequest{FromCode: b.From, ToCode: b.To, Date: b.Date})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Departures, nil
+}
+
+func showDeparture(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "departure")
+	if s == nil {
+		return
+	}
+	renderDepartures(w, r, http.StatusOK, s, b, nil, "")
+}
+
+func renderDepartures(w http.ResponseWriter, r *http.Request, code int, s *session, b *booking, f *form, msg string) {
+	deps, err := listDepartures(r, s, b)
+	if status.Code(err) == codes.Unavailable {
+		renderDegraded(w, r)
+		return
+	}
+	data := page{Session: s, Step: "departure", Booking: b, Departures: deps, Error: msg}
+	if err != nil {
+		data.Error = status.Convert(err).Message()
+	}
+	if f != nil {
+		data.Form = f.page()
+	}
+	ui.render(w, r, code, "book_departure.html", data)
+}
+
+func handleDeparture(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "departure")
+	if s == nil {
+		return
+	}
+	f := newForm(r, "departure")
+	id := f.number("departure_id", "Departure", 1<<53)
+	if f.ok() {
+		deps, err := listDepartures(r, s, b)
+		if status.Code(err) == codes.Unavailable {
+			renderDegraded(w, r)
+			return
+		}
+		if !slices.ContainsFunc(deps, func(d *pb.Departure) bool { return d.DepartureId == id }) {
+			f.fail("departure_id", "Pick one of the departures listed.")
+		}
+	}
+	if !f.ok() {
+		renderDepartures(w, r, http.StatusBadRequest, s, b, f, "")
+		return
+	}
+
+	if id != b.DepartureID {
+		b.Seats, b.SeatsDone = nil, false
+	}
+	b.DepartureID = id
+	next(w, r, s, b, f, "book_departure.html", "departure")
+}
+
+func showPassengers(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "passengers")
+	if s == nil {
+		return
+	}
+	render(w, r, "book_passengers.html", page{Session: s, Step: "passengers", Booking: b})
+}
+
+func handlePassengers(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "passengers")
+	if s == nil {
+		return
+	}
+	f := newForm(r, "passengers")
+	var list []traveller
+	for i := range b.Count {
+		n := strconv.Itoa(i)
+		t := traveller{
+			FirstName: f.required("first_name_"+n, "First name"),
+			LastName:  f.required("last_name_"+n, "Last name"),
+			Address:   f.value("address_" + n),
+		}
+		// Only the lead passenger must give an email; it is where the
+		// tickets are sent.
+		if i == 0 || f.value("email_"+n) != "" {
+			t.Email = f.email("email_" + n)
+		}
+		f.maxLen("first_name_"+n, "First name", 40)
+		f.maxLen("last_name_"+n, "Last name", 40)
+		f.maxLen("email_"+n, "Email", 80)
+		f.maxLen("address_"+n, "Address", 120)
+		list = append(list, t)
+	}
+	if !f.ok() {
+		ui.render(w, r, http.StatusBadRequest, "book_passengers.html", page{Session: s, Step: "passengers", Booking: b, Form: f.page()})
+		return
+	}
+	b.Passengers = list
+	next(w, r, s, b, f, "book_passengers.html", "passengers")
+}
+
+// seatSection is one section of the seat map as shown in the seat step.
+type seatSection struct {
+	Name  string
+	Class string
+	Quiet bool
+	Seats []seatChoice
+}
+
+type seatChoice struct {
+	ID      string
+	Seat    uint32
+	Free    bool
+	Checked bool
+}
+
+func showSeats(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "seats")
+	if s == nil {
+		return
+	}
+	renderSeats(w, r, http.StatusOK, s, b, nil, "")
+}
+
+func renderSeats(w http.ResponseWriter, r *http.Request, code int, s *session, b *booking, f *form, msg string) {
+	m, err := client.GetSeatMap(rpcContext(r, s), &pb.SeatMapRequest{DepartureId: b.DepartureID, FromCode: b.From, ToCode: b.To})
+	if status.Code(err) == codes.Unavailable {
+		renderDegraded(w, r)
+		return
+	}
+	data := page{Session: s, Step: "seats", Booking: b, Error: msg}
+	if err != nil {
+		data.Error = status.Convert(err).Message()
+	} else {
+		for _, seat := range m.Seats {
+			if len(data.Sections) == 0 || data.Sections[len(data.Sections)-1].Name != seat.Section {
+				data.Sections = append(data.Sections, seatSection{Name: seat.Section, Class: seat.CoachClass, Quiet: seat.QuietCoach})
+			}
+			id := fmt.Sprintf("%s-%d", seat.Section, seat.Seat)
+			sec := &data.Sections[len(data.Sections)-1]
+			sec.Seats = append(sec.Seats, seatChoice{ID: id, Seat: seat.Seat,
+				Free: seat.State == pb.SeatMap_FREE, Checked: slices.Contains(b.Seats, id)})
+		}
+	}
+	if f != nil {
+		data.Form = f.page()
+	}
+	ui.render(w, r, code, "book_seats.html", data)
+}
+
+func handleSeats(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "seats")
+	if s == nil {
+		return
+	}
+	f := newForm(r, "seats")
+	chosen := f.values["seat"]
+	for _, id := range chosen {
+		if !seatPattern.MatchString(id) {
+			f.fail("seat", "Pick seats from the seat map.")
+		}
+	}
+	if len(chosen) != 0 && len(chosen) != b.Count {
+		f.fail("seat", fmt.Sprintf("Pick %d seats, or none to have them assigned.", b.Count))
+	}
+	prefer := f.values["prefer"]
+	for _, v := range prefer {
+		if !slices.Contains(seatPreferences, v) {
+			f.fail("prefer", "Pick preferences from the list.")
+		}
+	}
+	if slices.Contains(prefer, "window") && slices.Contains(prefer, "aisle") ||
+		slices.Contains(prefer, "forward") && slices.Contains(prefer, "backward") {
+		f.fail("prefer", "Pick window or aisle, and forward or backward facing, not both.")
+	}
+	if !f.ok() {
+		renderSeats(w, r, http.StatusBadRequest, s, b, f, "")
+		return
+	}
+	b.Seats, b.SeatsDone, b.Prefer = chosen, true, prefer
+	next(w, r, s, b, f, "book_seats.html", "seats")
+}
+
+func showReview(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "review")
+	if s == nil {
+		return
+	}
+	renderReview(w, r, http.StatusOK, s, b, "")
+}
+
+// renderReview prices the booking with QuoteFare; the total goes into the
+// confirm form so the booking fails if the fare changes in the meantime.
+func renderReview(w http.ResponseWriter, r *http.Request, code int, s *session, b *booking, msg string) {
+	ctx := rpcContext(r, s)
+	fare, err := client.QuoteFare(ctx, b.request())
+	if status.Code(err) == codes.Unavailable {
+		renderDegraded(w, r)
+		return
+	}
+	data := page{Session: s, Step: "review", Booking: b, Fare: fare, Error: msg}
+	if err != nil {
+		data.Error = status.Convert(err).Message()
+	}
+	deps, err := listDepartures(r, s, b)
+	if err == nil {
+		for _, d := range deps {
+			if d.DepartureId == b.DepartureID {
+				data.Departures = []*pb.Departure{d}
+			}
+		}
+	}
+	ui.render(w, r, code, "book_review.html", data)
+}
+
+// bookedTitle heads the result page of a booking, whose payment may still
+// be pending.
+func bookedTitle(t *pb.ReservationResponse) string {
+	if t.Status == "PaymentPending" {
+		return "Payment Pending"
+	}
+	return "Booking Confirmed"
+}
+
+func handleConfirm(w http.ResponseWriter, r *http.Request) {
+	s, b := wizardStep(w, r, "review")
+	if s == nil {
+		return
+	}
+	f := newForm(r, "review")
+	price := f.number("price_paid", "Fare", 1<<53)
+	currency := f.value("currency")
+	if !f.ok() {
+		renderReview(w, r, http.StatusBadRequest, s, b, "The fare is missing, please review the booking again.")
+		return
+	}
+
+	req := b.request()
+	req.PricePaid = price
+	req.PaymentToken = f.value("payment_token")
+	ctx := metadata.AppendToOutgoingContext(rpcContext(r, s), idempotencyMDKey, b.Key)
+	resp, err := client.ReserveTicket(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		clearBooking(w)
+		render(w, r, "result.html", page{Session: s, Title: bookedTitle(resp), Ticket: resp, Fare: &pb.Fare{Total: resp.PricePaid, Currency: currency}})
+	case codes.Unavailable:
+		renderDegraded(w, r)
+	case codes.Aborted:
+		renderReview(w, r, http.StatusPaymentRequired, s, b, status.Convert(err).Message()+". Please try another payment method.")
+	case codes.DeadlineExceeded:
+		renderReview(w, r, http.StatusGatewayTimeout, s, b, "The payment did not go through and you have not been charged. Please try again.")
+	case codes.ResourceExhausted:
+		ui.render(w, r, http.StatusConflict, "book_review.html", page{Session: s, Step: "review", Booking: b, SoldOut: true,
+			Error: status.Convert(err).Message() + "."})
+	case codes.FailedPrecondition:
+		// Either the fare changed or a chosen seat was taken. A fresh
+		// quote tells which.
+		if fare, qerr := client.QuoteFare(rpcContext(r, s), b.request()); qerr == nil && fare.Total != price {
+			renderReview(w, r, http.StatusConflict, s, b, "The fare has changed. Please check the new total and confirm again.")
+			return
+		}
+		renderSeats(w, r, http.StatusConflict, s, b, nil, status.Convert(err).Message()+". Please choose other seats.")
+	case codes.NotFound:
+		renderDepartures(w, r, http.StatusConflict, s, b, nil, "That departure can no longer be booked. Please pick another one.")
+	default:
+		renderReview(w, r, http.StatusBadGateway, s, b, status.Convert(err).Message())
+	}
+}